@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/hashing"
+	"windows_service_module/pkg/metrics"
+	"windows_service_module/pkg/sink"
+	"windows_service_module/pkg/winsvc"
+)
+
+var (
+	config         *ServiceConfig
+	agentInstance  *agent.Agent
+	serviceManager *winsvc.ServiceManager
+	logger         *winsvc.Logger
+	serviceMetrics *metrics.Metrics
+	eventStore     *eventstore.Store
+	eventWriter    *eventstore.Writer
+	hashPool       *hashing.Pool
+
+	// runAsService는 서비스 관리자(Windows SCM, systemd)가 실행한 경우 true입니다 (debug 실행은 false)
+	runAsService bool
+)
+
+// configMu는 설정을 다시 로드할 때 config 교체를 보호합니다.
+// config는 서비스 루프에서만 교체하므로(setConfig) 서비스 루프에서는 그대로 읽고,
+// 다른 고루틴은 currentConfig로 읽거나 시작할 때 받은 설정 복사본을 사용합니다.
+var configMu sync.RWMutex
+
+// currentConfig는 현재 적용된 설정을 반환합니다 (서비스 루프 밖의 고루틴용)
+func currentConfig() *ServiceConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return config
+}
+
+// setConfig는 적용한 설정으로 config를 교체합니다
+func setConfig(cfg *ServiceConfig) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = cfg
+}
+
+const configFileName = "service_config.json"
+
+// configPath는 로드한 설정 파일의 절대 경로입니다
+var configPath string
+
+// defaultConfigPath는 실행 파일과 같은 디렉토리의 설정 파일 경로를 반환합니다
+func defaultConfigPath() string {
+	// 실행파일이 있는 경로만 추출하기
+	execDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		log.Fatalf("실행 파일 경로를 가져올 수 없습니다: %v", err)
+	}
+	return filepath.Join(execDir, configFileName)
+}
+
+// setup은 설정을 로드하고 로거와 서비스 관리자를 초기화합니다
+func setup() {
+	// 설정 로드
+	configPath = defaultConfigPath()
+	EnsureDefaultConfig(configPath)
+
+	var err error
+	config, err = LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("설정을 로드할 수 없습니다: %v", err)
+	}
+
+	// 로깅 초기화
+	logger = winsvc.NewLogger(config.LogPath, false)
+	applyLogSettings(config)
+
+	// 서비스 관리자 초기화
+	svcConfig := &winsvc.ServiceConfig{
+		ServiceName:        config.ServiceName,
+		ServiceDescription: config.ServiceDescription,
+		RestartOnFailure:   config.RestartOnFailure,
+		RestartDelay:       config.RestartDelay,
+		MaxRestartAttempts: config.MaxRestartAttempts,
+	}
+	serviceManager = winsvc.NewServiceManager(svcConfig)
+}
+
+// validateMonitoringPaths는 서비스를 실행하기 전에 감시 경로가 있는 디렉토리인지 확인합니다 (run, debug, Windows 서비스)
+func validateMonitoringPaths() {
+	if err := config.ValidatePaths(); err != nil {
+		log.Fatalf("설정을 로드할 수 없습니다: %v", err)
+	}
+}
+
+func initializeDirectories() error {
+	if err := resolvePaths(config); err != nil {
+		return err
+	}
+
+	// 디버그 로그
+	log.Printf("로그 경로: %s", config.LogPath)
+	log.Printf("DB 경로: %s", config.DatabasePath)
+	log.Printf("데이터 경로: %s", config.CustomDataPath)
+
+	dirs := []string{
+		config.LogPath,
+		filepath.Dir(config.DatabasePath),
+		config.CustomDataPath,
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("디렉토리 생성 실패 %s: %v", dir, err)
+		}
+		log.Printf("디렉토리 생성됨: %s", dir)
+	}
+
+	return nil
+}
+
+// resolvePaths는 설정의 상대 경로를 실행 파일 기준 절대 경로로 변환합니다
+func resolvePaths(cfg *ServiceConfig) error {
+	// 절대 경로로 변환
+	execDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		return fmt.Errorf("실행 파일 경로를 가져올 수 없습니다: %v", err)
+	}
+
+	resolve := func(path string) string {
+		// 설정의 상대 경로가 이미 절대 경로인지 확인
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(execDir, path)
+		}
+		return filepath.Clean(path)
+	}
+
+	// 설정 업데이트
+	cfg.LogPath = resolve(cfg.LogPath)
+	cfg.DatabasePath = resolve(cfg.DatabasePath)
+	cfg.CustomDataPath = resolve(cfg.CustomDataPath)
+	if cfg.HashLists.Allowlist != "" {
+		cfg.HashLists.Allowlist = resolve(cfg.HashLists.Allowlist)
+	}
+	if cfg.HashLists.Blocklist != "" {
+		cfg.HashLists.Blocklist = resolve(cfg.HashLists.Blocklist)
+	}
+	for i := range cfg.Sinks {
+		if cfg.Sinks[i].Type == sink.TypeFile && cfg.Sinks[i].Path != "" {
+			cfg.Sinks[i].Path = resolve(cfg.Sinks[i].Path)
+		}
+	}
+	return nil
+}
+
+// applyLogSettings는 설정의 로그 수준, 형식, 회전 설정을 로거에 적용합니다 (검증된 설정 기준).
+// 회전 설정은 다음 InitializeFileLogger 호출부터 적용됩니다.
+func applyLogSettings(cfg *ServiceConfig) {
+	level, _ := winsvc.ParseLevel(cfg.LogLevel)
+	format, _ := winsvc.ParseFormat(cfg.LogFormat)
+	logger.SetLevel(level)
+	logger.SetFormat(format)
+	logger.SetRotation(cfg.LogRotation)
+}
+
+// startAsyncLogging은 설정에 따라 비동기 로그 기록을 시작합니다.
+// 시작하지 못하면 동기 기록을 계속 사용합니다.
+func startAsyncLogging() {
+	if !config.LogAsync.Enabled {
+		return
+	}
+	if err := logger.StartAsync(config.LogAsync); err != nil {
+		logger.Log(winsvc.LogWarning, "비동기 로그 기록을 시작할 수 없습니다 (동기 기록 사용): %v", err)
+	}
+}
+
+// newAgent는 현재 설정으로 파일 모니터링 파이프라인을 생성합니다
+func newAgent() (*agent.Agent, error) {
+	// 이벤트 저장소 초기화 (스키마 마이그레이션 포함)
+	logger.Log(winsvc.LogInfo, "데이터베이스 경로 설정: %s", config.DatabasePath)
+	var err error
+	if eventStore, err = eventstore.Open(config.DatabasePath); err != nil {
+		return nil, fmt.Errorf("이벤트 데이터베이스 초기화 실패: %v", err)
+	}
+	if version, err := eventStore.SchemaVersion(); err == nil {
+		logger.Log(winsvc.LogInfo, "데이터베이스 스키마 버전: %d", version)
+	}
+
+	// IO 모니터링 초기화
+	source := agent.NewMonitorSource()
+	logger.Log(winsvc.LogInfo, "IO 모니터링이 초기화되었습니다.")
+
+	// 메트릭 초기화
+	serviceMetrics = metrics.New()
+	serviceMetrics.RegisterLogger(logger)
+
+	// 이벤트 저장 고루틴 시작
+	eventWriter = eventstore.NewWriter(eventStore, eventstore.DefaultQueueSize)
+	eventWriter.OnError = func(err error) {
+		logger.Log(winsvc.LogError, "이벤트 저장 실패 (다음 주기에 다시 시도): %v", err)
+	}
+	eventWriter.ObserveInsert = serviceMetrics.ObserveDBInsert
+	eventWriter.Start()
+	serviceMetrics.RegisterDroppedEvents(func() uint64 {
+		return source.Dropped() + eventWriter.Dropped()
+	})
+
+	// 외부 이벤트 싱크 시작
+	if sinkDispatcher, err = startSinks(config); err != nil {
+		return nil, err
+	}
+
+	// 실행 파일 해시 작업자 시작
+	if config.Hashing.Enabled {
+		hashPool = hashing.NewPool(config.Hashing)
+	}
+	initHashLists()
+	initRules()
+	if err := initQuarantine(config); err != nil {
+		return nil, err
+	}
+
+	agentConfig, err := newAgentConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return agent.New(agentConfig, source, logger), nil
+}
+
+// newAgentConfig는 서비스 설정에서 파이프라인 설정을 만듭니다
+func newAgentConfig(cfg *ServiceConfig) (agent.Config, error) {
+	fileFilter, err := filter.New(cfg.FileFilters)
+	if err != nil {
+		return agent.Config{}, fmt.Errorf("file_filters 설정 오류: %v", err)
+	}
+
+	agentConfig := agent.Config{
+		ServiceName:       cfg.ServiceName,
+		MonitoringPath:    cfg.MonitoringPath,
+		Filter:            fileFilter,
+		HeartbeatInterval: 10 * time.Second,
+		Metrics:           serviceMetrics,
+		Recorder:          newRecorder(cfg),
+		Hasher:            hashPool,
+		HashLists:         hashLists,
+		Coalesce:          cfg.EventCoalescing,
+		Rules:             rulesEngine,
+	}
+	if quarantineStore != nil {
+		agentConfig.Quarantiner = quarantineStore
+	}
+	return agentConfig, nil
+}
+
+// newRecorder는 처리한 이벤트를 이벤트 저장소와 외부 싱크에 기록하는 EventRecorder를 만듭니다
+func newRecorder(cfg *ServiceConfig) agent.EventRecorder {
+	recorder := agent.Recorders{storeRecorder{store: eventStore, writer: eventWriter}}
+	if sinkDispatcher != nil {
+		recorder = append(recorder, newSinkRecorder(sinkDispatcher, cfg.ServiceName))
+	}
+	return recorder
+}
+
+// storeRecorder는 처리한 이벤트를 이벤트 저장소에 기록합니다 (agent.EventRecorder 구현)
+type storeRecorder struct {
+	store  *eventstore.Store
+	writer *eventstore.Writer
+}
+
+func (r storeRecorder) Record(event agent.ProcessedEvent) {
+	r.writer.Add(eventstore.Record{
+		Timestamp:     event.Timestamp,
+		Path:          event.Path,
+		Operation:     event.Operation,
+		FileType:      event.FileType,
+		SHA256:        event.SHA256,
+		Count:         event.Count,
+		LastTimestamp: event.LastTimestamp,
+
+		OfflineDetected: event.OfflineDetected,
+	})
+
+	// 경고는 드물게 발생하므로 바로 저장 (규칙의 INFO, DEBUG 수준 log 동작은 로그에만 남김)
+	var alerts []eventstore.Alert
+	for _, a := range event.Alerts {
+		if a.Level < winsvc.LogWarning {
+			continue
+		}
+		alerts = append(alerts, eventstore.Alert{
+			Timestamp: event.Timestamp,
+			Level:     a.Level.String(),
+			Source:    a.Source,
+			Label:     a.Label,
+			Message:   a.Message,
+			Path:      event.Path,
+			Operation: event.Operation,
+			SHA256:    event.SHA256,
+		})
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	if err := r.store.AddAlerts(alerts); err != nil {
+		logger.Log(winsvc.LogError, "%v", err)
+	}
+}
+
+// closeEventPipeline은 진행 중인 해시 계산과 정리 작업을 마치고, 대기 중인 이벤트를 저장한 뒤 데이터베이스를 닫습니다.
+// newAgent가 실패한 경우에도 호출할 수 있으며, 이벤트 루프(agent.Run)가 끝난 뒤에 호출해야 합니다.
+func closeEventPipeline() {
+	maintenance.wait()
+	baselineCheck.stop()
+	catchUp.stop()
+	if hashPool != nil {
+		// 해시 작업자가 결과를 eventWriter와 eventStore, 싱크에 기록하므로 먼저 종료
+		hashPool.Close()
+		hashPool = nil
+	}
+	if sinkDispatcher != nil {
+		sinkDispatcher.Close(sink.DefaultCloseTimeout)
+		sinkDispatcher = nil
+	}
+	if eventWriter != nil {
+		eventWriter.Close()
+		eventWriter = nil
+	}
+	if eventStore != nil {
+		eventStore.Close()
+		eventStore = nil
+	}
+}
+
+// serviceControl은 플랫폼별 서비스 관리자(Windows SCM, systemd 시그널)의 요청을 서비스 루프에 전달하는 값입니다
+type serviceControl int
+
+const (
+	controlStop   serviceControl = iota + 1 // 서비스 중지 (SCM Stop/Shutdown, SIGTERM/SIGINT)
+	controlReload                           // 설정 다시 로드 (SCM ParamChange, SIGHUP)
+)
+
+// sendUntil은 v를 ch에 보내거나 done이 닫힐 때까지 기다립니다. 보냈으면 true를 반환합니다.
+// 서비스 루프가 끝난 뒤에는 제어 요청을 받지 않으므로 플랫폼별 변환 고루틴이 멈추지 않게 합니다.
+func sendUntil[T any](ch chan<- T, v T, done <-chan struct{}) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// runService는 시작 단계를 실행한 뒤 controls로 중지 요청을 받을 때까지 서비스 루프를 실행합니다.
+// report는 시작 진행 상황을, status는 Running(시작 완료)과 StopPending(중지 시작) 상태 변경을 받습니다 (nil이면 보고하지 않음).
+// 시작 단계가 실패하면 *winsvc.StartupError를, 모니터링이 중단되면 오류를 반환합니다.
+// 반환한 오류를 로그에 남길 수 있도록 로거는 호출한 쪽에서 닫습니다.
+func runService(controls <-chan serviceControl, report func(winsvc.StartupStatus), status func(winsvc.ServiceState)) error {
+	if status == nil {
+		status = func(winsvc.ServiceState) {}
+	}
+
+	// 디렉토리, 로그, 파이프라인, 모니터링을 차례로 시작하며 단계마다 진행 상황을 보고
+	// newAgent와 Start는 ctx를 따르지 않으므로, 제한 시간을 넘긴 단계가 끝난 뒤에 정리
+	startup := newStartup(report)
+	defer func() {
+		if !startup.Wait(0) {
+			logger.Log(winsvc.LogError, "제한 시간을 넘긴 시작 단계가 끝나지 않아 이벤트 파이프라인을 정리하지 않고 종료합니다")
+			return
+		}
+		closeEventPipeline()
+	}()
+	if err := startup.Run(context.Background()); err != nil {
+		return err
+	}
+
+	status(winsvc.StateRunning)
+	logger.Log(winsvc.LogInfo, "서비스 '%s'가 시작되었습니다.", config.ServiceName)
+
+	// 재시작 횟수는 서비스 관리자가 서비스를 시작한 횟수로 집계 (debug 실행과 설정 다시 로드는 제외)
+	restarts, err := recordServiceStart(runAsService)
+	if err != nil {
+		logger.Log(winsvc.LogWarning, "서비스 시작 횟수를 기록할 수 없습니다: %v", err)
+	}
+	serviceMetrics.RegisterRestarts(restarts)
+
+	// 상태 HTTP 서버 (status_address가 설정된 경우)
+	statusServer := startStatusServer()
+	defer stopStatusServer(statusServer)
+
+	// 이벤트 루프는 별도 고루틴에서 실행하고 여기서는 제어 요청만 처리
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- agentInstance.Run(ctx)
+	}()
+
+	// 서비스가 중지된 동안 변경된 파일 검사 (실시간 감시를 시작한 뒤 별도 고루틴에서 실행)
+	catchUp.start(ctx, agentInstance, config)
+
+	// 설정 파일 변경 감시
+	reloadRequests := make(chan struct{}, 1)
+	if err := watchConfigFile(ctx, configPath, reloadRequests); err != nil {
+		logger.Log(winsvc.LogWarning, "설정 파일 자동 반영이 비활성화됩니다: %v", err)
+	}
+
+	// 해시 목록 파일 변경 감시
+	hashListRequests := make(chan struct{}, 1)
+	if paths := hashLists.Paths(); len(paths) > 0 {
+		if err := watchFiles(ctx, paths, hashListRequests); err != nil {
+			logger.Log(winsvc.LogWarning, "해시 목록 자동 반영이 비활성화됩니다: %v", err)
+		}
+	}
+
+	// 규칙 파일 변경 감시
+	rulesRequests := make(chan struct{}, 1)
+	if err := watchFiles(ctx, []string{rulesEngine.Path()}, rulesRequests); err != nil {
+		logger.Log(winsvc.LogWarning, "규칙 자동 반영이 비활성화됩니다: %v", err)
+	}
+
+	// 이벤트 보관 기간 정리, WAL 체크포인트, VACUUM
+	maintenanceTicker := time.NewTicker(maintenanceTickInterval)
+	defer maintenanceTicker.Stop()
+
+	// 시작 시 기준선 비교 (이후 maintenanceTicker마다 주기 확인)
+	baselineCheck.tick(time.Now(), eventStore, config)
+
+	var monitorErr error
+loop:
+	for {
+		select {
+		case now := <-maintenanceTicker.C:
+			maintenance.tick(now, eventStore, config.Retention, config.CustomDataPath)
+			baselineCheck.tick(now, eventStore, config)
+			catchUp.recordLastSeen(now)
+		case <-reloadRequests:
+			logger.Log(winsvc.LogInfo, "설정 파일 변경이 감지되었습니다: %s", configPath)
+			reloadConfig()
+		case <-hashListRequests:
+			reloadHashLists()
+		case <-rulesRequests:
+			reloadRules()
+		case err := <-runErr:
+			monitorErr = fmt.Errorf("모니터링이 중단되었습니다: %v", err)
+			break loop
+		case c := <-controls:
+			switch c {
+			case controlReload:
+				logger.Log(winsvc.LogInfo, "설정 다시 로드 요청을 받았습니다.")
+				reloadConfig()
+			case controlStop:
+				logger.Log(winsvc.LogInfo, "서비스 '%s'가 중지 요청을 받았습니다.", config.ServiceName)
+				break loop
+			}
+		}
+	}
+
+	status(winsvc.StateStopPending)
+
+	// 정리 작업 수행
+	cancel()
+	if monitorErr == nil {
+		<-runErr
+	}
+	catchUp.stop()
+	catchUp.recordLastSeen(time.Now())
+
+	if monitorErr != nil {
+		return monitorErr
+	}
+	logger.Log(winsvc.LogInfo, "서비스 '%s'가 종료되었습니다.", config.ServiceName)
+	return nil
+}
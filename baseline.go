@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"windows_service_module/pkg/baseline"
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/winsvc"
+)
+
+// scanMonitoredPaths는 감시 경로에서 file_filters와 일치하는 파일을 훑습니다.
+// known이 있으면 크기와 수정 시각이 같은 파일은 해시를 다시 계산하지 않습니다.
+func scanMonitoredPaths(ctx context.Context, cfg *ServiceConfig, known []eventstore.BaselineFile) (baseline.ScanResult, error) {
+	fileFilter, err := filter.New(cfg.FileFilters)
+	if err != nil {
+		return baseline.ScanResult{}, fmt.Errorf("file_filters 설정 오류: %v", err)
+	}
+
+	opts := baseline.ScanOptions{
+		Match:   fileFilter.Match,
+		Skip:    fileFilter.Excluded,
+		Workers: cfg.Hashing.Workers,
+		MaxSize: int64(cfg.Hashing.MaxSizeMB) << 20,
+	}
+	if len(known) > 0 {
+		opts.Known = make(map[string]baseline.File, len(known))
+		for _, f := range fromStoredBaseline(known) {
+			opts.Known[f.Path] = f
+		}
+	}
+	return baseline.Scan(ctx, cfg.MonitoringPath, opts)
+}
+
+// toStoredBaseline은 훑은 파일을 baseline_files 행으로 변환합니다
+func toStoredBaseline(files []baseline.File) []eventstore.BaselineFile {
+	stored := make([]eventstore.BaselineFile, 0, len(files))
+	for _, f := range files {
+		stored = append(stored, eventstore.BaselineFile{Path: f.Path, Size: f.Size, ModTime: f.ModTime, SHA256: f.SHA256})
+	}
+	return stored
+}
+
+// fromStoredBaseline은 baseline_files 행을 비교용 파일 목록으로 변환합니다
+func fromStoredBaseline(stored []eventstore.BaselineFile) []baseline.File {
+	files := make([]baseline.File, 0, len(stored))
+	for _, f := range stored {
+		files = append(files, baseline.File{Path: f.Path, Size: f.Size, ModTime: f.ModTime, SHA256: f.SHA256})
+	}
+	return files
+}
+
+// diffBaseline은 저장된 기준선과 현재 감시 경로를 비교합니다
+func diffBaseline(ctx context.Context, cfg *ServiceConfig, store *eventstore.Store) (eventstore.BaselineSnapshot, baseline.ScanResult, []baseline.Change, error) {
+	snapshot, stored, err := store.Baseline()
+	if err != nil {
+		return snapshot, baseline.ScanResult{}, nil, err
+	}
+	result, err := scanMonitoredPaths(ctx, cfg, stored)
+	if err != nil {
+		return snapshot, result, nil, err
+	}
+	return snapshot, result, baseline.Diff(fromStoredBaseline(stored), result.Files, result.Covers), nil
+}
+
+// baselineJob은 서비스 시작 시와 주기적으로 기준선과 감시 경로를 비교하여 차이를 경고로 기록합니다.
+// 서비스 루프에서 tick을 호출하며, 비교는 별도 고루틴에서 하므로 서비스 제어 요청 처리를 막지 않습니다.
+type baselineJob struct {
+	running atomic.Bool
+	wg      sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// 서비스 루프에서만 접근
+	lastDiff time.Time
+	// 실행 중인 고루틴 하나만 접근: 이전 비교에서 알린 차이 (같은 차이를 주기마다 다시 알리지 않음)
+	reported map[string]bool
+}
+
+// baselineCheck는 서비스의 기준선 비교 작업입니다
+var baselineCheck baselineJob
+
+// tick은 비교할 시점이면 기준선 비교를 시작합니다. 이전 비교가 아직 실행 중이면 건너뜁니다.
+// 첫 호출은 서비스 시작 시점이며, diff_on_start가 false이면 그때부터 주기를 셉니다.
+func (j *baselineJob) tick(now time.Time, store *eventstore.Store, cfg *ServiceConfig) {
+	if store == nil {
+		return
+	}
+	if j.lastDiff.IsZero() && !cfg.Baseline.DiffOnStart {
+		j.lastDiff = now
+		return
+	}
+	if !j.lastDiff.IsZero() {
+		interval := time.Duration(cfg.Baseline.DiffIntervalHours) * time.Hour
+		if interval <= 0 || now.Sub(j.lastDiff) < interval {
+			return
+		}
+	}
+	if !j.running.CompareAndSwap(false, true) {
+		return
+	}
+	j.lastDiff = now
+	if j.ctx == nil {
+		j.ctx, j.cancel = context.WithCancel(context.Background())
+	}
+
+	snapshot := *cfg
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer j.running.Store(false)
+		j.run(store, &snapshot)
+	}()
+}
+
+// stop은 실행 중인 비교를 취소하고 끝날 때까지 기다립니다
+func (j *baselineJob) stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+}
+
+func (j *baselineJob) run(store *eventstore.Store, cfg *ServiceConfig) {
+	start := time.Now()
+	snapshot, result, changes, err := diffBaseline(j.ctx, cfg, store)
+	switch {
+	case errors.Is(err, eventstore.ErrNoBaseline):
+		logger.Log(winsvc.LogInfo, "%v", err)
+		return
+	case errors.Is(err, context.Canceled):
+		return
+	case err != nil:
+		logger.Log(winsvc.LogError, "기준선 비교 실패: %v", err)
+		return
+	}
+
+	counts := map[string]int{}
+	reported := make(map[string]bool, len(changes))
+	var alerts []eventstore.Alert
+	for _, c := range changes {
+		counts[c.Kind]++
+		key := driftKey(c)
+		reported[key] = true
+		if j.reported[key] {
+			continue
+		}
+		alert := driftAlert(c)
+		alerts = append(alerts, alert)
+		serviceMetrics.ObserveAlert(alert.Source, alert.Level)
+		logger.LogFields(winsvc.LogWarning, alert.Message, driftFields(c)...)
+	}
+	j.reported = reported
+
+	if len(alerts) > 0 {
+		if err := store.AddAlerts(alerts); err != nil {
+			logger.Log(winsvc.LogError, "%v", err)
+		}
+	}
+	logger.LogFields(winsvc.LogInfo, "기준선 비교 완료",
+		winsvc.F("baseline", snapshot.CreatedAt.Format(eventstore.TimeLayout)),
+		winsvc.F("files", len(result.Files)),
+		winsvc.F("added", counts[baseline.Added]),
+		winsvc.F("removed", counts[baseline.Removed]),
+		winsvc.F("changed", counts[baseline.Changed]),
+		winsvc.F("new_alerts", len(alerts)),
+		winsvc.F("unreadable", result.Unreadable),
+		winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+}
+
+// driftKey는 같은 차이를 다시 알리지 않도록 차이 하나를 식별합니다 (내용이 다시 바뀌면 새 차이)
+func driftKey(c baseline.Change) string {
+	key := c.Kind + "|" + c.Path
+	if c.New != nil {
+		key += fmt.Sprintf("|%d|%s|%s", c.New.Size, c.New.ModTime.Format(time.RFC3339Nano), c.New.SHA256)
+	}
+	return key
+}
+
+// driftMessages는 차이 종류별 경고 메시지와 alerts 테이블의 작업 종류입니다
+var driftMessages = map[string]struct{ message, operation string }{
+	baseline.Added:   {"기준선 이후 추가된 실행 파일", "CREATE"},
+	baseline.Removed: {"기준선 이후 삭제된 실행 파일", "REMOVE"},
+	baseline.Changed: {"기준선 이후 변경된 실행 파일", "WRITE"},
+}
+
+// driftAlert는 차이를 alerts 테이블의 행으로 변환합니다
+func driftAlert(c baseline.Change) eventstore.Alert {
+	m := driftMessages[c.Kind]
+	alert := eventstore.Alert{
+		Timestamp: time.Now(),
+		Level:     winsvc.LogWarning.String(),
+		Source:    "baseline",
+		Label:     c.Kind,
+		Message:   m.message,
+		Path:      c.Path,
+		Operation: m.operation,
+	}
+	if c.New != nil {
+		alert.SHA256 = c.New.SHA256
+	} else if c.Old != nil {
+		alert.SHA256 = c.Old.SHA256
+	}
+	return alert
+}
+
+// driftFields는 차이의 로그 필드를 만듭니다
+func driftFields(c baseline.Change) []winsvc.Field {
+	fields := []winsvc.Field{winsvc.F("path", c.Path), winsvc.F("kind", c.Kind)}
+	if c.Old != nil {
+		fields = append(fields,
+			winsvc.F("old_size", c.Old.Size),
+			winsvc.F("old_mtime", c.Old.ModTime.Format(time.RFC3339)),
+			winsvc.F("old_sha256", c.Old.SHA256))
+	}
+	if c.New != nil {
+		fields = append(fields,
+			winsvc.F("size", c.New.Size),
+			winsvc.F("mtime", c.New.ModTime.Format(time.RFC3339)),
+			winsvc.F("sha256", c.New.SHA256))
+	}
+	return fields
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"windows_service_module/pkg/baseline"
+	"windows_service_module/pkg/eventstore"
+)
+
+// runBaselineCommand는 감시 경로의 기준선을 만들거나 현재 상태와 비교합니다.
+// 감시 경로, 파일 필터, 해시 설정은 설정 파일에서 읽으며, 서비스가 실행 중이어도 사용할 수 있습니다.
+func runBaselineCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "create" && args[0] != "diff") {
+		fmt.Fprintln(os.Stderr, "사용법: baseline create|diff [--db 데이터베이스 경로] [--format table|json]")
+		return 1
+	}
+	sub := args[0]
+
+	fs := flag.NewFlagSet("baseline "+sub, flag.ContinueOnError)
+	dbPath := fs.String("db", "", "데이터베이스 파일 경로 (기본값: 설정의 database_path)")
+	format := fs.String("format", "table", "diff 출력 형식 (table, json)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "알 수 없는 출력 형식: %s (table, json 중 하나)\n", *format)
+		return 1
+	}
+
+	cfg, err := loadOfflineConfig("--db")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	path := *dbPath
+	if path == "" {
+		path = cfg.DatabasePath
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	if sub == "create" {
+		store, err := eventstore.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		defer store.Close()
+
+		result, err := scanMonitoredPaths(ctx, cfg, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		snapshot, err := store.SaveBaseline(cfg.MonitoringPath, toStoredBaseline(result.Files))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("기준선을 만들었습니다: 파일 %d개 (해시 실패 %d개, 읽을 수 없는 항목 %d개, %s)\n",
+			snapshot.Files, result.HashErrors, result.Unreadable, time.Since(start).Round(time.Millisecond))
+		return 0
+	}
+
+	store, err := eventstore.OpenReadOnly(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	snapshot, result, changes, err := diffBaseline(ctx, cfg, store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		err = writeDriftJSON(os.Stdout, snapshot, changes)
+	} else {
+		err = writeDriftTable(os.Stdout, snapshot, result, changes)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "출력 실패: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeDriftTable은 기준선과의 차이를 표로 출력합니다
+func writeDriftTable(out io.Writer, snapshot eventstore.BaselineSnapshot, result baseline.ScanResult, changes []baseline.Change) error {
+	fmt.Fprintf(out, "기준선: %s (파일 %d개), 현재: 파일 %d개\n\n",
+		snapshot.CreatedAt.Format(eventstore.TimeLayout), snapshot.Files, len(result.Files))
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(out, "기준선 이후 달라진 파일이 없습니다")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "종류\t크기\t수정 시각\tSHA256\t경로")
+	for _, c := range changes {
+		f := c.New
+		if f == nil {
+			f = c.Old
+		}
+		size, sum := fmt.Sprint(f.Size), shortHash(f.SHA256)
+		if c.Kind == baseline.Changed {
+			size = fmt.Sprintf("%d -> %d", c.Old.Size, c.New.Size)
+			sum = shortHash(c.Old.SHA256) + " -> " + shortHash(c.New.SHA256)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Kind, size, f.ModTime.Local().Format(eventstore.TimeLayout), sum, c.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\n차이 %d건\n", len(changes))
+	return err
+}
+
+// writeDriftJSON은 기준선 정보와 차이를 JSON으로 출력합니다
+func writeDriftJSON(out io.Writer, snapshot eventstore.BaselineSnapshot, changes []baseline.Change) error {
+	if changes == nil {
+		changes = []baseline.Change{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Baseline eventstore.BaselineSnapshot `json:"baseline"`
+		Changes  []baseline.Change           `json:"changes"`
+	}{snapshot, changes})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/catchup"
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/winsvc"
+)
+
+// catchUpJob은 서비스 시작 시 마지막 실행 이후 변경된 파일을 찾아 이벤트로 기록합니다 (보완 검사).
+// 실시간 감시는 시작한 시점부터의 변경만 알 수 있으므로, 중지된 동안의 변경을 파일의 수정/생성 시각으로 찾습니다.
+// 검사는 서비스가 Running을 알린 뒤 별도 고루틴에서 하므로 큰 감시 경로도 시작 제한 시간에 영향을 주지 않습니다.
+type catchUpJob struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	// since는 검사 기준 시각입니다 (start에서 정하며 서비스 루프에서만 접근)
+	since time.Time
+	// done은 검사가 끝났거나 할 필요가 없는지 여부입니다.
+	// 끝나기 전에 서비스가 중지되면 다음 시작 때 같은 기준으로 다시 검사하도록 기준 시각을 유지합니다.
+	done atomic.Bool
+}
+
+// catchUp은 서비스의 시작 시 보완 검사 작업입니다
+var catchUp catchUpJob
+
+// start는 상태 파일의 마지막 실행 시각을 읽고 그 이후 변경된 파일의 검사를 시작합니다.
+// 실시간 감시를 시작한 뒤 호출해야 검사와 감시 사이에 빠지는 변경이 없습니다.
+func (j *catchUpJob) start(ctx context.Context, a *agent.Agent, cfg *ServiceConfig) {
+	if !cfg.CatchUp.Enabled {
+		j.done.Store(true)
+		return
+	}
+	state, err := loadServiceState(serviceStatePath())
+	if err != nil {
+		logger.Log(winsvc.LogWarning, "마지막 실행 시각을 읽을 수 없어 보완 검사를 건너뜁니다: %v", err)
+		j.done.Store(true)
+		return
+	}
+	if j.since = state.LastSeen; j.since.IsZero() {
+		logger.Log(winsvc.LogInfo, "마지막 실행 시각 기록이 없어 보완 검사를 건너뜁니다")
+		j.done.Store(true)
+		return
+	}
+
+	fileFilter, err := filter.New(cfg.FileFilters)
+	if err != nil {
+		logger.Log(winsvc.LogError, "보완 검사 실패: file_filters 설정 오류: %v", err)
+		j.done.Store(true)
+		return
+	}
+	// 실시간 감시를 시작한 뒤의 변경은 모니터가 알리므로 그 시각까지만 검사 (같은 변경을 두 번 기록하지 않음)
+	opts := catchup.Options{
+		Since:   j.since,
+		Until:   a.Status().StartedAt,
+		Match:   fileFilter.Match,
+		Skip:    fileFilter.Excluded,
+		Workers: cfg.CatchUp.Workers,
+	}
+	roots := append([]string(nil), cfg.MonitoringPath...)
+
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.run(ctx, a, roots, opts)
+	}()
+}
+
+func (j *catchUpJob) run(ctx context.Context, a *agent.Agent, roots []string, opts catchup.Options) {
+	logger.LogFields(winsvc.LogInfo, "서비스가 중지된 동안 변경된 파일을 검사합니다",
+		winsvc.F("since", opts.Since.Format(time.RFC3339)),
+		winsvc.F("until", opts.Until.Format(time.RFC3339)),
+		winsvc.F("paths", strings.Join(roots, ",")))
+
+	start := time.Now()
+	var events atomic.Int64
+	result, err := catchup.Scan(ctx, roots, opts, func(c catchup.Change) {
+		event := agent.Event{
+			Path:      c.Path,
+			Operation: c.Operation,
+			Timestamp: c.Time,
+			FileType:  strings.ToLower(filepath.Ext(c.Path)),
+		}
+		if a.ProcessOffline(ctx, event) {
+			events.Add(1)
+		}
+	})
+	if errors.Is(err, context.Canceled) {
+		logger.LogFields(winsvc.LogInfo, "보완 검사가 중단되었습니다 (다음 시작 시 다시 검사)",
+			winsvc.F("events", events.Load()),
+			winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+		return
+	}
+	j.done.Store(true)
+
+	logger.LogFields(winsvc.LogInfo, "보완 검사 완료",
+		winsvc.F("since", opts.Since.Format(time.RFC3339)),
+		winsvc.F("dirs", result.Dirs),
+		winsvc.F("files", result.Files),
+		winsvc.F("events", events.Load()),
+		winsvc.F("unreadable", result.Unreadable),
+		winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+}
+
+// stop은 실행 중인 검사를 취소하고 끝날 때까지 기다립니다
+func (j *catchUpJob) stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+}
+
+// recordLastSeen은 now까지의 파일 변경을 처리했음을 상태 파일에 기록합니다.
+// 서비스 루프에서 주기적으로, 그리고 이벤트 루프가 끝난 뒤 호출합니다.
+// 보완 검사가 끝나지 않았으면 다음 시작 때 같은 기준으로 다시 검사하도록 이전 기준 시각을 유지합니다.
+func (j *catchUpJob) recordLastSeen(now time.Time) {
+	if !j.done.Load() {
+		now = j.since
+	}
+	_, err := updateServiceState(serviceStatePath(), func(state *serviceState) {
+		state.LastSeen = now
+	})
+	if err != nil {
+		logger.Log(winsvc.LogWarning, "마지막 실행 시각을 기록할 수 없습니다: %v", err)
+	}
+}
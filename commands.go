@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runOfflineCommand는 서비스 관리자나 기본 설정 파일 생성 없이 실행하는 명령을 처리합니다.
+// 처리한 명령이면 종료 코드와 true를 반환합니다.
+func runOfflineCommand(args []string) (int, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	switch args[0] {
+	case "validate-config":
+		return runValidateConfig(args[1:]), true
+	case "config":
+		if len(args) < 2 || args[1] != "show" {
+			fmt.Fprintln(os.Stderr, "사용법: config show [--effective] [설정 파일 경로]")
+			return 1, true
+		}
+		return runConfigShow(args[2:]), true
+	case "events":
+		return runEventsCommand(args[1:]), true
+	case "db":
+		return runDBCommand(args[1:]), true
+	case "baseline":
+		return runBaselineCommand(args[1:]), true
+	case "quarantine":
+		return runQuarantineCommand(args[1:]), true
+	}
+	return 0, false
+}
+
+// commandUsage는 사용법에 표시할 명령 하나입니다
+type commandUsage struct {
+	command     string
+	description string
+}
+
+// offlineCommandUsage는 runOfflineCommand가 처리하는 명령의 사용법입니다
+var offlineCommandUsage = []commandUsage{
+	{"validate-config [경로]", "설정 파일 검증"},
+	{"config show [--effective] [경로]", "설정 출력 (--effective: 최종 값과 출처)"},
+	{"events [옵션]", "파일 이벤트 조회 (events -h로 옵션 확인)"},
+	{"db migrate|status [--db 경로]", "데이터베이스 스키마 적용/확인"},
+	{"quarantine list|restore <ID>|purge", "격리한 파일 조회/복원/삭제"},
+	{"baseline create|diff [--db 경로]", "감시 경로 기준선 생성/비교"},
+}
+
+// usage는 오류 메시지와 사용법을 출력하고 종료합니다.
+// 서비스 관리 명령(serviceCommandUsage)과 실행 명령(runCommandUsage)은 플랫폼별로 정의합니다.
+func usage(errmsg string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n사용법:\n", errmsg)
+	for _, group := range [][]commandUsage{serviceCommandUsage, offlineCommandUsage, runCommandUsage} {
+		for _, c := range group {
+			fmt.Fprintf(&b, "  %s %-10s - %s\n", os.Args[0], c.command, c.description)
+		}
+	}
+	b.WriteString("\n설정 값은 명령 앞의 --<필드>=<값> 플래그나 HJSVC_<필드> 환경 변수로 덮어쓸 수 있습니다\n")
+	b.WriteString("  (예: --log-path=./logs, HJSVC_MONITORING_PATH=C:\\,D:\\)\n")
+	fmt.Fprint(os.Stderr, b.String())
+	os.Exit(1)
+}
@@ -1,6 +1,3 @@
-//go:build windows
-// +build windows
-
 package main
 
 import (
@@ -8,6 +5,17 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/baseline"
+	"windows_service_module/pkg/catchup"
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/hashing"
+	"windows_service_module/pkg/hashlist"
+	"windows_service_module/pkg/quarantine"
+	"windows_service_module/pkg/sink"
+	"windows_service_module/pkg/winsvc"
 )
 
 // ServiceConfig는 서비스 설정 정보를 담는 구조체
@@ -19,50 +27,110 @@ type ServiceConfig struct {
 	RestartDelay       int  `json:"restart_delay"` // 초 단위
 	MaxRestartAttempts int  `json:"max_restart_attempts"`
 	// 로그 설정
-	LogPath string `json:"log_path"`
+	LogPath   string `json:"log_path"`
+	LogLevel  string `json:"log_level"`  // debug, info, warning, error
+	LogFormat string `json:"log_format"` // text, json
+	// 로그 파일 회전 설정 (크기/시간 기준, 보관 개수, 압축)
+	LogRotation winsvc.RotationConfig `json:"log_rotation"`
+	// 비동기 로그 기록 설정 (큐 크기, 큐가 가득 찼을 때의 처리 방식)
+	LogAsync winsvc.AsyncConfig `json:"log_async"`
 	// 데이터베이스 경로 설정
 	DatabasePath string `json:"database_path"`
+	// 이벤트 보관 기간, 정리 주기, 삭제 전 보관(custom_data_path/archive) 설정
+	Retention eventstore.RetentionConfig `json:"retention"`
+	// 생성/변경된 실행 파일의 SHA-256 계산 설정 (작업자 수, 크기 제한, 재시도)
+	Hashing hashing.Config `json:"hashing"`
+	// 해시를 비교할 허용/차단 목록 파일 (비어 있으면 사용 안 함, 변경 시 자동으로 다시 로드)
+	HashLists hashlist.Config `json:"hash_lists"`
+	// 규칙의 quarantine 동작으로 파일을 custom_data_path/quarantine에 격리 (false이면 경고만 기록)
+	Quarantine quarantine.Config `json:"quarantine"`
 	// 모니터링 경로 설정
 	MonitoringPath []string `json:"monitoring_path"`
+	// 파일 필터 설정 (확장자, 포함/제외 패턴)
+	FileFilters filter.Config `json:"file_filters"`
+	// 이벤트를 보낼 외부 싱크 목록 (webhook, syslog, file)
+	Sinks []sink.Config `json:"sinks"`
+	// 같은 경로의 연속된 이벤트를 하나로 합치는 대기 시간 (window_ms가 0이면 합치지 않음)
+	EventCoalescing agent.CoalesceConfig `json:"event_coalescing"`
+	// 감시 경로 기준선(baseline create)과 비교하는 시점 (시작 시, 주기)
+	Baseline baseline.Config `json:"baseline"`
+	// 서비스가 중지된 동안 변경된 파일을 시작 시 찾아 offline-detected 이벤트로 기록 (보완 검사)
+	CatchUp catchup.Config `json:"catch_up"`
+	// 상태 HTTP 서버 주소 (예: "127.0.0.1:9790", 비어 있으면 사용 안 함, 루프백 주소만 허용)
+	StatusAddress string `json:"status_address"`
 	// 기타 설정
 	CustomDataPath string `json:"custom_data_path"`
 }
 
-// 기본 설정값
-var defaultConfig = ServiceConfig{
-	ServiceName:        "hj-service",
-	ServiceDescription: "hj-service module",
-	RestartOnFailure:   true,
-	RestartDelay:       5,
-	MaxRestartAttempts: 3,
-	LogPath:            "./logs",
-	DatabasePath:       "./db.sqlite",
-	MonitoringPath:     []string{"C:\\"},
-	CustomDataPath:     "./data",
+// newDefaultConfig는 기본 설정값의 새 복사본을 반환합니다
+func newDefaultConfig() ServiceConfig {
+	return ServiceConfig{
+		ServiceName:        "hj-service",
+		ServiceDescription: "hj-service module",
+		RestartOnFailure:   true,
+		RestartDelay:       5,
+		MaxRestartAttempts: 3,
+		LogPath:            "./logs",
+		LogLevel:           "info",
+		LogFormat:          "text",
+		LogRotation: winsvc.RotationConfig{
+			MaxSizeMB:     10,
+			IntervalHours: 24,
+			MaxFiles:      7,
+			Compress:      true,
+		},
+		LogAsync: winsvc.AsyncConfig{
+			Enabled:    true,
+			QueueSize:  winsvc.DefaultLogQueueSize,
+			DropPolicy: winsvc.DropNewest,
+		},
+		DatabasePath: "./db.sqlite",
+		Retention: eventstore.RetentionConfig{
+			MaxAgeDays:           0, // 삭제는 설정한 경우에만 (opt-in)
+			PruneIntervalMinutes: 60,
+			Archive:              true,
+			VacuumIntervalHours:  24 * 7,
+		},
+		Hashing: hashing.Config{
+			Enabled:      true,
+			Workers:      hashing.DefaultWorkers,
+			MaxSizeMB:    256,
+			Retries:      5,
+			RetryDelayMs: 500,
+		},
+		MonitoringPath: append([]string(nil), defaultMonitoringPath...),
+		FileFilters: filter.Config{
+			Extensions: append([]string(nil), filter.DefaultExtensions...),
+		},
+		EventCoalescing: agent.CoalesceConfig{
+			WindowMs:  500,
+			MaxWaitMs: 5000,
+		},
+		Baseline: baseline.Config{
+			DiffOnStart:       false, // 시작 시 비교는 설정한 경우에만 (opt-in)
+			DiffIntervalHours: 24,
+		},
+		CatchUp: catchup.Config{
+			Enabled: false, // 보완 검사는 설정한 경우에만 (opt-in)
+			Workers: catchup.DefaultWorkers,
+		},
+		CustomDataPath: "./data",
+	}
 }
 
-// LoadConfig는 설정 파일을 읽어옵니다.
+// LoadConfig는 기본값, 설정 파일, 환경 변수, 명령행 플래그 순으로 설정을 합치고 검증합니다.
+// 설정 파일이 없으면 기본값에 환경 변수와 플래그만 적용합니다. 감시 경로의 존재 여부는 확인하지 않습니다 (ValidatePaths).
 func LoadConfig(configPath string) (*ServiceConfig, error) {
-	var config ServiceConfig
-
-	// 설정 파일이 존재하지 않으면 기본 설정 값 반환
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return &defaultConfig, nil
-	}
-
-	// 설정 파일 읽기
-	configData, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
+	return loadConfig(configPath, false)
+}
 
-	// JSON 파싱
-	err = json.Unmarshal(configData, &config)
+// loadConfig는 LoadConfig와 같으며 checkPaths이면 감시 경로의 존재 여부도 함께 검증합니다
+func loadConfig(configPath string, checkPaths bool) (*ServiceConfig, error) {
+	config, _, err := loadConfigLayers(configPath, checkPaths)
 	if err != nil {
 		return nil, err
 	}
-
-	return &config, nil
+	return config, nil
 }
 
 // SaveConfig는 설정을 파일에 저장합니다.
@@ -89,6 +157,7 @@ func SaveConfig(config *ServiceConfig, configPath string) error {
 // EnsureDefaultConfig는 설정 파일이 없으면 기본 설정을 저장합니다.
 func EnsureDefaultConfig(configPath string) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		defaultConfig := newDefaultConfig()
 		err = SaveConfig(&defaultConfig, configPath)
 		if err != nil {
 			log.Printf("기본 설정 파일 생성 실패: %v", err)
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"text/tabwriter"
+)
+
+// runConfigShow는 설정 파일 내용 또는 모든 계층을 합친 설정과 각 값의 출처를 출력합니다
+func runConfigShow(args []string) int {
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	effective := fs.Bool("effective", false, "기본값, 파일, 환경 변수, 플래그를 합친 설정과 출처 출력")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	path := defaultConfigPath()
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	if !*effective {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "설정 파일을 읽을 수 없습니다: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	config, sources, err := loadConfigLayers(path, false)
+	var validationErr *ValidationError
+	if err != nil && !errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "설정을 로드할 수 없습니다: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("설정 파일: %s\n\n", path)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "필드\t값\t출처")
+	value := reflect.ValueOf(config).Elem()
+	for _, f := range configFields() {
+		source := sources[f.Path]
+		switch source {
+		case SourceEnv:
+			source += " (" + f.EnvName() + ")"
+		case SourceFlag:
+			source += " (--" + f.FlagName() + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Path, jsonValue(value.FieldByIndex(f.Index).Interface()), source)
+	}
+	w.Flush()
+
+	if validationErr != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", validationErr)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 설정 값의 출처
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// envPrefix는 설정을 덮어쓰는 환경 변수의 접두사입니다 (예: HJSVC_LOG_PATH)
+const envPrefix = "HJSVC_"
+
+// flagOverrides는 명령행 플래그로 지정한 설정 값입니다 (필드 경로 -> 값)
+var flagOverrides = map[string]string{}
+
+// configField는 설정 구조체의 말단 필드 하나입니다
+type configField struct {
+	Path  string // JSON 경로 (예: "file_filters.exclude")
+	Index []int  // reflect.Value.FieldByIndex 인덱스
+}
+
+// EnvName은 필드를 덮어쓰는 환경 변수 이름을 반환합니다
+func (f configField) EnvName() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(f.Path, ".", "_"))
+}
+
+// FlagName은 필드를 덮어쓰는 명령행 플래그 이름을 반환합니다
+func (f configField) FlagName() string {
+	return strings.ReplaceAll(f.Path, "_", "-")
+}
+
+// configFields는 ServiceConfig에서 환경 변수와 플래그로 덮어쓸 수 있는 모든 말단 필드를 선언 순서대로 반환합니다
+func configFields() []configField {
+	return collectFields(reflect.TypeOf(ServiceConfig{}), "", nil)
+}
+
+func collectFields(t reflect.Type, prefix string, index []int) []configField {
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		idx := append(append([]int(nil), index...), i)
+
+		switch {
+		case sf.Type.Kind() == reflect.Struct:
+			fields = append(fields, collectFields(sf.Type, path, idx)...)
+		case scalarField(sf.Type):
+			fields = append(fields, configField{Path: path, Index: idx})
+		}
+		// 구조체 목록(sinks) 등은 환경 변수나 플래그 한 값으로 나타낼 수 없으므로 설정 파일로만 지정
+	}
+	return fields
+}
+
+// scalarField는 setFieldValue로 설정할 수 있는 타입(문자열, 정수, 불리언, 문자열 목록)인지 확인합니다
+func scalarField(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int64, reflect.Bool:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// setFieldValue는 문자열 값을 필드 타입에 맞게 변환하여 설정합니다.
+// 목록 값은 쉼표로 구분합니다.
+func setFieldValue(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("정수가 아닙니다: %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("true 또는 false가 아닙니다: %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("지원하지 않는 목록 타입입니다")
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("지원하지 않는 타입입니다: %s", v.Kind())
+	}
+	return nil
+}
+
+// jsonPathPresent는 JSON 객체에 점으로 구분된 경로의 키가 있는지 확인합니다
+func jsonPathPresent(data []byte, path string) bool {
+	for _, key := range strings.Split(path, ".") {
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return false
+		}
+		value, ok := obj[key]
+		if !ok {
+			return false
+		}
+		data = value
+	}
+	return true
+}
+
+// loadConfigLayers는 기본값 -> 설정 파일 -> 환경 변수 -> 플래그 순으로 설정을 합칩니다.
+// 각 필드의 출처를 함께 반환하며, 검증 오류가 있어도 합쳐진 설정은 반환합니다.
+// checkPaths이면 감시 경로의 존재 여부도 검증합니다 (ValidatePaths).
+func loadConfigLayers(configPath string, checkPaths bool) (*ServiceConfig, map[string]string, error) {
+	config := newDefaultConfig()
+	fields := configFields()
+	sources := make(map[string]string, len(fields))
+	for _, f := range fields {
+		sources[f.Path] = SourceDefault
+	}
+
+	v := &ValidationError{}
+	value := reflect.ValueOf(&config).Elem()
+
+	// 설정 파일 (있는 필드만 기본값을 덮어씀)
+	data, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, nil, err
+		}
+		unknownFields(data, value.Type(), "", v)
+		for _, f := range fields {
+			if jsonPathPresent(data, f.Path) {
+				sources[f.Path] = SourceFile
+			}
+		}
+	case !os.IsNotExist(err):
+		return nil, nil, err
+	}
+
+	// 환경 변수
+	for _, f := range fields {
+		raw, ok := os.LookupEnv(f.EnvName())
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(value.FieldByIndex(f.Index), raw); err != nil {
+			v.add(f.Path, "환경 변수 %s: %v", f.EnvName(), err)
+			continue
+		}
+		sources[f.Path] = SourceEnv
+	}
+
+	// 명령행 플래그
+	for _, f := range fields {
+		raw, ok := flagOverrides[f.Path]
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(value.FieldByIndex(f.Index), raw); err != nil {
+			v.add(f.Path, "플래그 --%s: %v", f.FlagName(), err)
+			continue
+		}
+		sources[f.Path] = SourceFlag
+	}
+
+	config.validate(v)
+	if checkPaths {
+		config.validatePaths(v)
+	}
+
+	// 출력 순서를 일정하게 유지
+	sort.SliceStable(v.Errors, func(i, j int) bool {
+		return v.Errors[i].Field < v.Errors[j].Field
+	})
+	return &config, sources, v.err()
+}
+
+// parseConfigFlags는 명령 앞에 지정한 --필드=값 플래그를 flagOverrides에 기록하고
+// 나머지 인자를 반환합니다 (예: --log-path=/var/log/hj --file-filters.extensions=.exe,.sys)
+func parseConfigFlags(args []string) ([]string, error) {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // 오류는 호출한 쪽의 usage로 출력
+	for _, f := range configFields() {
+		path := f.Path
+		fs.Func(f.FlagName(), fmt.Sprintf("%s 설정 덮어쓰기 (환경 변수 %s)", path, f.EnvName()),
+			func(s string) error {
+				flagOverrides[path] = s
+				return nil
+			})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
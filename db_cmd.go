@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"windows_service_module/pkg/eventstore"
+)
+
+// runDBCommand는 서비스 데이터베이스의 스키마 버전을 확인하거나 올립니다.
+// 서비스 시작 시에도 자동으로 적용되므로, 업그레이드 전에 미리 적용하거나 상태를 확인할 때 사용합니다.
+func runDBCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "migrate" && args[0] != "status") {
+		fmt.Fprintln(os.Stderr, "사용법: db migrate|status [--db 데이터베이스 경로]")
+		return 1
+	}
+	sub := args[0]
+
+	fs := flag.NewFlagSet("db "+sub, flag.ContinueOnError)
+	dbPath := fs.String("db", "", "데이터베이스 파일 경로 (기본값: 설정의 database_path)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	path := *dbPath
+	if path == "" {
+		var err error
+		if path, err = configuredDatabasePath(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	if sub == "migrate" {
+		applied, err := eventstore.Migrate(path)
+		for _, m := range applied {
+			fmt.Printf("적용됨: %04d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Printf("스키마가 이미 최신 버전입니다 (버전 %d)\n", eventstore.LatestVersion())
+		}
+		return 0
+	}
+
+	store, err := eventstore.OpenReadOnly(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	status, err := store.SchemaStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if err := writeSchemaStatus(os.Stdout, path, status); err != nil {
+		fmt.Fprintf(os.Stderr, "출력 실패: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeSchemaStatus는 스키마 버전별 적용 상태를 표로 출력합니다
+func writeSchemaStatus(w io.Writer, path string, status []eventstore.MigrationStatus) error {
+	current, pending := 0, 0
+	for _, st := range status {
+		if st.Applied {
+			current = st.Version
+		} else {
+			pending++
+		}
+	}
+
+	fmt.Fprintf(w, "데이터베이스: %s\n", path)
+	fmt.Fprintf(w, "현재 버전: %d, 지원 버전: %d, 대기 중: %d\n\n", current, eventstore.LatestVersion(), pending)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "버전\t이름\t상태\t적용 시각")
+	for _, st := range status {
+		state, appliedAt := "대기", ""
+		if st.Applied {
+			state = "적용됨"
+			if !st.AppliedAt.IsZero() {
+				appliedAt = st.AppliedAt.Local().Format(eventstore.TimeLayout)
+			}
+		}
+		if st.Version > eventstore.LatestVersion() {
+			state = "알 수 없음 (새 버전)"
+		}
+		fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	return tw.Flush()
+}
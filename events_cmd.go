@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"windows_service_module/pkg/eventstore"
+)
+
+// runEventsCommand는 file_events 데이터베이스에서 이벤트를 조회하여 출력합니다.
+// 서비스 관리자 없이 실행되며 다른 서버에서 복사한 데이터베이스 파일도 --db로 지정할 수 있습니다.
+func runEventsCommand(args []string) int {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "데이터베이스 파일 경로 (기본값: 설정의 database_path)")
+	since := fs.String("since", "", "이 시각 이후 (예: 2025-01-02, \"2025-01-02 15:04:05\", RFC 3339, 24h)")
+	until := fs.String("until", "", "이 시각 이전 (형식은 --since와 같음)")
+	pathPrefix := fs.String("path", "", "경로 접두사 (대소문자 구분 없음)")
+	ops := fs.String("op", "", "작업 종류, 쉼표로 구분 (예: CREATE,REMOVE)")
+	types := fs.String("type", "", "확장자, 쉼표로 구분 (예: .exe,.dll)")
+	sha := fs.String("sha256", "", "파일 SHA-256 (다른 서버의 같은 파일 이벤트 찾기)")
+	offline := fs.Bool("offline", false, "서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트만")
+	limit := fs.Int("limit", 100, "페이지당 최대 행 수 (0이면 제한 없음)")
+	page := fs.Int("page", 1, "페이지 번호 (1부터 시작)")
+	desc := fs.Bool("desc", false, "최신 이벤트부터 출력")
+	format := fs.String("format", "table", "출력 형식 (table, csv, json)")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	q := eventstore.Query{
+		PathPrefix: *pathPrefix,
+		Operations: splitList(*ops),
+		FileTypes:  splitList(*types),
+		SHA256:     strings.TrimSpace(*sha),
+		Offline:    *offline,
+		Limit:      *limit,
+		Descending: *desc,
+	}
+
+	var err error
+	now := time.Now()
+	if q.Since, err = parseTimeArg(*since, now); err != nil {
+		fmt.Fprintf(os.Stderr, "--since: %v\n", err)
+		return 1
+	}
+	if q.Until, err = parseTimeArg(*until, now); err != nil {
+		fmt.Fprintf(os.Stderr, "--until: %v\n", err)
+		return 1
+	}
+	if *limit < 0 || *page < 1 {
+		fmt.Fprintln(os.Stderr, "--limit은 0 이상, --page는 1 이상이어야 합니다")
+		return 1
+	}
+	if *limit > 0 {
+		q.Offset = (*page - 1) * *limit
+	}
+
+	switch *format {
+	case "table", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "알 수 없는 출력 형식: %s (table, csv, json 중 하나)\n", *format)
+		return 1
+	}
+
+	path := *dbPath
+	if path == "" {
+		if path, err = configuredDatabasePath(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	store, err := eventstore.OpenReadOnly(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	records, err := store.Find(q)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	switch *format {
+	case "csv":
+		err = writeEventsCSV(os.Stdout, records)
+	case "json":
+		err = writeEventsJSON(os.Stdout, records)
+	default:
+		var total int
+		if total, err = store.Count(q); err == nil {
+			err = writeEventsTable(os.Stdout, records, q.Offset, total)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "출력 실패: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// configuredDatabasePath는 설정 계층에서 database_path를 읽어 절대 경로로 반환합니다.
+// 조회에는 database_path만 필요하므로 다른 필드의 검증 오류는 무시합니다.
+func configuredDatabasePath() (string, error) {
+	cfg, err := loadOfflineConfig("--db")
+	if err != nil {
+		return "", err
+	}
+	return cfg.DatabasePath, nil
+}
+
+// loadOfflineConfig는 오프라인 명령에서 경로를 얻기 위해 설정 계층을 읽고 상대 경로를 변환합니다.
+// 검증 오류는 무시하며, 설정을 읽을 수 없으면 경로를 직접 지정할 플래그(flagName)를 안내합니다.
+func loadOfflineConfig(flagName string) (*ServiceConfig, error) {
+	cfg, _, err := loadConfigLayers(defaultConfigPath(), false)
+	var validationErr *ValidationError
+	if err != nil && !errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("설정을 로드할 수 없습니다 (%s로 경로를 지정하세요): %v", flagName, err)
+	}
+	if err := resolvePaths(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// splitList는 쉼표로 구분된 값을 나눕니다
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTimeArg는 날짜, 날짜와 시각, RFC 3339 또는 현재 기준 상대 시간(24h, 30m)을 해석합니다
+func parseTimeArg(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{eventstore.TimeLayout, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("시각 형식을 해석할 수 없습니다: %q", s)
+}
+
+// writeEventsTable은 이벤트를 표로 출력하고 전체 건수 중 출력 범위를 표시합니다
+func writeEventsTable(out io.Writer, records []eventstore.Record, offset, total int) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\t시각\t작업\t유형\t횟수\tSHA256\t경로")
+	offline := false
+	for _, r := range records {
+		op := r.Operation
+		if r.OfflineDetected {
+			op += "*"
+			offline = true
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			r.ID, r.Timestamp.Format(eventstore.TimeLayout), op, r.FileType, r.Count, shortHash(r.SHA256), r.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if offline {
+		fmt.Fprintln(out, "\n* 서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트 (시각은 파일의 수정/생성 시각)")
+	}
+
+	if len(records) == 0 {
+		_, err := fmt.Fprintf(out, "\n전체 %d건 중 조회된 이벤트가 없습니다\n", total)
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\n전체 %d건 중 %d-%d\n", total, offset+1, offset+len(records))
+	return err
+}
+
+// shortHash는 표 출력용으로 해시의 앞 12자리만 반환합니다 (전체 값은 csv/json 형식으로 확인)
+func shortHash(sum string) string {
+	if sum == "" {
+		return "-"
+	}
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
+
+// writeEventsCSV는 이벤트를 헤더가 있는 CSV로 출력합니다
+func writeEventsCSV(out io.Writer, records []eventstore.Record) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"id", "timestamp", "path", "operation", "file_type", "sha256", "count", "last_timestamp", "offline_detected"})
+	for _, r := range records {
+		w.Write([]string{
+			strconv.FormatInt(r.ID, 10),
+			r.Timestamp.Format(time.RFC3339),
+			r.Path,
+			r.Operation,
+			r.FileType,
+			r.SHA256,
+			strconv.Itoa(r.Count),
+			r.LastTimestamp.Format(time.RFC3339),
+			strconv.FormatBool(r.OfflineDetected),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeEventsJSON은 이벤트를 JSON 배열로 출력합니다
+func writeEventsJSON(out io.Writer, records []eventstore.Record) error {
+	if records == nil {
+		records = []eventstore.Record{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/eventstore"
+)
+
+const testSHA256 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+// createEventsDB는 조회 테스트용 이벤트 5개를 저장한 데이터베이스를 만들고 경로를 반환합니다
+func createEventsDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, err := eventstore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.Local) }
+	records := []eventstore.Record{
+		{Timestamp: at(1, 9), Path: `C:\Apps\a.exe`, Operation: "CREATE", FileType: ".exe", SHA256: testSHA256},
+		{Timestamp: at(1, 10), Path: `C:\Apps\b.dll`, Operation: "CREATE", FileType: ".dll"},
+		{Timestamp: at(2, 9), Path: `C:\apps\a.exe`, Operation: "REMOVE", FileType: ".exe"},
+		{Timestamp: at(2, 10), Path: `C:\Temp\x_y.exe`, Operation: "CREATE", FileType: ".EXE", OfflineDetected: true},
+		{Timestamp: at(3, 9), Path: `C:\Temp\xzy.exe`, Operation: "WRITE", FileType: ".exe", Count: 3, LastTimestamp: at(3, 11)},
+	}
+	if err := store.Append(records); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runEvents는 events 명령을 실행하고 표준 출력과 종료 코드를 반환합니다
+func runEvents(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	code := runEventsCommand(args)
+	w.Close()
+	return <-output, code
+}
+
+// eventIDs는 JSON 출력에서 이벤트 ID를 순서대로 읽습니다
+func eventIDs(t *testing.T, out string) []int64 {
+	t.Helper()
+	var records []eventstore.Record
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("JSON 형식 오류: %v\n%s", err, out)
+	}
+	ids := []int64{}
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestEventsCommandFilters(t *testing.T) {
+	db := createEventsDB(t)
+	tests := []struct {
+		name string
+		args []string
+		want []int64
+	}{
+		{"조건 없음", nil, []int64{1, 2, 3, 4, 5}},
+		{"경로 접두사 대소문자", []string{"--path", `c:\APPS\`}, []int64{1, 2, 3}},
+		{"경로의 LIKE 특수 문자", []string{"--path", `C:\Temp\x_`}, []int64{4}},
+		{"작업 종류", []string{"--op", "create, remove"}, []int64{1, 2, 3, 4}},
+		{"확장자 대소문자", []string{"--type", ".exe"}, []int64{1, 3, 4, 5}},
+		{"SHA-256 대문자", []string{"--sha256", strings.ToUpper(testSHA256)}, []int64{1}},
+		{"보완 검사 이벤트", []string{"--offline"}, []int64{4}},
+		{"시각 범위", []string{"--since", "2025-01-02", "--until", "2025-01-03"}, []int64{3, 4}},
+		{"시각 범위 경계", []string{"--since", "2025-01-01 10:00:00", "--until", "2025-01-02 09:00"}, []int64{2}},
+		{"조건 조합", []string{"--op", "CREATE", "--type", ".exe"}, []int64{1, 4}},
+		{"일치 없음", []string{"--op", "RENAME"}, []int64{}},
+	}
+	for _, tt := range tests {
+		out, code := runEvents(t, append([]string{"--db", db, "--format", "json"}, tt.args...)...)
+		if code != 0 {
+			t.Errorf("%s: 종료 코드 = %d", tt.name, code)
+			continue
+		}
+		if got := eventIDs(t, out); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ID = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventsCommandPaging(t *testing.T) {
+	db := createEventsDB(t)
+	tests := []struct {
+		args []string
+		want []int64
+	}{
+		{[]string{"--limit", "2"}, []int64{1, 2}},
+		{[]string{"--limit", "2", "--page", "2"}, []int64{3, 4}},
+		{[]string{"--limit", "2", "--page", "3"}, []int64{5}},
+		{[]string{"--limit", "2", "--page", "4"}, []int64{}},
+		{[]string{"--limit", "2", "--desc"}, []int64{5, 4}},
+		{[]string{"--limit", "2", "--page", "2", "--desc"}, []int64{3, 2}},
+		{[]string{"--limit", "0", "--page", "3"}, []int64{1, 2, 3, 4, 5}},
+		{[]string{"--limit", "1", "--page", "2", "--type", ".exe"}, []int64{3}},
+	}
+	for _, tt := range tests {
+		out, code := runEvents(t, append([]string{"--db", db, "--format", "json"}, tt.args...)...)
+		if code != 0 {
+			t.Errorf("%v: 종료 코드 = %d", tt.args, code)
+			continue
+		}
+		if got := eventIDs(t, out); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: ID = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestEventsCommandTable(t *testing.T) {
+	db := createEventsDB(t)
+
+	out, code := runEvents(t, "--db", db, "--limit", "2", "--page", "2")
+	if code != 0 {
+		t.Fatalf("종료 코드 = %d", code)
+	}
+	lines := strings.Split(out, "\n")
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"ID", "시각", "작업", "유형", "횟수", "SHA256", "경로"}) {
+		t.Errorf("헤더 = %q", lines[0])
+	}
+	// 보완 검사 이벤트는 작업에 *를 붙이고 설명을 덧붙임
+	if fields := strings.Fields(lines[2]); len(fields) != 8 || fields[0] != "4" || fields[3] != "CREATE*" || fields[7] != `C:\Temp\x_y.exe` {
+		t.Errorf("보완 검사 이벤트 행 = %q", lines[2])
+	}
+	for _, want := range []string{"* 서비스가 중지된 동안 변경되어", "전체 5건 중 3-4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("표 출력에 %q가 없습니다:\n%s", want, out)
+		}
+	}
+
+	// 해시는 앞 12자리만, 없으면 -
+	out, _ = runEvents(t, "--db", db, "--limit", "2")
+	if !strings.Contains(out, " "+testSHA256[:12]+" ") || strings.Contains(out, testSHA256) {
+		t.Errorf("짧은 해시가 출력되지 않았습니다:\n%s", out)
+	}
+	if fields := strings.Fields(strings.Split(out, "\n")[2]); len(fields) != 8 || fields[6] != "-" {
+		t.Errorf("해시 없는 행 = %v", fields)
+	}
+
+	out, _ = runEvents(t, "--db", db, "--op", "RENAME")
+	if !strings.Contains(out, "전체 0건 중 조회된 이벤트가 없습니다") {
+		t.Errorf("빈 결과 출력:\n%s", out)
+	}
+}
+
+func TestEventsCommandCSV(t *testing.T) {
+	db := createEventsDB(t)
+
+	out, code := runEvents(t, "--db", db, "--format", "csv", "--path", `C:\Temp`)
+	if code != 0 {
+		t.Fatalf("종료 코드 = %d", code)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV 형식 오류: %v\n%s", err, out)
+	}
+	want := [][]string{
+		{"id", "timestamp", "path", "operation", "file_type", "sha256", "count", "last_timestamp", "offline_detected"},
+		{"4", rfc3339(2, 10), `C:\Temp\x_y.exe`, "CREATE", ".EXE", "", "1", rfc3339(2, 10), "true"},
+		{"5", rfc3339(3, 9), `C:\Temp\xzy.exe`, "WRITE", ".exe", "", "3", rfc3339(3, 11), "false"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("CSV = %q, want %q", rows, want)
+	}
+}
+
+// rfc3339는 2025년 1월 day일 hour시(로컬 시각)를 RFC 3339로 반환합니다
+func rfc3339(day, hour int) string {
+	return time.Date(2025, 1, day, hour, 0, 0, 0, time.Local).Format(time.RFC3339)
+}
+
+func TestEventsCommandJSON(t *testing.T) {
+	db := createEventsDB(t)
+
+	out, code := runEvents(t, "--db", db, "--format", "json", "--sha256", testSHA256)
+	if code != 0 {
+		t.Fatalf("종료 코드 = %d", code)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("JSON 형식 오류: %v\n%s", err, out)
+	}
+	if len(records) != 1 {
+		t.Fatalf("이벤트 = %v, want 1개", records)
+	}
+	want := map[string]interface{}{
+		"id":             float64(1),
+		"timestamp":      rfc3339(1, 9),
+		"path":           `C:\Apps\a.exe`,
+		"operation":      "CREATE",
+		"file_type":      ".exe",
+		"sha256":         testSHA256,
+		"count":          float64(1),
+		"last_timestamp": rfc3339(1, 9),
+	}
+	if !reflect.DeepEqual(records[0], want) {
+		t.Errorf("JSON = %v, want %v", records[0], want)
+	}
+
+	// 결과가 없으면 null이 아닌 빈 배열
+	if out, _ := runEvents(t, "--db", db, "--format", "json", "--op", "RENAME"); strings.TrimSpace(out) != "[]" {
+		t.Errorf("빈 결과 JSON = %q, want []", out)
+	}
+}
+
+func TestEventsCommandErrors(t *testing.T) {
+	db := createEventsDB(t)
+	tests := [][]string{
+		{"--db", db, "--format", "xml"},
+		{"--db", db, "--page", "0"},
+		{"--db", db, "--limit", "-1"},
+		{"--db", db, "--since", "yesterday"},
+		{"--db", filepath.Join(t.TempDir(), "missing.db")},
+		{"--unknown"},
+	}
+	for _, args := range tests {
+		if out, code := runEvents(t, args...); code != 1 || out != "" {
+			t.Errorf("%v: 종료 코드 = %d, 출력 %q, want 1과 출력 없음", args, code, out)
+		}
+	}
+}
+
+func TestParseTimeArg(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		arg  string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"30m", now.Add(-30 * time.Minute)},
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-01-01 15:04", time.Date(2025, 1, 1, 15, 4, 0, 0, time.Local)},
+		{"2025-01-01 15:04:05", time.Date(2025, 1, 1, 15, 4, 5, 0, time.Local)},
+		{"2025-01-01T15:04:05", time.Date(2025, 1, 1, 15, 4, 5, 0, time.Local)},
+		{"2025-01-01T15:04:05Z", time.Date(2025, 1, 1, 15, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeArg(tt.arg, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTimeArg(%q) = %v, %v, want %v", tt.arg, got, err, tt.want)
+		}
+	}
+	if _, err := parseTimeArg("01/02/2025", now); err == nil {
+		t.Error("해석할 수 없는 형식은 오류를 반환해야 합니다")
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"windows_service_module/pkg/hashlist"
+	"windows_service_module/pkg/winsvc"
+)
+
+// hashLists는 실행 파일 해시를 비교하는 허용/차단 목록입니다 (hash_lists 설정)
+var hashLists *hashlist.Lists
+
+// initHashLists는 설정의 목록 파일을 읽습니다 (해시 계산이 꺼져 있으면 설정 검증에서 거부).
+// 목록 파일 경로는 서비스를 다시 시작해야 바뀌며, 파일 내용은 변경될 때마다 다시 읽습니다.
+func initHashLists() {
+	hashLists = hashlist.New(config.HashLists)
+	if len(hashLists.Paths()) == 0 {
+		return
+	}
+	reloadHashLists()
+}
+
+// reloadHashLists는 목록 파일을 다시 읽습니다. 읽지 못하면 기존 목록을 계속 사용합니다.
+func reloadHashLists() {
+	stats, err := hashLists.Load()
+	if err != nil {
+		logger.Log(winsvc.LogError, "해시 목록을 로드할 수 없습니다 (기존 목록 유지): %v", err)
+		return
+	}
+	for _, line := range stats.Invalid {
+		logger.Log(winsvc.LogWarning, "해시 목록의 잘못된 줄을 건너뜁니다: %s", line)
+	}
+	logger.LogFields(winsvc.LogInfo, "해시 목록을 로드했습니다",
+		winsvc.F("allowlist", stats.Allowed), winsvc.F("blocklist", stats.Blocked), winsvc.F("invalid", len(stats.Invalid)))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/hashlist"
+	"windows_service_module/pkg/winsvc"
+)
+
+func TestHashListsHotReload(t *testing.T) {
+	const (
+		oldSum = "9b8db510ef42b8ed54a3712636fda55a4f8cfcd5493e20b74ab00cd4f3979f2d"
+		newSum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "block.txt")
+	if err := os.WriteFile(path, []byte(oldSum+" old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	savedConfig, savedLogger, savedLists := config, logger, hashLists
+	defer func() { config, logger, hashLists = savedConfig, savedLogger, savedLists }()
+	cfg := newDefaultConfig()
+	config = &cfg
+	config.HashLists = hashlist.Config{Blocklist: path}
+	logger = winsvc.NewLogger(t.TempDir(), false)
+
+	initHashLists()
+	if _, ok := hashLists.Lookup(oldSum); !ok {
+		t.Fatal("시작 시 목록을 읽지 않았습니다")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requests := make(chan struct{}, 1)
+	if err := watchFiles(ctx, hashLists.Paths(), requests); err != nil {
+		t.Fatal(err)
+	}
+
+	// 편집기처럼 임시 파일에 쓰고 이름을 바꿔 교체해도 감지
+	tmp := filepath.Join(dir, "block.txt.tmp")
+	if err := os.WriteFile(tmp, []byte(newSum+" new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("목록 파일 변경이 감지되지 않았습니다")
+	}
+	reloadHashLists()
+
+	if _, ok := hashLists.Lookup(oldSum); ok {
+		t.Error("이전 항목이 남아 있습니다")
+	}
+	if match, ok := hashLists.Lookup(newSum); !ok || match.Kind != hashlist.Block || match.Label != "new" {
+		t.Errorf("Lookup = %+v, %v, want 새 차단 항목", match, ok)
+	}
+
+	// 목록 파일을 읽을 수 없으면 기존 목록 유지
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	reloadHashLists()
+	if _, ok := hashLists.Lookup(newSum); !ok {
+		t.Error("목록 파일을 읽지 못했는데 기존 목록이 사라졌습니다")
+	}
+}
@@ -0,0 +1,120 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"windows_service_module/pkg/winsvc"
+)
+
+// defaultMonitoringPath는 기본 설정의 감시 경로입니다
+var defaultMonitoringPath = []string{"/home"}
+
+// serviceCommandUsage는 사용법에 표시할 서비스 관리 명령입니다
+var serviceCommandUsage = []commandUsage{
+	{"install", "systemd 유닛 설치"},
+	{"remove", "systemd 유닛 제거"},
+	{"start", "서비스 시작"},
+	{"stop", "서비스 중지"},
+	{"status", "서비스 상태 확인"},
+}
+
+// runCommandUsage는 사용법에 표시할 서비스 실행 명령입니다
+var runCommandUsage = []commandUsage{
+	{"run", "서비스 실행 (systemd에서 호출)"},
+	{"debug", "콘솔에서 서비스 실행"},
+}
+
+// signalControls는 systemd의 SIGTERM(중지)과 SIGHUP(ExecReload 설정 다시 로드), 콘솔의 SIGINT를 서비스 제어 요청으로 바꿉니다.
+// 반환한 함수를 호출하면 시그널 수신을 멈춥니다.
+func signalControls() (<-chan serviceControl, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+
+	controls := make(chan serviceControl)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				c := controlStop
+				if sig == syscall.SIGHUP {
+					c = controlReload
+				}
+				sendUntil(controls, c, done)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return controls, func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+func main() {
+	// 명령 앞의 설정 플래그 해석 (예: --log-path=D:\logs debug)
+	args, err := parseConfigFlags(os.Args[1:])
+	if err != nil {
+		usage(fmt.Sprintf("잘못된 플래그: %v", err))
+	}
+
+	// 설정 확인, 이벤트 조회 등은 서비스 관리자나 기본 설정 생성 없이 수행
+	if code, ok := runOfflineCommand(args); ok {
+		os.Exit(code)
+	}
+
+	setup()
+
+	// 명령행 인자에 따라 다른 동작 수행
+	if len(args) < 1 {
+		usage("명령이 지정되지 않았습니다")
+	}
+
+	cmd := args[0]
+	switch cmd {
+	case "install":
+		err = serviceManager.Install()
+	case "remove":
+		err = serviceManager.Remove()
+	case "start":
+		err = serviceManager.Start()
+	case "stop":
+		err = serviceManager.Stop()
+	case "status":
+		err = serviceManager.Status()
+	case "run", "debug":
+		validateMonitoringPaths()
+		if cmd == "debug" {
+			// 디버그 모드로 실행 (콘솔 출력)
+			logger.SetDebug(true)
+		} else {
+			runAsService = true
+		}
+		// systemd에는 시작 진행 상황을 보고하지 않음
+		controls, stopSignals := signalControls()
+		err = runService(controls, nil, nil)
+		stopSignals()
+		logger.Close()
+	default:
+		usage(fmt.Sprintf("알 수 없는 명령: %s", cmd))
+	}
+
+	var startErr *winsvc.StartupError
+	if errors.As(err, &startErr) {
+		// systemd가 실패한 시작 단계를 알 수 있도록 단계별 종료 코드로 종료
+		log.Printf("명령 실행 중 오류 발생: %v", err)
+		os.Exit(int(startErr.ExitCode))
+	}
+	if err != nil {
+		log.Fatalf("명령 실행 중 오류 발생: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows && !linux
+// +build !windows,!linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultMonitoringPath는 기본 설정의 감시 경로입니다
+var defaultMonitoringPath = []string{"/"}
+
+// 서비스 관리 명령을 지원하지 않으므로 사용법에 표시할 명령이 없습니다
+var serviceCommandUsage, runCommandUsage []commandUsage
+
+func main() {
+	fmt.Println("이 도구는 Windows 또는 Linux(systemd) 운영체제에서만 사용할 수 있습니다.")
+	fmt.Println("이 모듈은 Windows 서비스와 systemd 유닛을 관리하기 위한 것입니다.")
+	os.Exit(1)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/winsvc"
+)
+
+// maintenanceTickInterval은 서비스 루프가 정리 작업 실행 시점을 확인하는 주기입니다
+const maintenanceTickInterval = time.Minute
+
+// archiveDirName은 삭제한 이벤트를 보관하는 디렉토리 이름입니다 (custom_data_path 아래)
+const archiveDirName = "archive"
+
+// maintenanceJob은 이벤트 보관 기간 정리, WAL 체크포인트, VACUUM을 주기적으로 실행합니다.
+// 서비스 루프의 ticker에서 tick을 호출하며, 실행은 별도 고루틴에서 하므로 서비스 제어 요청 처리를 막지 않습니다.
+type maintenanceJob struct {
+	running atomic.Bool
+	wg      sync.WaitGroup
+
+	// 아래 필드는 실행 중인 고루틴 하나만 접근
+	lastPrune  time.Time
+	lastVacuum time.Time
+}
+
+// maintenance는 서비스의 데이터베이스 정리 작업입니다
+var maintenance maintenanceJob
+
+// tick은 주기가 된 정리 작업을 시작합니다. 이전 작업이 아직 실행 중이면 건너뜁니다.
+// 설정은 서비스 루프에서 읽어 전달하므로 설정 다시 로드와 경합하지 않습니다.
+func (j *maintenanceJob) tick(now time.Time, store *eventstore.Store, retention eventstore.RetentionConfig, dataPath string) {
+	if store == nil || !j.running.CompareAndSwap(false, true) {
+		return
+	}
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer j.running.Store(false)
+		j.run(now, store, retention, dataPath)
+	}()
+}
+
+// wait는 실행 중인 정리 작업이 끝날 때까지 기다립니다
+func (j *maintenanceJob) wait() {
+	j.wg.Wait()
+}
+
+func (j *maintenanceJob) run(now time.Time, store *eventstore.Store, retention eventstore.RetentionConfig, dataPath string) {
+	interval := time.Duration(retention.PruneIntervalMinutes) * time.Minute
+	if retention.Enabled() && interval > 0 && now.Sub(j.lastPrune) >= interval {
+		j.lastPrune = now
+		prune(now, store, retention, dataPath)
+
+		start := time.Now()
+		if err := store.Checkpoint(); err != nil {
+			logger.Log(winsvc.LogWarning, "%v", err)
+		} else {
+			logger.LogFields(winsvc.LogInfo, "WAL 체크포인트 완료", winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+		}
+	}
+
+	vacuumInterval := time.Duration(retention.VacuumIntervalHours) * time.Hour
+	if vacuumInterval <= 0 {
+		return
+	}
+	statePath := filepath.Join(dataPath, serviceStateFileName)
+	if j.lastVacuum.IsZero() {
+		// 재시작할 때마다 주기가 처음부터 시작되지 않도록 기준 시각을 상태 파일에 유지
+		if state, err := loadServiceState(statePath); err == nil && !state.LastVacuum.IsZero() {
+			j.lastVacuum = state.LastVacuum
+		} else {
+			j.lastVacuum = now
+			saveLastVacuum(statePath, now)
+		}
+	}
+	if now.Sub(j.lastVacuum) < vacuumInterval {
+		return
+	}
+	j.lastVacuum = now
+
+	start := time.Now()
+	if err := store.Vacuum(); err != nil {
+		logger.Log(winsvc.LogWarning, "%v", err)
+		return
+	}
+	logger.LogFields(winsvc.LogInfo, "데이터베이스 VACUUM 완료", winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+	saveLastVacuum(statePath, now)
+}
+
+// saveLastVacuum은 VACUUM 기준 시각을 상태 파일에 기록합니다
+func saveLastVacuum(statePath string, t time.Time) {
+	_, err := updateServiceState(statePath, func(state *serviceState) {
+		state.LastVacuum = t
+	})
+	if err != nil {
+		logger.Log(winsvc.LogWarning, "VACUUM 시각을 기록할 수 없습니다: %v", err)
+	}
+}
+
+// prune은 보관 기간과 개수 제한을 넘는 이벤트를 모두 삭제할 때까지 나누어 삭제합니다
+func prune(now time.Time, store *eventstore.Store, retention eventstore.RetentionConfig, dataPath string) {
+	var before time.Time
+	if retention.MaxAgeDays > 0 {
+		before = now.AddDate(0, 0, -retention.MaxAgeDays)
+	}
+	archiveDir := ""
+	if retention.Archive {
+		archiveDir = filepath.Join(dataPath, archiveDirName)
+	}
+
+	start := time.Now()
+	deleted := 0
+	for {
+		result, err := store.Prune(before, retention.MaxRows, archiveDir)
+		deleted += result.Deleted
+		if result.Archive != "" {
+			logger.LogFields(winsvc.LogInfo, "삭제할 이벤트를 보관했습니다",
+				winsvc.F("file", result.Archive), winsvc.F("rows", result.Deleted))
+		}
+		if err != nil {
+			logger.Log(winsvc.LogError, "이벤트 정리 실패: %v", err)
+			break
+		}
+		if !result.More {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		logger.LogFields(winsvc.LogInfo, "보관 기간이 지난 이벤트를 삭제했습니다",
+			winsvc.F("deleted", deleted), winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+	} else {
+		logger.LogFields(winsvc.LogDebug, "삭제할 이벤트가 없습니다", winsvc.F("duration_ms", time.Since(start).Milliseconds()))
+	}
+}
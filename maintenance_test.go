@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/winsvc"
+)
+
+// openMaintenanceTestStore는 10년 전부터 하루에 하나씩 n개의 이벤트를 저장한 임시 데이터베이스를 엽니다
+func openMaintenanceTestStore(t *testing.T, n int) *eventstore.Store {
+	t.Helper()
+	store, err := eventstore.Open(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	start := time.Now().AddDate(-10, 0, 0)
+	records := make([]eventstore.Record, n)
+	for i := range records {
+		records[i] = eventstore.Record{Timestamp: start.AddDate(0, 0, i), Path: `C:\Apps\a.exe`, Operation: "CREATE", FileType: ".exe"}
+	}
+	if err := store.Append(records); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := logger
+	logger = winsvc.NewLogger(t.TempDir(), false)
+	t.Cleanup(func() { logger = saved })
+	return store
+}
+
+func countEvents(t *testing.T, store *eventstore.Store) int {
+	t.Helper()
+	n, err := store.Count(eventstore.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMaintenancePrune(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention eventstore.RetentionConfig
+		want      int
+	}{
+		// max_age_days 0은 기간 제한 없음: 아무리 오래된 이벤트도 삭제하지 않음
+		{"제한 없음", eventstore.RetentionConfig{PruneIntervalMinutes: 60}, 10},
+		{"개수 제한만", eventstore.RetentionConfig{MaxRows: 4, PruneIntervalMinutes: 60}, 4},
+		{"기간 제한", eventstore.RetentionConfig{MaxAgeDays: 30, PruneIntervalMinutes: 60}, 0},
+		{"정리 주기 0", eventstore.RetentionConfig{MaxAgeDays: 30}, 10},
+	}
+	for _, tt := range tests {
+		store := openMaintenanceTestStore(t, 10)
+		var job maintenanceJob
+		job.run(time.Now(), store, tt.retention, t.TempDir())
+		if got := countEvents(t, store); got != tt.want {
+			t.Errorf("%s: 남은 이벤트 = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaintenancePruneArchive(t *testing.T) {
+	retention := eventstore.RetentionConfig{MaxAgeDays: 30, PruneIntervalMinutes: 60, Archive: true}
+
+	// 보관 파일을 쓴 뒤에만 삭제
+	store, dataPath := openMaintenanceTestStore(t, 10), t.TempDir()
+	prune(time.Now(), store, retention, dataPath)
+	if got := countEvents(t, store); got != 0 {
+		t.Errorf("남은 이벤트 = %d, want 0", got)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dataPath, archiveDirName, "events-*.jsonl.gz")); len(matches) != 1 {
+		t.Errorf("보관 파일 = %v, want 1개", matches)
+	}
+
+	// 보관 디렉토리를 만들 수 없으면 삭제하지 않음
+	store, dataPath = openMaintenanceTestStore(t, 10), t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataPath, archiveDirName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	prune(time.Now(), store, retention, dataPath)
+	if got := countEvents(t, store); got != 10 {
+		t.Errorf("보관 실패 후 남은 이벤트 = %d, want 10", got)
+	}
+}
@@ -0,0 +1,583 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/hashing"
+	"windows_service_module/pkg/hashlist"
+	"windows_service_module/pkg/metrics"
+	"windows_service_module/pkg/rules"
+	"windows_service_module/pkg/winsvc"
+)
+
+// Config는 모니터링 파이프라인 실행 설정입니다
+type Config struct {
+	ServiceName       string
+	MonitoringPath    []string
+	Filter            *filter.Filter
+	HeartbeatInterval time.Duration    // 0이면 상태 로그를 남기지 않음
+	Metrics           *metrics.Metrics // nil이면 메트릭을 집계하지 않음
+	Recorder          EventRecorder    // 필터를 통과한 이벤트 저장소, nil이면 저장하지 않음
+	Hasher            *hashing.Pool    // 생성/변경된 파일의 SHA-256 계산, nil이면 계산하지 않음
+	HashLists         *hashlist.Lists  // 허용/차단 해시 목록, nil이면 비교하지 않음
+	Coalesce          CoalesceConfig   // 같은 경로의 연속된 이벤트 합치기
+	Rules             *rules.Engine    // 이벤트 규칙, nil이면 평가하지 않음
+	Quarantiner       Quarantiner      // 규칙의 quarantine 동작 처리, nil이면 격리하지 않고 경고만 남김
+}
+
+// ProcessedEvent는 필터를 통과하여 처리를 마친 이벤트입니다.
+// 같은 경로의 연속된 이벤트를 합친 경우 Timestamp는 첫 이벤트 시각입니다.
+type ProcessedEvent struct {
+	Event
+	Count          int       // 합친 이벤트 수 (합치지 않았으면 1)
+	LastTimestamp  time.Time // 마지막 이벤트 시각
+	SHA256         string    // 파일 SHA-256 (계산하지 않았거나 실패하면 빈 문자열)
+	HashError      string    // 해시를 계산하지 못한 사유
+	Alerts         []Alert   // 이벤트에서 발생한 경고
+	Rules          []string  // 일치한 규칙 이름
+	Tags           []string  // 규칙이 붙인 태그
+	Sinks          []string  // 규칙이 지정한 싱크 이름
+	QuarantineRule string    // 격리를 요청한 규칙 이름 (없으면 빈 문자열)
+	// 서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트 (Timestamp는 파일의 수정/생성 시각)
+	OfflineDetected bool
+}
+
+// OfflineTag는 시작 시 보완 검사로 감지한 이벤트에 붙는 태그입니다
+const OfflineTag = "offline-detected"
+
+// Alert는 이벤트 검사에서 발생한 경고입니다 (예: 차단 목록 해시 일치)
+type Alert struct {
+	Level   winsvc.Level // 규칙의 log 동작 외에는 LogWarning 또는 LogError
+	Source  string       // 경고를 만든 검사 (예: "blocklist", "rule")
+	Label   string       // 일치한 항목의 설명
+	Message string
+}
+
+// EventRecorder는 처리한 이벤트를 저장합니다.
+// Record는 이벤트 루프와 해시 작업자 고루틴에서 동시에 호출될 수 있으며 기다리지 않아야 합니다.
+type EventRecorder interface {
+	Record(event ProcessedEvent)
+}
+
+// Quarantiner는 규칙이 격리를 요청한 파일을 보호된 디렉토리로 옮깁니다.
+// 해시 작업자 고루틴에서 호출될 수 있으며, 격리 항목의 ID를 반환합니다.
+// eventSHA256은 이벤트 처리 중 계산한 해시이며, 격리 항목의 해시는 옮긴 파일에서 다시 계산합니다.
+type Quarantiner interface {
+	Quarantine(path, eventSHA256, rule string) (string, error)
+}
+
+// Recorders는 이벤트를 여러 EventRecorder에 차례로 기록합니다 (예: 데이터베이스와 외부 싱크)
+type Recorders []EventRecorder
+
+func (rs Recorders) Record(event ProcessedEvent) {
+	for _, r := range rs {
+		r.Record(event)
+	}
+}
+
+// hashOperations는 해시를 계산하는 작업 종류입니다 (삭제된 파일은 계산할 수 없음)
+var hashOperations = map[string]bool{
+	"CREATE": true,
+	"WRITE":  true,
+}
+
+// eventTimeLayout은 합친 이벤트의 첫/마지막 시각 로그 형식입니다
+const eventTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Logger는 에이전트가 사용하는 로그 출력 인터페이스입니다 (winsvc.Logger 호환)
+type Logger interface {
+	Log(level winsvc.Level, format string, args ...interface{})
+	LogFields(level winsvc.Level, message string, fields ...winsvc.Field)
+}
+
+// State는 모니터링 파이프라인의 실행 상태입니다
+type State string
+
+// 파이프라인 상태 정의
+const (
+	StateStopped State = "stopped" // 시작 전 또는 정상 종료
+	StateRunning State = "running" // 이벤트 수신 중
+	StateFailed  State = "failed"  // 시작 실패 또는 이벤트 공급원 오류로 중단
+)
+
+// Status는 파이프라인의 현재 상태와 처리 통계입니다
+type Status struct {
+	State           State      `json:"state"`
+	StartedAt       time.Time  `json:"started_at"`
+	WatchedPaths    []string   `json:"watched_paths"`
+	Extensions      []string   `json:"extensions"`
+	EventsProcessed uint64     `json:"events_processed"` // 필터를 통과하여 처리한 이벤트 수
+	EventsFiltered  uint64     `json:"events_filtered"`  // 필터에 의해 제외된 이벤트 수
+	LastEventTime   *time.Time `json:"last_event_time,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// Agent는 이벤트 공급원에서 파일 이벤트를 받아 처리하는 모니터링 파이프라인입니다
+type Agent struct {
+	mu     sync.RWMutex
+	config Config
+	source EventSource
+	logger Logger
+
+	// 상태 조회용 정보 (stateMu로 보호, 카운터는 원자적으로 증가)
+	stateMu   sync.Mutex
+	state     State
+	startedAt time.Time
+	lastEvent time.Time
+	lastError string
+	processed atomic.Uint64
+	filtered  atomic.Uint64
+
+	// pending은 합치는 중인 이벤트입니다 (Run 고루틴에서만 사용)
+	pending *coalescer
+}
+
+// New는 새로운 Agent 인스턴스를 생성합니다
+func New(config Config, source EventSource, logger Logger) *Agent {
+	return &Agent{
+		config:  config,
+		source:  source,
+		logger:  logger,
+		state:   StateStopped,
+		pending: newCoalescer(),
+	}
+}
+
+// setState는 파이프라인 상태를 바꾸고 오류가 있으면 기록합니다
+func (a *Agent) setState(state State, err error) {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	a.state = state
+	if state == StateRunning {
+		a.startedAt = time.Now()
+	}
+	if err != nil {
+		a.lastError = err.Error()
+	}
+}
+
+// Status는 파이프라인의 현재 상태를 반환합니다. 다른 고루틴에서 호출해도 안전합니다.
+func (a *Agent) Status() Status {
+	a.mu.RLock()
+	paths := append([]string(nil), a.config.MonitoringPath...)
+	extensions := a.config.Filter.Extensions()
+	a.mu.RUnlock()
+
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	status := Status{
+		State:           a.state,
+		StartedAt:       a.startedAt,
+		WatchedPaths:    paths,
+		Extensions:      extensions,
+		EventsProcessed: a.processed.Load(),
+		EventsFiltered:  a.filtered.Load(),
+		LastError:       a.lastError,
+	}
+	if !a.lastEvent.IsZero() {
+		last := a.lastEvent
+		status.LastEventTime = &last
+	}
+	return status
+}
+
+// Start는 이벤트 공급원에 감시 경로와 필터를 설정하고 모니터링을 시작합니다
+func (a *Agent) Start() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// 모니터링 경로 설정
+	if err := a.source.SetPaths(a.config.MonitoringPath); err != nil {
+		return fmt.Errorf("모니터링 경로 설정 실패: %v", err)
+	}
+
+	// 파일 필터 설정 (하위 모니터는 확장자만, 패턴은 handleEvent에서 확인)
+	a.source.SetFilters(a.config.Filter.Extensions())
+
+	// 모니터링 시작
+	if err := a.source.Start(); err != nil {
+		err = fmt.Errorf("모니터링 시작 실패: %v", err)
+		a.setState(StateFailed, err)
+		return err
+	}
+	a.setState(StateRunning, nil)
+	a.config.Metrics.SetWatchedPaths(len(a.config.MonitoringPath))
+	a.config.Metrics.SetFileTypes(a.config.Filter.Extensions())
+	a.logger.Log(winsvc.LogInfo, "모니터링이 성공적으로 시작되었습니다")
+	return nil
+}
+
+// Run은 ctx가 취소될 때까지 이벤트 루프를 실행한 뒤 이벤트 공급원을 중지합니다
+func (a *Agent) Run(ctx context.Context) error {
+	defer func() {
+		a.source.Stop()
+		a.logger.Log(winsvc.LogInfo, "IO 모니터링이 중지되었습니다.")
+	}()
+
+	a.mu.RLock()
+	serviceName, heartbeatInterval := a.config.ServiceName, a.config.HeartbeatInterval
+	a.mu.RUnlock()
+
+	var heartbeat <-chan time.Time
+	if heartbeatInterval > 0 {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	// 합치는 중인 이벤트의 대기 시간이 끝나면 처리
+	flushTimer := time.NewTimer(time.Hour)
+	flushTimer.Stop()
+	defer flushTimer.Stop()
+	var flush <-chan time.Time
+	scheduleFlush := func() {
+		flushTimer.Stop()
+		flush = nil
+		if deadline, ok := a.pending.next(a.coalesceConfig()); ok {
+			flushTimer.Reset(time.Until(deadline))
+			flush = flushTimer.C
+		}
+	}
+
+	events := a.source.Events()
+	for {
+		select {
+		case <-heartbeat:
+			// 주기적으로 수행할 작업
+			a.logger.Log(winsvc.LogInfo, "서비스 '%s'가 실행 중입니다.", serviceName)
+
+		case event, ok := <-events:
+			if !ok {
+				a.flushPending()
+				err := fmt.Errorf("이벤트 채널이 닫혔습니다")
+				a.setState(StateFailed, err)
+				return err
+			}
+			a.handleEvent(event, time.Now())
+			scheduleFlush()
+
+		case now := <-flush:
+			for _, processed := range a.pending.due(now, a.coalesceConfig()) {
+				a.processEvent(processed)
+			}
+			scheduleFlush()
+
+		case <-ctx.Done():
+			a.flushPending()
+			a.setState(StateStopped, nil)
+			return nil
+		}
+	}
+}
+
+// coalesceConfig는 현재 이벤트 합치기 설정을 반환합니다
+func (a *Agent) coalesceConfig() CoalesceConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.config.Coalesce
+}
+
+// flushPending은 종료 전에 합치는 중인 이벤트를 모두 처리합니다
+func (a *Agent) flushPending() {
+	for _, processed := range a.pending.flush() {
+		a.processEvent(processed)
+	}
+}
+
+// handleEvent는 단일 파일 이벤트를 필터에 비교하고, 합치기가 설정되어 있으면 같은 경로의 대기 중인 이벤트에 합칩니다
+func (a *Agent) handleEvent(event Event, now time.Time) {
+	a.mu.RLock()
+	fileFilter, m, coalesce := a.config.Filter, a.config.Metrics, a.config.Coalesce
+	a.mu.RUnlock()
+
+	if !fileFilter.Match(event.Path) {
+		a.filtered.Add(1)
+		m.ObserveFiltered()
+		a.logger.LogFields(winsvc.LogDebug, "필터에 의해 제외된 이벤트",
+			winsvc.F("path", event.Path),
+			winsvc.F("operation", event.Operation))
+		return
+	}
+
+	a.processed.Add(1)
+	m.ObserveEvent(event.Operation, event.FileType)
+	a.stateMu.Lock()
+	a.lastEvent = time.Now()
+	a.stateMu.Unlock()
+
+	if coalesce.window() > 0 {
+		ready, merged := a.pending.add(event, now)
+		if merged {
+			m.ObserveCoalesced()
+		}
+		for _, processed := range ready {
+			a.processEvent(processed)
+		}
+		return
+	}
+	a.processEvent(ProcessedEvent{Event: event, Count: 1, LastTimestamp: event.Timestamp})
+}
+
+// ProcessOffline은 서비스가 중지된 동안 변경된 파일의 이벤트를 처리합니다 (시작 시 보완 검사).
+// 이벤트 루프를 거치지 않고 호출한 고루틴에서 처리하며, 합치지 않고 OfflineTag를 붙여 기록합니다.
+// 해시 계산 대기열이 가득 차면 자리가 날 때까지 기다리므로 많은 파일을 한꺼번에 넣어도 해시를 빠뜨리지 않습니다.
+// 필터와 일치하지 않으면 false를 반환합니다.
+func (a *Agent) ProcessOffline(ctx context.Context, event Event) bool {
+	a.mu.RLock()
+	fileFilter, m := a.config.Filter, a.config.Metrics
+	a.mu.RUnlock()
+
+	if !fileFilter.Match(event.Path) {
+		return false
+	}
+	a.processed.Add(1)
+	m.ObserveEvent(event.Operation, event.FileType)
+
+	a.process(ctx, ProcessedEvent{Event: event, Count: 1, LastTimestamp: event.Timestamp, OfflineDetected: true}, true)
+	return true
+}
+
+// processEvent는 필터를 통과한 (합친) 이벤트에 규칙을 적용하고, 해시를 계산하여 저장과 로그 기록을 합니다
+func (a *Agent) processEvent(processed ProcessedEvent) {
+	a.process(context.Background(), processed, false)
+}
+
+// process는 processEvent와 ProcessOffline의 공통 처리입니다.
+// wait가 true이면 해시 계산 대기열에 자리가 날 때까지 ctx가 취소되기 전까지 기다립니다.
+func (a *Agent) process(ctx context.Context, processed ProcessedEvent, wait bool) {
+	a.mu.RLock()
+	m, recorder, hasher, lists, engine := a.config.Metrics, a.config.Recorder, a.config.Hasher, a.config.HashLists, a.config.Rules
+	a.mu.RUnlock()
+
+	if engine != nil && !a.applyRules(engine, &processed, m) {
+		return
+	}
+	if processed.OfflineDetected {
+		processed.Tags = append(processed.Tags[:len(processed.Tags):len(processed.Tags)], OfflineTag)
+	}
+
+	if hasher == nil || !hashOperations[strings.ToUpper(processed.Operation)] {
+		a.finishEvent(processed, recorder)
+		return
+	}
+
+	// 해시는 작업자 고루틴에서 계산하고, 계산이 끝나면 그 고루틴에서 저장과 로그 기록을 마침
+	done := func(r hashing.Result) {
+		m.ObserveHash(r.Duration, r.Err)
+		processed.SHA256 = r.SHA256
+		if r.Err != nil {
+			processed.HashError = r.Err.Error()
+		}
+		if lists != nil && r.SHA256 != "" {
+			if match, ok := lists.Lookup(r.SHA256); ok {
+				processed.Alerts = append(processed.Alerts, hashListAlert(match))
+			}
+		}
+		a.finishEvent(processed, recorder)
+	}
+	var submitted bool
+	if wait {
+		submitted = hasher.SubmitWait(ctx, processed.Path, done)
+	} else {
+		submitted = hasher.Submit(processed.Path, done)
+	}
+	if !submitted {
+		err := hashing.ErrQueueFull
+		if wait {
+			err = hashing.ErrStopped
+		}
+		m.ObserveHash(0, err)
+		processed.HashError = err.Error()
+		a.finishEvent(processed, recorder)
+	}
+}
+
+// applyRules는 이벤트와 일치하는 규칙의 동작을 이벤트에 반영합니다. suppress 규칙과 일치하면 false를 반환합니다.
+func (a *Agent) applyRules(engine *rules.Engine, processed *ProcessedEvent, m *metrics.Metrics) bool {
+	event := rules.Event{
+		Path:      processed.Path,
+		Operation: processed.Operation,
+		FileType:  processed.FileType,
+		Time:      processed.Timestamp,
+		Size:      -1,
+	}
+	// 크기 조건이 있는 규칙이 있을 때만 파일 정보를 확인 (삭제된 파일은 크기를 알 수 없음)
+	if engine.NeedsSize() && !strings.EqualFold(processed.Operation, "REMOVE") {
+		if info, err := os.Stat(processed.Path); err == nil && info.Mode().IsRegular() {
+			event.Size = info.Size()
+		}
+	}
+
+	result := engine.Evaluate(event)
+	for _, name := range result.Rules {
+		m.ObserveRule(name)
+	}
+	if result.Suppress {
+		m.ObserveSuppressed()
+		a.logger.LogFields(winsvc.LogDebug, "규칙에 의해 기록하지 않는 이벤트",
+			winsvc.F("path", processed.Path),
+			winsvc.F("operation", processed.Operation),
+			winsvc.F("rules", strings.Join(result.Rules, ",")))
+		return false
+	}
+
+	processed.Rules, processed.Tags, processed.Sinks = result.Rules, result.Tags, result.Sinks
+	processed.QuarantineRule = result.QuarantineRule
+	for _, alert := range result.Alerts {
+		processed.Alerts = append(processed.Alerts, Alert{Level: alert.Level, Source: "rule", Label: alert.Rule, Message: alert.Message})
+	}
+	return true
+}
+
+// hashListAlert는 해시 목록 일치 결과를 경고로 변환합니다.
+// 차단 목록은 ERROR이며, 허용 목록은 알려진 정상 파일이므로 경고로 저장하지 않고 INFO 로그에 목록 이름만 남깁니다.
+func hashListAlert(match hashlist.Match) Alert {
+	if match.Kind == hashlist.Block {
+		return Alert{Level: winsvc.LogError, Source: string(match.Kind), Label: match.Label,
+			Message: "차단 목록에 있는 파일이 발견되었습니다"}
+	}
+	return Alert{Level: winsvc.LogInfo, Source: string(match.Kind), Label: match.Label,
+		Message: "허용 목록에 있는 알려진 파일입니다"}
+}
+
+// finishEvent는 처리한 이벤트를 저장하고 이벤트 로그와 파일 로그에 기록합니다.
+// 경고가 있으면 일반 이벤트 로그 대신 경고마다 해당 수준의 로그를 남깁니다.
+func (a *Agent) finishEvent(processed ProcessedEvent, recorder EventRecorder) {
+	if processed.QuarantineRule != "" {
+		processed.Alerts = append(processed.Alerts, a.quarantine(processed))
+	}
+
+	a.mu.RLock()
+	m := a.config.Metrics
+	a.mu.RUnlock()
+	for _, alert := range processed.Alerts {
+		m.ObserveAlert(alert.Source, alert.Level.String())
+	}
+
+	if recorder != nil {
+		recorder.Record(processed)
+	}
+
+	fields := []winsvc.Field{
+		winsvc.F("path", processed.Path),
+		winsvc.F("file_type", processed.FileType),
+		winsvc.F("operation", processed.Operation),
+	}
+	if processed.SHA256 != "" {
+		fields = append(fields, winsvc.F("sha256", processed.SHA256))
+	}
+	if processed.HashError != "" {
+		fields = append(fields, winsvc.F("hash_error", processed.HashError))
+	}
+	if len(processed.Rules) > 0 {
+		fields = append(fields, winsvc.F("rules", strings.Join(processed.Rules, ",")))
+	}
+	if len(processed.Tags) > 0 {
+		fields = append(fields, winsvc.F("tags", strings.Join(processed.Tags, ",")))
+	}
+	if processed.OfflineDetected {
+		fields = append(fields, winsvc.F("offline_detected", true))
+	}
+	if processed.Count > 1 {
+		fields = append(fields,
+			winsvc.F("count", processed.Count),
+			winsvc.F("first_time", processed.Timestamp.Format(eventTimeLayout)),
+			winsvc.F("last_time", processed.LastTimestamp.Format(eventTimeLayout)))
+	}
+	if len(processed.Alerts) == 0 {
+		a.logger.LogFields(winsvc.LogInfo, "파일 이벤트 발생", fields...)
+		return
+	}
+	for _, alert := range processed.Alerts {
+		alertFields := append(fields[:len(fields):len(fields)], winsvc.F("source", alert.Source))
+		if alert.Label != "" {
+			alertFields = append(alertFields, winsvc.F("label", alert.Label))
+		}
+		a.logger.LogFields(alert.Level, alert.Message, alertFields...)
+	}
+}
+
+// quarantine은 규칙이 격리를 요청한 파일을 격리하고 결과를 경고로 반환합니다
+func (a *Agent) quarantine(processed ProcessedEvent) Alert {
+	a.mu.RLock()
+	q := a.config.Quarantiner
+	a.mu.RUnlock()
+
+	alert := Alert{Level: winsvc.LogWarning, Source: "quarantine", Label: processed.QuarantineRule}
+	switch {
+	case strings.EqualFold(processed.Operation, "REMOVE"):
+		alert.Message = "삭제된 파일은 격리할 수 없습니다"
+	case q == nil:
+		alert.Message = "격리가 설정되지 않아 파일을 격리하지 않았습니다"
+	default:
+		id, err := q.Quarantine(processed.Path, processed.SHA256, processed.QuarantineRule)
+		if err != nil {
+			alert.Level = winsvc.LogError
+			alert.Message = fmt.Sprintf("파일을 격리할 수 없습니다: %v", err)
+		} else {
+			alert.Message = fmt.Sprintf("파일을 격리했습니다 (ID: %s)", id)
+		}
+	}
+	return alert
+}
+
+// Reconfigure는 실행 중인 파이프라인에 새 감시 경로와 필터를 적용합니다
+func (a *Agent) Reconfigure(config Config) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	added, removed := diffPaths(a.config.MonitoringPath, config.MonitoringPath)
+	if len(added) > 0 || len(removed) > 0 {
+		if err := a.source.SetPaths(config.MonitoringPath); err != nil {
+			return fmt.Errorf("모니터링 경로 변경 실패: %v", err)
+		}
+		for _, path := range added {
+			a.logger.Log(winsvc.LogInfo, "감시 경로 추가: %s", path)
+		}
+		for _, path := range removed {
+			a.logger.Log(winsvc.LogInfo, "감시 경로 제거: %s", path)
+		}
+	}
+
+	oldExt := a.config.Filter.Extensions()
+	newExt := config.Filter.Extensions()
+	if strings.Join(oldExt, ",") != strings.Join(newExt, ",") {
+		a.source.SetFilters(newExt)
+		a.logger.Log(winsvc.LogInfo, "감시 확장자 변경: %v -> %v", oldExt, newExt)
+	}
+
+	a.config = config
+	a.config.Metrics.SetWatchedPaths(len(config.MonitoringPath))
+	a.config.Metrics.SetFileTypes(newExt)
+	return nil
+}
+
+// diffPaths는 이전 경로 목록과 새 경로 목록의 차이를 반환합니다
+func diffPaths(oldPaths, newPaths []string) (added, removed []string) {
+	oldSet := make(map[string]bool, len(oldPaths))
+	for _, p := range oldPaths {
+		oldSet[p] = true
+	}
+	newSet := make(map[string]bool, len(newPaths))
+	for _, p := range newPaths {
+		newSet[p] = true
+		if !oldSet[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range oldPaths {
+		if !newSet[p] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/hashing"
+	"windows_service_module/pkg/hashlist"
+	"windows_service_module/pkg/winsvc"
+)
+
+func TestHashListAlert(t *testing.T) {
+	tests := []struct {
+		kind  hashlist.Kind
+		level winsvc.Level
+	}{
+		{hashlist.Block, winsvc.LogError},
+		// 허용 목록은 경고로 저장하지 않도록 WARNING 미만
+		{hashlist.Allow, winsvc.LogInfo},
+	}
+	for _, tt := range tests {
+		alert := hashListAlert(hashlist.Match{Kind: tt.kind, Label: "tool"})
+		if alert.Level != tt.level || alert.Source != string(tt.kind) || alert.Label != "tool" {
+			t.Errorf("%s: alert = %+v, want level %v", tt.kind, alert, tt.level)
+		}
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	dllFilter, err := filter.New(filter.Config{Extensions: []string{".dll"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exeFilter, err := filter.New(filter.Config{Extensions: []string{".exe"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewFakeSource(10)
+	recorder := make(chanRecorder, 10)
+	a := New(Config{MonitoringPath: []string{"/data/a"}, Filter: dllFilter, Recorder: recorder}, source, nopLogger{})
+	if err := a.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	newConfig := Config{MonitoringPath: []string{"/data/a", "/data/b"}, Filter: exeFilter, Recorder: recorder}
+	if err := a.Reconfigure(newConfig); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	source.mu.Lock()
+	paths, filters := source.Paths, source.Filters
+	source.mu.Unlock()
+	if !reflect.DeepEqual(paths, newConfig.MonitoringPath) || !reflect.DeepEqual(filters, []string{".exe"}) {
+		t.Errorf("이벤트 공급원 설정 = %v %v, want %v [.exe]", paths, filters, newConfig.MonitoringPath)
+	}
+	status := a.Status()
+	if !reflect.DeepEqual(status.WatchedPaths, newConfig.MonitoringPath) || !reflect.DeepEqual(status.Extensions, []string{".exe"}) {
+		t.Errorf("Status = %v %v", status.WatchedPaths, status.Extensions)
+	}
+
+	// 새 필터로 이벤트를 거름
+	source.Emit(Event{Path: "/data/b/old.dll", Operation: "CREATE"})
+	source.Emit(Event{Path: "/data/b/new.exe", Operation: "CREATE"})
+	if event := receive(t, recorder); event.Path != "/data/b/new.exe" {
+		t.Errorf("기록된 이벤트 = %s, want /data/b/new.exe", event.Path)
+	}
+
+	// 경로가 같으면 이벤트 공급원의 경로를 다시 설정하지 않음
+	source.SetPaths(nil)
+	if err := a.Reconfigure(newConfig); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	source.mu.Lock()
+	paths = source.Paths
+	source.mu.Unlock()
+	if paths != nil {
+		t.Errorf("경로가 같은데 SetPaths가 호출되었습니다: %v", paths)
+	}
+}
+
+// failingPathsSource는 SetPaths에서 오류를 반환하는 EventSource입니다
+type failingPathsSource struct {
+	*FakeSource
+}
+
+func (failingPathsSource) SetPaths([]string) error {
+	return errors.New("접근 거부")
+}
+
+func TestReconfigurePathError(t *testing.T) {
+	oldFilter, _ := filter.New(filter.Config{Extensions: []string{".dll"}})
+	newFilter, _ := filter.New(filter.Config{Extensions: []string{".exe"}})
+	a := New(Config{MonitoringPath: []string{"/data/a"}, Filter: oldFilter}, failingPathsSource{NewFakeSource(1)}, nopLogger{})
+
+	err := a.Reconfigure(Config{MonitoringPath: []string{"/data/b"}, Filter: newFilter})
+	if err == nil {
+		t.Fatal("경로 변경 실패 시 오류를 반환해야 합니다")
+	}
+	// 실패하면 기존 설정을 유지
+	if status := a.Status(); !reflect.DeepEqual(status.WatchedPaths, []string{"/data/a"}) || !reflect.DeepEqual(status.Extensions, []string{".dll"}) {
+		t.Errorf("Status = %v %v, want 기존 설정", status.WatchedPaths, status.Extensions)
+	}
+}
+
+func TestHashModifiedFile(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "app.exe")
+	if err := os.WriteFile(exe, []byte("MZ old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileFilter, err := filter.New(filter.Config{Extensions: []string{".exe"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher := hashing.NewPool(hashing.Config{Workers: 1, Retries: 5, RetryDelayMs: 50})
+	defer hasher.Close()
+	recorder := make(chanRecorder, 10)
+	a := New(Config{MonitoringPath: []string{dir}, Filter: fileFilter, Recorder: recorder, Hasher: hasher,
+		Coalesce: CoalesceConfig{WindowMs: 100, MaxWaitMs: 1000}}, NewMonitorSource(), nopLogger{})
+	if err := a.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// 실행 중에 기존 실행 파일을 덮어쓰면 새 내용의 해시를 계산
+	content := []byte("MZ patched")
+	if err := os.WriteFile(exe, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	event := receive(t, recorder)
+	if event.Path != exe || event.Operation != "WRITE" || event.SHA256 != hex.EncodeToString(sum[:]) || event.HashError != "" {
+		t.Errorf("기록된 이벤트 = %s %s sha256=%q hash_error=%q, want WRITE와 새 내용의 해시",
+			event.Path, event.Operation, event.SHA256, event.HashError)
+	}
+	select {
+	case extra := <-recorder:
+		t.Errorf("이벤트가 더 기록되었습니다: %s %s", extra.Path, extra.Operation)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CoalesceConfig는 같은 경로의 연속된 이벤트를 하나로 합치는 설정입니다.
+// 설치 프로그램이 파일 하나를 쓰면서 발생시키는 여러 생성/변경 이벤트를 한 번만 기록합니다.
+type CoalesceConfig struct {
+	WindowMs  int `json:"window_ms"`   // 마지막 이벤트 후 이 시간 동안 같은 경로의 이벤트가 없으면 기록, 0이면 합치지 않음
+	MaxWaitMs int `json:"max_wait_ms"` // 이벤트가 계속 발생해도 첫 이벤트 후 이 시간이 지나면 기록
+}
+
+// window는 합치기 대기 시간을 반환합니다 (0이면 합치지 않음)
+func (c CoalesceConfig) window() time.Duration {
+	return time.Duration(c.WindowMs) * time.Millisecond
+}
+
+// maxWait는 첫 이벤트부터 기록까지의 최대 대기 시간을 반환합니다 (window보다 짧으면 window)
+func (c CoalesceConfig) maxWait() time.Duration {
+	if d := time.Duration(c.MaxWaitMs) * time.Millisecond; d > c.window() {
+		return d
+	}
+	return c.window()
+}
+
+// pendingEvent는 합치는 중인 경로 하나의 이벤트입니다
+type pendingEvent struct {
+	event    Event // Timestamp는 첫 이벤트 시각
+	count    int
+	last     time.Time // 마지막 이벤트 시각
+	received time.Time // 마지막 이벤트를 받은 시각 (대기 시간 계산 기준)
+	first    time.Time // 첫 이벤트를 받은 시각
+}
+
+// coalescer는 경로별로 이벤트를 모아 대기 시간이 지나면 하나의 이벤트로 내보냅니다.
+// 이벤트 루프 고루틴에서만 사용하며, 시각을 인자로 받으므로 가짜 시계로 동작을 확인할 수 있습니다.
+type coalescer struct {
+	pending map[string]*pendingEvent
+}
+
+func newCoalescer() *coalescer {
+	return &coalescer{pending: make(map[string]*pendingEvent)}
+}
+
+// add는 이벤트를 같은 경로의 대기 중인 이벤트에 합치고, 기존 이벤트에 합쳐졌으면 true를 반환합니다.
+// 삭제는 대기 중인 생성/변경 이벤트에 합치지 않고, 대기 중이던 이벤트를 바로 처리하도록 반환합니다.
+// 생성 직후 삭제된 파일(드로퍼)도 생성과 삭제가 모두 기록됩니다.
+func (c *coalescer) add(event Event, now time.Time) ([]ProcessedEvent, bool) {
+	p, ok := c.pending[event.Path]
+	if ok && isRemove(event.Operation) && !isRemove(p.event.Operation) {
+		c.pending[event.Path] = &pendingEvent{event: event, count: 1, last: event.Timestamp, received: now, first: now}
+		return sortPending([]*pendingEvent{p}), false
+	}
+	if !ok {
+		c.pending[event.Path] = &pendingEvent{event: event, count: 1, last: event.Timestamp, received: now, first: now}
+		return nil, false
+	}
+	p.count++
+	p.event.Operation = mergeOperation(p.event.Operation, event.Operation)
+	if event.Timestamp.After(p.last) {
+		p.last = event.Timestamp
+	}
+	p.received = now
+	return nil, true
+}
+
+// due는 대기 시간이 지난 이벤트를 첫 이벤트 시각 순서로 꺼냅니다
+func (c *coalescer) due(now time.Time, config CoalesceConfig) []ProcessedEvent {
+	var ready []*pendingEvent
+	for path, p := range c.pending {
+		if now.Sub(p.received) >= config.window() || now.Sub(p.first) >= config.maxWait() {
+			ready = append(ready, p)
+			delete(c.pending, path)
+		}
+	}
+	return sortPending(ready)
+}
+
+// flush는 대기 중인 이벤트를 모두 꺼냅니다 (종료 시)
+func (c *coalescer) flush() []ProcessedEvent {
+	ready := make([]*pendingEvent, 0, len(c.pending))
+	for path, p := range c.pending {
+		ready = append(ready, p)
+		delete(c.pending, path)
+	}
+	return sortPending(ready)
+}
+
+// next는 다음으로 대기 시간이 끝나는 시각을 반환합니다 (대기 중인 이벤트가 없으면 false)
+func (c *coalescer) next(config CoalesceConfig) (time.Time, bool) {
+	var earliest time.Time
+	for _, p := range c.pending {
+		deadline := p.received.Add(config.window())
+		if d := p.first.Add(config.maxWait()); d.Before(deadline) {
+			deadline = d
+		}
+		if earliest.IsZero() || deadline.Before(earliest) {
+			earliest = deadline
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+func sortPending(ready []*pendingEvent) []ProcessedEvent {
+	sort.Slice(ready, func(i, j int) bool { return ready[i].event.Timestamp.Before(ready[j].event.Timestamp) })
+	events := make([]ProcessedEvent, 0, len(ready))
+	for _, p := range ready {
+		events = append(events, ProcessedEvent{Event: p.event, Count: p.count, LastTimestamp: p.last})
+	}
+	return events
+}
+
+// mergeOperation은 합친 이벤트의 작업 종류를 정합니다 (삭제는 add에서 앞의 이벤트와 나누므로 삭제에 이어지는 이벤트만 합침).
+// 새로 생성된 파일에 이어지는 변경은 생성으로 보고, 삭제 후 다시 생성되었으면 생성으로 봅니다.
+func mergeOperation(prev, next string) string {
+	if strings.EqualFold(prev, "CREATE") {
+		return prev
+	}
+	return next
+}
+
+// isRemove는 삭제 이벤트인지 확인합니다
+func isRemove(operation string) bool {
+	return strings.EqualFold(operation, "REMOVE")
+}
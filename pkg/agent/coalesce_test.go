@@ -0,0 +1,229 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/winsvc"
+)
+
+// nopLogger는 로그를 버리는 Logger입니다
+type nopLogger struct{}
+
+func (nopLogger) Log(level winsvc.Level, format string, args ...interface{})           {}
+func (nopLogger) LogFields(level winsvc.Level, message string, fields ...winsvc.Field) {}
+
+// chanRecorder는 기록한 이벤트를 채널로 전달합니다
+type chanRecorder chan ProcessedEvent
+
+func (r chanRecorder) Record(event ProcessedEvent) {
+	r <- event
+}
+
+// startPipeline은 FakeSource로 파이프라인을 실행하고 기록된 이벤트를 받을 채널을 반환합니다
+func startPipeline(t *testing.T, coalesce CoalesceConfig) (*FakeSource, chanRecorder) {
+	t.Helper()
+	fileFilter, err := filter.New(filter.Config{Extensions: []string{".dll"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := NewFakeSource(100)
+	recorder := make(chanRecorder, 100)
+	a := New(Config{Filter: fileFilter, Recorder: recorder, Coalesce: coalesce}, source, nopLogger{})
+	if err := a.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return source, recorder
+}
+
+// receive는 기록된 이벤트 하나를 기다립니다
+func receive(t *testing.T, recorder chanRecorder) ProcessedEvent {
+	t.Helper()
+	select {
+	case event := <-recorder:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("이벤트가 기록되지 않았습니다")
+		return ProcessedEvent{}
+	}
+}
+
+func TestCoalesceBurst(t *testing.T) {
+	source, recorder := startPipeline(t, CoalesceConfig{WindowMs: 50, MaxWaitMs: 2000})
+
+	if !source.EmitBurst(`C:\tools\setup.dll`, 5, 5*time.Millisecond) {
+		t.Fatal("EmitBurst 실패")
+	}
+	event := receive(t, recorder)
+	if event.Path != `C:\tools\setup.dll` || event.Count != 5 || event.Operation != "CREATE" {
+		t.Errorf("합친 이벤트 = %s %s count=%d, want CREATE count=5", event.Path, event.Operation, event.Count)
+	}
+	if !event.LastTimestamp.After(event.Timestamp) {
+		t.Errorf("LastTimestamp %v가 첫 이벤트 시각 %v 이후가 아닙니다", event.LastTimestamp, event.Timestamp)
+	}
+
+	select {
+	case extra := <-recorder:
+		t.Errorf("이벤트가 한 번 더 기록되었습니다: %+v", extra)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestCoalesceDisabled(t *testing.T) {
+	source, recorder := startPipeline(t, CoalesceConfig{})
+
+	source.EmitBurst(`C:\tools\setup.dll`, 3, 0)
+	for i := 0; i < 3; i++ {
+		if event := receive(t, recorder); event.Count != 1 {
+			t.Errorf("합치지 않으면 count=1이어야 합니다: %d", event.Count)
+		}
+	}
+}
+
+func TestCoalescerOperations(t *testing.T) {
+	tests := []struct {
+		ops  []string
+		want []string // 기록되는 이벤트 "작업 종류×이벤트 수"
+	}{
+		{[]string{"CREATE", "WRITE", "WRITE"}, []string{"CREATE×3"}},
+		{[]string{"WRITE", "WRITE"}, []string{"WRITE×2"}},
+		{[]string{"WRITE", "RENAME"}, []string{"RENAME×2"}},
+		// 삭제는 앞의 생성/변경에 합치지 않으므로 생성 직후 삭제된 파일도 생성이 기록됨
+		{[]string{"CREATE", "REMOVE"}, []string{"CREATE×1", "REMOVE×1"}},
+		{[]string{"create", "remove"}, []string{"create×1", "remove×1"}},
+		{[]string{"CREATE", "WRITE", "REMOVE"}, []string{"CREATE×2", "REMOVE×1"}},
+		{[]string{"WRITE", "REMOVE"}, []string{"WRITE×1", "REMOVE×1"}},
+		{[]string{"REMOVE", "REMOVE"}, []string{"REMOVE×2"}},
+		// 삭제 후 다시 생성되었으면 생성
+		{[]string{"WRITE", "REMOVE", "CREATE"}, []string{"WRITE×1", "CREATE×2"}},
+		{[]string{"REMOVE", "CREATE", "WRITE"}, []string{"CREATE×3"}},
+		{[]string{"CREATE", "REMOVE", "CREATE", "REMOVE"}, []string{"CREATE×1", "CREATE×2", "REMOVE×1"}},
+	}
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		c := newCoalescer()
+		var got []string
+		record := func(events []ProcessedEvent) {
+			for _, e := range events {
+				got = append(got, fmt.Sprintf("%s×%d", e.Operation, e.Count))
+			}
+		}
+		for i, op := range tt.ops {
+			now := t0.Add(time.Duration(i) * time.Millisecond)
+			ready, _ := c.add(Event{Path: "a.dll", Operation: op, Timestamp: now}, now)
+			record(ready)
+		}
+		record(c.flush())
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: %v, want %v", tt.ops, got, tt.want)
+		}
+	}
+}
+
+func TestCoalesceCreateRemove(t *testing.T) {
+	source, recorder := startPipeline(t, CoalesceConfig{WindowMs: 50, MaxWaitMs: 2000})
+
+	// 드로퍼가 파일을 만들고 실행한 뒤 바로 삭제
+	path := `C:\Temp\dropper.dll`
+	source.Emit(Event{Path: path, Operation: "CREATE"})
+	source.Emit(Event{Path: path, Operation: "WRITE"})
+	source.Emit(Event{Path: path, Operation: "REMOVE"})
+
+	created := receive(t, recorder)
+	if created.Path != path || created.Operation != "CREATE" || created.Count != 2 {
+		t.Errorf("첫 이벤트 = %s count=%d, want CREATE count=2", created.Operation, created.Count)
+	}
+	removed := receive(t, recorder)
+	if removed.Path != path || removed.Operation != "REMOVE" || removed.Count != 1 {
+		t.Errorf("두 번째 이벤트 = %s count=%d, want REMOVE count=1", removed.Operation, removed.Count)
+	}
+	if removed.Timestamp.Before(created.LastTimestamp) {
+		t.Errorf("삭제 시각 %v가 생성 이벤트의 마지막 시각 %v보다 앞섭니다", removed.Timestamp, created.LastTimestamp)
+	}
+}
+
+func TestCoalescerWindowPerPath(t *testing.T) {
+	config := CoalesceConfig{WindowMs: 50, MaxWaitMs: 1000}
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+
+	c := newCoalescer()
+	c.add(Event{Path: "a.dll", Operation: "CREATE", Timestamp: at(0)}, at(0))
+	c.add(Event{Path: "b.dll", Operation: "WRITE", Timestamp: at(30)}, at(30))
+	if _, merged := c.add(Event{Path: "a.dll", Operation: "WRITE", Timestamp: at(40)}, at(40)); !merged {
+		t.Error("같은 경로의 이벤트는 합쳐야 합니다")
+	}
+
+	// 다음 기록 시각은 b의 대기 시간 끝 (a는 마지막 이벤트 후 다시 대기)
+	if next, ok := c.next(config); !ok || !next.Equal(at(80)) {
+		t.Errorf("next = %v, %v, want %v", next, ok, at(80))
+	}
+	if ready := c.due(at(79), config); len(ready) != 0 {
+		t.Errorf("대기 시간 전에 %d개가 기록되었습니다", len(ready))
+	}
+
+	ready := c.due(at(80), config)
+	if len(ready) != 1 || ready[0].Path != "b.dll" || ready[0].Count != 1 {
+		t.Fatalf("due(80) = %+v, want b.dll 하나", ready)
+	}
+
+	ready = c.due(at(90), config)
+	if len(ready) != 1 || ready[0].Path != "a.dll" || ready[0].Count != 2 || ready[0].Operation != "CREATE" {
+		t.Fatalf("due(90) = %+v, want a.dll CREATE count=2", ready)
+	}
+	if !ready[0].Timestamp.Equal(at(0)) || !ready[0].LastTimestamp.Equal(at(40)) {
+		t.Errorf("시각 = %v ~ %v, want %v ~ %v", ready[0].Timestamp, ready[0].LastTimestamp, at(0), at(40))
+	}
+	if _, ok := c.next(config); ok {
+		t.Error("대기 중인 이벤트가 남아 있습니다")
+	}
+}
+
+func TestCoalescerMaxWait(t *testing.T) {
+	config := CoalesceConfig{WindowMs: 50, MaxWaitMs: 100}
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// 대기 시간보다 짧은 간격으로 계속 변경되어도 첫 이벤트 후 max_wait_ms가 지나면 기록
+	c := newCoalescer()
+	for ms := 0; ms <= 80; ms += 40 {
+		now := t0.Add(time.Duration(ms) * time.Millisecond)
+		c.add(Event{Path: "a.dll", Operation: "WRITE", Timestamp: now}, now)
+	}
+	if ready := c.due(t0.Add(99*time.Millisecond), config); len(ready) != 0 {
+		t.Fatalf("max_wait_ms 전에 기록되었습니다: %+v", ready)
+	}
+	ready := c.due(t0.Add(100*time.Millisecond), config)
+	if len(ready) != 1 || ready[0].Count != 3 {
+		t.Fatalf("due = %+v, want count=3 하나", ready)
+	}
+}
+
+func TestCoalescerFlushOrder(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := newCoalescer()
+	for i, path := range []string{"c.dll", "a.dll", "b.dll"} {
+		ts := t0.Add(time.Duration(i) * time.Second)
+		c.add(Event{Path: path, Operation: "CREATE", Timestamp: ts}, ts)
+	}
+
+	ready := c.flush()
+	var paths []string
+	for _, p := range ready {
+		paths = append(paths, p.Path)
+	}
+	if len(paths) != 3 || paths[0] != "c.dll" || paths[1] != "a.dll" || paths[2] != "b.dll" {
+		t.Errorf("flush 순서 = %v, want 첫 이벤트 시각 순 [c.dll a.dll b.dll]", paths)
+	}
+}
@@ -0,0 +1,315 @@
+package agent
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/yhj0901/windowsIOMonitoring/pkg/monitor"
+)
+
+// Event는 이벤트 공급원이 전달하는 파일 이벤트입니다
+type Event = monitor.FileEvent
+
+// EventSource는 파일 이벤트를 공급하는 인터페이스입니다.
+// SetPaths와 SetFilters는 실행 중에도 호출할 수 있어야 합니다.
+type EventSource interface {
+	SetPaths(paths []string) error
+	SetFilters(filters []string)
+	Start() error
+	Stop()
+	Events() <-chan Event
+}
+
+// MonitorSource는 fsnotify 기반 EventSource 구현입니다.
+// 감시 경로 아래의 디렉토리를 모두 감시에 등록하고 새로 생긴 디렉토리도 추가합니다.
+// 감시 경로가 바뀌면 실행 중인 감시자에 디렉토리를 추가하거나 해제하므로 감시를 다시 시작하지 않으며,
+// 이벤트 채널은 Stop에서 이벤트 처리 고루틴이 끝난 뒤에만 닫습니다.
+type MonitorSource struct {
+	mu      sync.Mutex
+	paths   []string
+	filters map[string]bool   // 전달할 확장자 (소문자), 비어 있으면 모든 파일
+	watcher *fsnotify.Watcher // 실행 중이 아니면 nil
+	watched map[string]bool   // 감시에 등록한 디렉토리
+	done    chan struct{}     // 이벤트 처리 고루틴이 끝나면 닫힘
+	events  chan Event
+	dropped atomic.Uint64 // 채널이 가득 차 버린 이벤트 수
+
+	// 버린 이벤트 요약 로그 (이벤트 처리 고루틴에서만 사용)
+	dropLoggedAt time.Time
+	dropLogged   uint64
+}
+
+// dropLogInterval은 버린 이벤트 요약 로그의 최소 간격입니다
+const dropLogInterval = 10 * time.Second
+
+// NewMonitorSource는 새로운 MonitorSource를 생성합니다
+func NewMonitorSource() *MonitorSource {
+	return &MonitorSource{
+		events: make(chan Event, 100),
+	}
+}
+
+// SetPaths는 감시 경로를 교체합니다. 실행 중이면 추가된 경로를 감시에 등록하고 제거된 경로의 감시를 해제합니다
+func (s *MonitorSource) SetPaths(paths []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldPaths := s.paths
+	s.paths = append([]string(nil), paths...)
+	if s.watcher == nil {
+		return nil
+	}
+
+	// 새 감시 경로 아래에 있지 않은 디렉토리는 감시 해제
+	for dir := range s.watched {
+		if !s.covers(dir) {
+			s.watcher.Remove(dir)
+			delete(s.watched, dir)
+		}
+	}
+
+	var errs []string
+	for _, path := range s.paths {
+		if containsPath(oldPaths, path) {
+			continue
+		}
+		if err := s.watchTree(path); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("감시 경로를 등록할 수 없습니다: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// SetFilters는 전달할 파일 확장자를 교체합니다
+func (s *MonitorSource) SetFilters(filters []string) {
+	set := make(map[string]bool, len(filters))
+	for _, ext := range filters {
+		set[strings.ToLower(ext)] = true
+	}
+
+	s.mu.Lock()
+	s.filters = set
+	s.mu.Unlock()
+}
+
+func (s *MonitorSource) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.watcher != nil {
+		return fmt.Errorf("모니터링이 이미 실행 중입니다")
+	}
+	if len(s.paths) == 0 {
+		return fmt.Errorf("모니터링할 경로가 없습니다")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("파일 시스템 감시자 생성 실패: %v", err)
+	}
+	s.watcher = watcher
+	s.watched = make(map[string]bool)
+	for _, path := range s.paths {
+		if err := s.watchTree(path); err != nil {
+			watcher.Close()
+			s.watcher, s.watched = nil, nil
+			return fmt.Errorf("감시 경로를 등록할 수 없습니다 %s: %v", path, err)
+		}
+	}
+
+	s.done = make(chan struct{})
+	go s.run(watcher, s.done)
+	return nil
+}
+
+// Stop은 감시를 끝내고, 이벤트 처리 고루틴이 남은 이벤트를 전달한 뒤 이벤트 채널을 닫습니다
+func (s *MonitorSource) Stop() {
+	s.mu.Lock()
+	watcher, done := s.watcher, s.done
+	s.watcher, s.watched = nil, nil
+	s.mu.Unlock()
+
+	if watcher == nil {
+		return
+	}
+	// 이벤트 처리 고루틴이 잠금을 기다릴 수 있으므로 잠금 밖에서 닫고 기다림
+	watcher.Close()
+	<-done
+	close(s.events)
+}
+
+func (s *MonitorSource) Events() <-chan Event {
+	return s.events
+}
+
+// run은 감시자가 닫힐 때까지 fsnotify 이벤트를 처리합니다
+func (s *MonitorSource) run(watcher *fsnotify.Watcher, done chan<- struct{}) {
+	defer close(done)
+	defer s.logDropped(time.Now())
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			s.handle(event)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("파일 감시 오류: %v", err)
+		}
+	}
+}
+
+// handle은 fsnotify 이벤트를 파일 이벤트로 바꿔 전달합니다.
+// 새로 생긴 디렉토리는 감시에 등록하고, 삭제되거나 이름이 바뀐 디렉토리는 감시를 해제합니다.
+func (s *MonitorSource) handle(event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+
+	s.mu.Lock()
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		if s.watched[path] {
+			s.unwatchTree(path)
+			s.mu.Unlock()
+			return
+		}
+	}
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Lstat(path); err == nil && info.IsDir() {
+			if s.watcher != nil {
+				if err := s.watchTree(path); err != nil {
+					log.Printf("새 디렉토리를 감시할 수 없습니다: %s - %v", path, err)
+				}
+			}
+			s.mu.Unlock()
+			return
+		}
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	matched := len(s.filters) == 0 || s.filters[ext]
+	s.mu.Unlock()
+
+	var operation string
+	switch {
+	case event.Has(fsnotify.Create):
+		operation = "CREATE"
+	case event.Has(fsnotify.Remove):
+		operation = "REMOVE"
+	case event.Has(fsnotify.Write):
+		// 기존 파일을 덮어쓰면 CREATE 없이 WRITE만 발생 (연속된 WRITE는 에이전트가 합침)
+		operation = "WRITE"
+	default:
+		// 이름 변경은 새 이름의 CREATE로 전달되며, 권한 변경은 전달하지 않음
+		return
+	}
+	if !matched {
+		return
+	}
+
+	fileEvent := Event{
+		Path:      path,
+		Operation: operation,
+		Timestamp: time.Now(),
+		FileType:  ext,
+	}
+	select {
+	case s.events <- fileEvent:
+	default:
+		// 채널이 가득 찬 경우 (논블로킹): 이벤트마다 로그를 남기면 몰릴 때 로그가 넘치므로 요약만 남김
+		s.dropped.Add(1)
+		if now := time.Now(); now.Sub(s.dropLoggedAt) >= dropLogInterval {
+			s.logDropped(now)
+		}
+	}
+}
+
+// logDropped는 마지막 요약 이후 버린 이벤트가 있으면 그 수를 로그로 남깁니다
+func (s *MonitorSource) logDropped(now time.Time) {
+	total := s.dropped.Load()
+	if total == s.dropLogged {
+		return
+	}
+	log.Printf("이벤트 채널이 가득 차 이벤트 %d개를 버렸습니다 (누적 %d개)", total-s.dropLogged, total)
+	s.dropLoggedAt, s.dropLogged = now, total
+}
+
+// watchTree는 잠금을 가진 상태에서 root 아래의 디렉토리를 모두 감시에 등록합니다.
+// root를 등록하지 못하면 오류를 반환하고, 하위 디렉토리의 오류는 로그만 남기고 건너뜁니다.
+func (s *MonitorSource) watchTree(root string) error {
+	root = filepath.Clean(root)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			log.Printf("디렉토리에 접근할 수 없습니다: %s - %v", path, err)
+			return nil
+		}
+		if !d.IsDir() || s.watched[path] {
+			return nil
+		}
+		if err := s.watcher.Add(path); err != nil {
+			if path == root {
+				return err
+			}
+			log.Printf("디렉토리 감시 추가 실패: %s - %v", path, err)
+			return nil
+		}
+		s.watched[path] = true
+		return nil
+	})
+}
+
+// unwatchTree는 잠금을 가진 상태에서 dir과 그 아래 디렉토리의 감시를 해제합니다
+func (s *MonitorSource) unwatchTree(dir string) {
+	for path := range s.watched {
+		if within(path, dir) {
+			// 삭제된 디렉토리는 감시자가 이미 해제했을 수 있으므로 오류는 무시
+			s.watcher.Remove(path)
+			delete(s.watched, path)
+		}
+	}
+}
+
+// covers는 잠금을 가진 상태에서 path가 감시 경로 중 하나의 아래에 있는지 확인합니다
+func (s *MonitorSource) covers(path string) bool {
+	for _, root := range s.paths {
+		if within(path, filepath.Clean(root)) {
+			return true
+		}
+	}
+	return false
+}
+
+// within은 path가 root이거나 root 아래에 있으면 true를 반환합니다 (Windows에서는 대소문자 구분 없음)
+func within(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// containsPath는 경로 목록에 path가 있는지 확인합니다
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Dropped는 처리가 밀려 버린 이벤트 수를 반환합니다
+func (s *MonitorSource) Dropped() uint64 {
+	return s.dropped.Load()
+}
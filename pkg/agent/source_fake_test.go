@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeSource는 테스트용 메모리 기반 EventSource 구현입니다.
+// Emit으로 합성 이벤트를 보내 파일 시스템 없이 필터, 이벤트 합치기, 저장 동작을 확인할 수 있습니다.
+type FakeSource struct {
+	mu      sync.Mutex
+	Paths   []string
+	Filters []string
+	Started bool
+
+	// StartErr를 설정하면 Start에서 오류를 반환합니다
+	StartErr error
+
+	events chan Event
+	done   chan struct{}
+}
+
+// NewFakeSource는 buffer 크기의 이벤트 채널을 가진 FakeSource를 생성합니다
+func NewFakeSource(buffer int) *FakeSource {
+	return &FakeSource{
+		events: make(chan Event, buffer),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *FakeSource) SetPaths(paths []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Paths = append([]string(nil), paths...)
+	return nil
+}
+
+func (s *FakeSource) SetFilters(filters []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Filters = append([]string(nil), filters...)
+}
+
+func (s *FakeSource) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.StartErr != nil {
+		return s.StartErr
+	}
+	if s.Started {
+		return fmt.Errorf("모니터링이 이미 실행 중입니다")
+	}
+	s.Started = true
+	return nil
+}
+
+// Stop은 대기 중인 Emit을 끝냅니다. 이벤트 채널은 닫지 않습니다 (닫힌 채널은 공급원 오류로 처리되므로).
+func (s *FakeSource) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Started {
+		s.Started = false
+		close(s.done)
+	}
+}
+
+func (s *FakeSource) Events() <-chan Event {
+	return s.events
+}
+
+// Emit은 합성 이벤트를 보냅니다. Timestamp가 비어 있으면 현재 시각을, FileType이 비어 있으면 경로의 확장자를 사용합니다.
+// 채널이 가득 차면 이벤트 루프가 받을 때까지 기다리며, Stop 이후에는 false를 반환합니다.
+func (s *FakeSource) Emit(event Event) bool {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.FileType == "" {
+		event.FileType = strings.ToLower(filepath.Ext(event.Path))
+	}
+
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// EmitBurst는 설치 프로그램이 파일 하나를 쓸 때처럼 같은 경로에 대해 생성 후 변경 이벤트를 interval 간격으로 n개 보냅니다
+func (s *FakeSource) EmitBurst(path string, n int, interval time.Duration) bool {
+	for i := 0; i < n; i++ {
+		operation := "WRITE"
+		if i == 0 {
+			operation = "CREATE"
+		}
+		if !s.Emit(Event{Path: path, Operation: operation}) {
+			return false
+		}
+		if i < n-1 {
+			time.Sleep(interval)
+		}
+	}
+	return true
+}
+
+// Fail은 이벤트 채널을 닫아 공급원 오류를 흉내 냅니다. 이후 Emit을 호출하면 안 됩니다.
+func (s *FakeSource) Fail() {
+	close(s.events)
+}
@@ -0,0 +1,264 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startMonitorSource는 paths를 감시하는 MonitorSource를 시작합니다
+func startMonitorSource(t *testing.T, paths ...string) *MonitorSource {
+	t.Helper()
+	s := NewMonitorSource()
+	if err := s.SetPaths(paths); err != nil {
+		t.Fatal(err)
+	}
+	s.SetFilters([]string{".exe", ".dll"})
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// writeFile은 파일을 만들거나 덮어씁니다
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("MZ"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// nextEvent는 path의 op 이벤트가 올 때까지 다른 이벤트를 건너뛰며 기다립니다
+func nextEvent(t *testing.T, s *MonitorSource, path, op string) Event {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				t.Fatal("이벤트 채널이 닫혔습니다")
+			}
+			if event.Path == path && event.Operation == op {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("%s %s 이벤트가 오지 않았습니다", path, op)
+			return Event{}
+		}
+	}
+}
+
+// noEvent는 잠시 기다리는 동안 path의 이벤트가 오지 않는지 확인합니다
+func noEvent(t *testing.T, s *MonitorSource, path string) {
+	t.Helper()
+	timeout := time.After(200 * time.Millisecond)
+	for {
+		select {
+		case event := <-s.Events():
+			if event.Path == path {
+				t.Errorf("이벤트가 전달되었습니다: %+v", event)
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// waitWatched는 디렉토리가 감시에 등록될 때까지 기다립니다
+func waitWatched(t *testing.T, s *MonitorSource, dir string) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		s.mu.Lock()
+		ok := s.watched[dir]
+		s.mu.Unlock()
+		if ok {
+			return
+		}
+	}
+	t.Fatalf("%s가 감시에 등록되지 않았습니다", dir)
+}
+
+func TestMonitorSourceEvents(t *testing.T) {
+	dir := t.TempDir()
+	s := startMonitorSource(t, dir)
+
+	exe := filepath.Join(dir, "setup.exe")
+	writeFile(t, exe)
+	if event := nextEvent(t, s, exe, "CREATE"); event.FileType != ".exe" || event.Timestamp.IsZero() {
+		t.Errorf("CREATE 이벤트 = %+v, want FileType .exe", event)
+	}
+
+	txt := filepath.Join(dir, "readme.txt")
+	writeFile(t, txt)
+	noEvent(t, s, txt)
+
+	// 새로 만든 하위 디렉토리도 감시
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitWatched(t, s, sub)
+	dll := filepath.Join(sub, "lib.dll")
+	writeFile(t, dll)
+	nextEvent(t, s, dll, "CREATE")
+
+	if err := os.Remove(exe); err != nil {
+		t.Fatal(err)
+	}
+	nextEvent(t, s, exe, "REMOVE")
+}
+
+func TestMonitorSourceWrite(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "app.exe")
+	writeFile(t, exe)
+	s := startMonitorSource(t, dir)
+
+	// 기존 파일을 덮어쓰면 CREATE 없이 WRITE
+	f, err := os.OpenFile(exe, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("\x90"))
+	f.Close()
+	if event := nextEvent(t, s, exe, "WRITE"); event.FileType != ".exe" {
+		t.Errorf("WRITE 이벤트 = %+v, want FileType .exe", event)
+	}
+
+	txt := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(txt, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	noEvent(t, s, txt)
+	writeFile(t, txt)
+	noEvent(t, s, txt)
+}
+
+func TestMonitorSourceSetPaths(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	s := startMonitorSource(t, oldDir)
+
+	if err := s.SetPaths([]string{newDir}); err != nil {
+		t.Fatalf("SetPaths: %v", err)
+	}
+	// 감시를 다시 시작하지 않고 이전 경로는 해제, 새 경로는 등록
+	s.mu.Lock()
+	watchedOld, watchedNew := s.watched[oldDir], s.watched[newDir]
+	s.mu.Unlock()
+	if watchedOld || !watchedNew {
+		t.Fatalf("감시 중: 이전 경로 %v, 새 경로 %v, want false, true", watchedOld, watchedNew)
+	}
+
+	oldFile := filepath.Join(oldDir, "a.exe")
+	writeFile(t, oldFile)
+	noEvent(t, s, oldFile)
+
+	newFile := filepath.Join(newDir, "b.exe")
+	writeFile(t, newFile)
+	nextEvent(t, s, newFile, "CREATE")
+
+	if err := s.SetPaths([]string{newDir, filepath.Join(newDir, "missing")}); err == nil {
+		t.Error("없는 경로를 추가하면 오류를 반환해야 합니다")
+	}
+}
+
+func TestMonitorSourceSetPathsDuringEvents(t *testing.T) {
+	dirs := []string{t.TempDir(), t.TempDir()}
+	s := startMonitorSource(t, dirs[0])
+
+	// 파일이 계속 바뀌는 동안 감시 경로를 바꿔도 이벤트 채널이 닫히거나 패닉이 나지 않아야 함
+	stop := make(chan struct{})
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			os.WriteFile(filepath.Join(dirs[i%2], "burst.exe"), []byte("MZ"), 0644)
+		}
+	}()
+	go func() {
+		for range s.Events() {
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		if err := s.SetPaths(dirs[i%2 : i%2+1]); err != nil {
+			t.Fatalf("SetPaths: %v", err)
+		}
+	}
+	close(stop)
+	<-written
+
+	s.Stop()
+	if _, ok := <-s.Events(); ok {
+		t.Error("Stop 후 이벤트 채널이 닫히지 않았습니다")
+	}
+}
+
+func TestMonitorSourceStartErrors(t *testing.T) {
+	s := NewMonitorSource()
+	if err := s.Start(); err == nil {
+		t.Error("감시 경로 없이 Start하면 오류를 반환해야 합니다")
+	}
+
+	s.SetPaths([]string{filepath.Join(t.TempDir(), "missing")})
+	if err := s.Start(); err == nil {
+		t.Error("없는 경로로 Start하면 오류를 반환해야 합니다")
+	}
+
+	s = startMonitorSource(t, t.TempDir())
+	if err := s.Start(); err == nil {
+		t.Error("두 번째 Start는 오류를 반환해야 합니다")
+	}
+}
+
+func TestMonitorSourceDropSummary(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	dir := t.TempDir()
+	s := startMonitorSource(t, dir)
+
+	// 이벤트를 받지 않아 채널이 가득 차도 버린 이벤트마다 로그를 남기지 않음
+	const files = 150
+	for i := 0; i < files; i++ {
+		// 빈 파일을 만들어 파일마다 CREATE 이벤트만 발생
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("f%03d.exe", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+	want := uint64(files - cap(s.events))
+	for deadline := time.Now().Add(2 * time.Second); s.Dropped() < want && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := s.Dropped(); got != want {
+		t.Fatalf("Dropped = %d, want %d", got, want)
+	}
+	s.Stop()
+
+	// 처음 버릴 때 한 번, 종료할 때 나머지를 한 번 요약
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("로그 %d줄, want 2줄:\n%s", len(lines), buf.String())
+	}
+	if summary := fmt.Sprintf("이벤트 %d개를 버렸습니다 (누적 %d개)", want-1, want); !strings.Contains(lines[1], summary) {
+		t.Errorf("요약 로그 = %q, want %q 포함", lines[1], summary)
+	}
+}
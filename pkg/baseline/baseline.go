@@ -0,0 +1,239 @@
+package baseline
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"windows_service_module/pkg/hashing"
+)
+
+// Config는 서비스 실행 중 기준선 비교 설정입니다
+type Config struct {
+	DiffOnStart       bool `json:"diff_on_start"`       // 서비스 시작 시 기준선과 비교
+	DiffIntervalHours int  `json:"diff_interval_hours"` // 주기적으로 비교하는 간격, 0이면 주기 비교 안 함
+}
+
+// File은 감시 경로에서 찾은 파일 하나입니다
+type File struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	SHA256  string    `json:"sha256,omitempty"` // 계산하지 못했으면 빈 문자열
+}
+
+// ScanOptions는 감시 경로를 훑는 설정입니다
+type ScanOptions struct {
+	Match   func(path string) bool // 기록할 파일 (nil이면 모든 파일)
+	Skip    func(path string) bool // 들어가지 않을 디렉토리 (nil이면 모두 탐색)
+	Workers int                    // 동시에 해시를 계산하는 수 (0이면 hashing.DefaultWorkers)
+	MaxSize int64                  // 이보다 큰 파일은 해시를 계산하지 않음 (0이면 제한 없음)
+
+	// Known은 이전 기준선입니다. 크기와 수정 시각이 같은 파일은 해시를 다시 계산하지 않고 기준선의 값을 사용합니다.
+	Known map[string]File
+}
+
+// ScanResult는 감시 경로를 훑은 결과입니다
+type ScanResult struct {
+	Files      []File // 경로 순서
+	Hashed     int    // 해시를 계산한 파일 수
+	Unreadable int    // 읽을 수 없어 건너뛴 파일이나 디렉토리 수 (권한 등)
+	HashErrors int    // 해시를 계산하지 못한 파일 수
+
+	roots   []string // 훑은 감시 경로
+	skipped []string // 읽을 수 없거나 제외되어 들어가지 않은 경로 (그 아래 파일은 확인하지 않음)
+}
+
+// Covers는 path가 이번에 확인한 범위(감시 경로 아래이고, 읽을 수 없거나 제외된 디렉토리 밖)에 있는지 반환합니다.
+// 범위 밖의 기준선 파일은 현재 목록에 없어도 삭제되었다고 볼 수 없습니다.
+func (r ScanResult) Covers(path string) bool {
+	inRoot := false
+	for _, root := range r.roots {
+		if within(path, root) {
+			inRoot = true
+			break
+		}
+	}
+	if !inRoot {
+		return false
+	}
+	for _, dir := range r.skipped {
+		if within(path, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// within은 path가 dir 자체이거나 dir 아래에 있는지 확인합니다 (Windows는 대소문자 무시)
+func within(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	if runtime.GOOS == "windows" {
+		path, dir = strings.ToLower(path), strings.ToLower(dir)
+	}
+	if path == dir {
+		return true
+	}
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, dir)
+}
+
+// Scan은 roots 아래의 파일을 훑어 크기, 수정 시각, SHA-256을 기록합니다.
+// 해시는 Workers개의 고루틴에서 계산하며, ctx가 취소되면 ctx의 오류를 반환합니다.
+func Scan(ctx context.Context, roots []string, opts ScanOptions) (ScanResult, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = hashing.DefaultWorkers
+	}
+
+	result := ScanResult{roots: append([]string(nil), roots...)}
+	var mu sync.Mutex
+	files := make(chan File, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range files {
+				hashed, failed := false, false
+				if known, ok := opts.Known[f.Path]; ok && known.SHA256 != "" && known.Size == f.Size && known.ModTime.Equal(f.ModTime) {
+					f.SHA256 = known.SHA256
+				} else if ctx.Err() == nil {
+					sum, err := hashing.File(f.Path, opts.MaxSize)
+					f.SHA256, hashed, failed = sum, err == nil, err != nil
+				}
+
+				mu.Lock()
+				result.Files = append(result.Files, f)
+				if hashed {
+					result.Hashed++
+				}
+				if failed {
+					result.HashErrors++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var walkErr error
+	for _, root := range roots {
+		walkErr = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				// 권한이 없는 디렉토리 등은 건너뛰고 계속 (없는 감시 경로는 d가 nil)
+				mu.Lock()
+				result.Unreadable++
+				result.skipped = append(result.skipped, path)
+				mu.Unlock()
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && opts.Skip != nil && opts.Skip(path) {
+					mu.Lock()
+					result.skipped = append(result.skipped, path)
+					mu.Unlock()
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || (opts.Match != nil && !opts.Match(path)) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				mu.Lock()
+				result.Unreadable++
+				result.skipped = append(result.skipped, path)
+				mu.Unlock()
+				return nil
+			}
+			files <- File{Path: path, Size: info.Size(), ModTime: info.ModTime()}
+			return nil
+		})
+		if walkErr != nil {
+			break
+		}
+	}
+	close(files)
+	wg.Wait()
+
+	if walkErr != nil {
+		return result, walkErr
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+	return result, nil
+}
+
+// Change 종류
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change는 기준선과 현재 상태의 차이 하나입니다
+type Change struct {
+	Kind string `json:"kind"` // added, removed, changed
+	Path string `json:"path"`
+	Old  *File  `json:"old,omitempty"` // removed, changed
+	New  *File  `json:"new,omitempty"` // added, changed
+}
+
+// Diff는 기준선(old)과 현재 파일 목록(current)을 경로 순서로 비교합니다.
+// 크기가 다르거나 SHA-256이 다르면 변경으로 보며, 해시가 없으면 수정 시각으로 판단합니다
+// (수정 시각만 바뀌고 내용이 같은 파일은 변경으로 보지 않음).
+// covers가 false를 반환하는 기준선 파일(읽을 수 없는 디렉토리나 현재 감시 경로 밖)은 삭제로 보지 않습니다 (nil이면 모두 확인).
+func Diff(old, current []File, covers func(path string) bool) []Change {
+	oldByPath := make(map[string]File, len(old))
+	for _, f := range old {
+		oldByPath[f.Path] = f
+	}
+
+	var changes []Change
+	seen := make(map[string]bool, len(current))
+	for i := range current {
+		cur := current[i]
+		seen[cur.Path] = true
+		prev, ok := oldByPath[cur.Path]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Path: cur.Path, New: &cur})
+			continue
+		}
+		if changed(prev, cur) {
+			changes = append(changes, Change{Kind: Changed, Path: cur.Path, Old: &prev, New: &cur})
+		}
+	}
+	for i := range old {
+		if prev := old[i]; !seen[prev.Path] && (covers == nil || covers(prev.Path)) {
+			changes = append(changes, Change{Kind: Removed, Path: prev.Path, Old: &prev})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func changed(prev, cur File) bool {
+	if prev.Size != cur.Size {
+		return true
+	}
+	if prev.SHA256 != "" && cur.SHA256 != "" {
+		return prev.SHA256 != cur.SHA256
+	}
+	return !prev.ModTime.Equal(cur.ModTime)
+}
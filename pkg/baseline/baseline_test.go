@@ -0,0 +1,110 @@
+package baseline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	old := []File{
+		{Path: "/a/same.exe", Size: 1, ModTime: t0, SHA256: "s"},
+		{Path: "/a/touched.exe", Size: 1, ModTime: t0, SHA256: "t"},
+		{Path: "/a/changed.exe", Size: 1, ModTime: t0, SHA256: "c"},
+		{Path: "/a/resized.exe", Size: 1, ModTime: t0},
+		{Path: "/a/removed.exe", Size: 1, ModTime: t0},
+		{Path: "/locked/hidden.exe", Size: 1, ModTime: t0},
+	}
+	current := []File{
+		{Path: "/a/added.exe", Size: 1, ModTime: t0},
+		{Path: "/a/same.exe", Size: 1, ModTime: t0, SHA256: "s"},
+		{Path: "/a/touched.exe", Size: 1, ModTime: t0.Add(time.Hour), SHA256: "t"}, // 수정 시각만 바뀜
+		{Path: "/a/changed.exe", Size: 1, ModTime: t0, SHA256: "c2"},
+		{Path: "/a/resized.exe", Size: 2, ModTime: t0},
+	}
+
+	kinds := func(changes []Change) []string {
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.Kind+" "+c.Path)
+		}
+		return got
+	}
+	want := []string{
+		"added /a/added.exe",
+		"changed /a/changed.exe",
+		"removed /a/removed.exe",
+		"changed /a/resized.exe",
+		"removed /locked/hidden.exe",
+	}
+	if got := kinds(Diff(old, current, nil)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%q\nwant\n%q", got, want)
+	}
+
+	// 확인하지 못한 경로의 파일은 삭제로 보지 않음
+	covers := func(path string) bool { return !strings.HasPrefix(path, "/locked/") }
+	want = []string{
+		"added /a/added.exe",
+		"changed /a/changed.exe",
+		"removed /a/removed.exe",
+		"changed /a/resized.exe",
+	}
+	if got := kinds(Diff(old, current, covers)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(covers) =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestScanCovers(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.exe", filepath.Join("sub", "b.exe"), filepath.Join("skip", "c.exe"), "notes.txt"} {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	result, err := Scan(context.Background(), []string{root, missing}, ScanOptions{
+		Match: func(path string) bool { return filepath.Ext(path) == ".exe" },
+		Skip:  func(path string) bool { return filepath.Base(path) == "skip" },
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	var paths []string
+	for _, f := range result.Files {
+		paths = append(paths, f.Path)
+		if f.SHA256 == "" {
+			t.Errorf("%s 해시가 없습니다", f.Path)
+		}
+	}
+	if want := []string{filepath.Join(root, "a.exe"), filepath.Join(root, "sub", "b.exe")}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Files = %q, want %q", paths, want)
+	}
+	if result.Unreadable != 1 {
+		t.Errorf("Unreadable = %d, want 1 (없는 감시 경로)", result.Unreadable)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(root, "a.exe"), true},
+		{filepath.Join(root, "sub", "gone.exe"), true},
+		{filepath.Join(root, "skip", "c.exe"), false},       // 제외된 디렉토리
+		{filepath.Join(missing, "x.exe"), false},            // 읽을 수 없는 감시 경로
+		{filepath.Join(root+"-other", "x.exe"), false},      // 이름이 비슷한 다른 디렉토리
+		{filepath.Join(filepath.Dir(root), "x.exe"), false}, // 감시 경로 밖
+	}
+	for _, tt := range tests {
+		if got := result.Covers(tt.path); got != tt.want {
+			t.Errorf("Covers(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
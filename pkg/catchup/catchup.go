@@ -0,0 +1,247 @@
+package catchup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config는 서비스 시작 시 보완 검사 설정입니다
+type Config struct {
+	Enabled bool `json:"enabled"` // 서비스가 중지된 동안 변경된 파일을 시작 시 찾아 이벤트로 기록
+	Workers int  `json:"workers"` // 동시에 디렉토리를 읽는 수 (0이면 DefaultWorkers)
+}
+
+// DefaultWorkers는 기본 동시 디렉토리 읽기 수입니다.
+// 시작 직후 실시간 감시와 디스크를 함께 쓰므로 작게 유지합니다.
+const DefaultWorkers = 2
+
+// 보완 검사로 만든 이벤트의 작업 종류
+const (
+	OpCreate = "CREATE" // 마지막 실행 이후 생성된 파일 (생성 시각을 알 수 있는 경우)
+	OpWrite  = "WRITE"  // 마지막 실행 이후 변경된 파일 (생성인지 변경인지 알 수 없는 경우 포함)
+)
+
+// Options는 보완 검사 설정입니다
+type Options struct {
+	Since   time.Time              // 이 시각 이후 변경된 파일만 찾음
+	Until   time.Time              // 이 시각 이후의 변경은 제외 (실시간 감시를 시작한 시각, 0이면 제한 없음)
+	Match   func(path string) bool // 찾을 파일 (nil이면 모든 파일)
+	Skip    func(path string) bool // 들어가지 않을 디렉토리 (nil이면 모두 탐색)
+	Workers int                    // 동시에 디렉토리를 읽는 수 (0이면 DefaultWorkers)
+}
+
+// Change는 Since와 Until 사이에 변경된 파일 하나입니다
+type Change struct {
+	Path      string
+	Operation string    // OpCreate 또는 OpWrite
+	Time      time.Time // 생성 또는 수정 시각 중 늦은 시각
+}
+
+// Result는 보완 검사 결과입니다
+type Result struct {
+	Dirs       int // 읽은 디렉토리 수
+	Files      int // Match와 일치한 파일 수
+	Changed    int // Since 이후 변경된 파일 수
+	Unreadable int // 읽을 수 없어 건너뛴 파일이나 디렉토리 수 (권한 등)
+}
+
+// Scan은 roots 아래에서 Since 이후 생성되거나 수정된 파일을 찾아 found를 호출합니다.
+// 디렉토리는 Workers개의 고루틴에서 나누어 읽으며 found도 그 고루틴에서 호출되므로,
+// found가 기다리면 검사도 그만큼 늦춰집니다. ctx가 취소되면 ctx의 오류를 반환합니다.
+func Scan(ctx context.Context, roots []string, opts Options, found func(Change)) (Result, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	q := newDirQueue(ctx, nestedRootsRemoved(roots))
+	// 취소되면 기다리는 작업자를 깨움
+	defer context.AfterFunc(ctx, q.wake)()
+
+	var result Result
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				dir, ok := q.pop()
+				if !ok {
+					return
+				}
+				r := scanDir(ctx, dir, opts, q, found)
+				q.done()
+
+				mu.Lock()
+				result.Dirs += r.Dirs
+				result.Files += r.Files
+				result.Changed += r.Changed
+				result.Unreadable += r.Unreadable
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return result, ctx.Err()
+}
+
+// scanDir는 디렉토리 하나를 읽어 하위 디렉토리는 q에 넣고 변경된 파일은 found로 전달합니다
+func scanDir(ctx context.Context, dir string, opts Options, q *dirQueue, found func(Change)) Result {
+	var r Result
+	entries, err := os.ReadDir(dir)
+	if err != nil && len(entries) == 0 {
+		// 권한이 없는 디렉토리 등은 건너뛰고 계속
+		r.Unreadable++
+		return r
+	}
+	r.Dirs++
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return r
+		}
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			if opts.Skip == nil || !opts.Skip(path) {
+				q.push(path)
+			}
+			continue
+		}
+		if !e.Type().IsRegular() || (opts.Match != nil && !opts.Match(path)) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			r.Unreadable++
+			continue
+		}
+		r.Files++
+
+		modified, created := fileTimes(info)
+		change, ok := classify(path, modified, created, opts)
+		if !ok {
+			continue
+		}
+		r.Changed++
+		found(change)
+	}
+	return r
+}
+
+// classify는 파일의 수정/생성 시각이 (Since, Until] 사이이면 변경으로 판단합니다.
+// Until 이후에 생성된 파일은 실시간 감시가 이미 알렸으므로 제외하며, 수정 시각이 Until 이후이면
+// 마지막 수정만 알 수 있어 그 전의 변경 여부를 판단할 수 없으므로 (생성이 아니면) 제외합니다.
+func classify(path string, modified, created time.Time, opts Options) (Change, bool) {
+	inRange := func(t time.Time) bool {
+		return t.After(opts.Since) && (opts.Until.IsZero() || !t.After(opts.Until))
+	}
+	switch {
+	case !opts.Until.IsZero() && created.After(opts.Until):
+		return Change{}, false
+	case inRange(created):
+		// 다른 위치에서 복사하거나 옮긴 파일은 수정 시각이 생성 시각보다 이전일 수 있으므로 늦은 시각을 사용
+		change := Change{Path: path, Operation: OpCreate, Time: created}
+		if inRange(modified) && modified.After(created) {
+			change.Time = modified
+		}
+		return change, true
+	case inRange(modified):
+		return Change{Path: path, Operation: OpWrite, Time: modified}, true
+	}
+	return Change{}, false
+}
+
+// nestedRootsRemoved는 다른 경로 아래에 포함된 경로를 제외하여 같은 파일을 두 번 찾지 않게 합니다
+func nestedRootsRemoved(roots []string) []string {
+	var result []string
+	for i, root := range roots {
+		nested := false
+		for j, other := range roots {
+			r, o := filepath.Clean(root), filepath.Clean(other)
+			// 같은 경로가 여러 번 있으면 처음 것만 사용
+			if i != j && within(r, o) && (!equalPath(r, o) || j < i) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			result = append(result, root)
+		}
+	}
+	return result
+}
+
+// within은 path가 dir이거나 dir 아래에 있는지 확인합니다 (Windows 경로는 대소문자 구분 없음)
+func within(path, dir string) bool {
+	if equalPath(path, dir) {
+		return true
+	}
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	return len(path) > len(dir) && equalPath(path[:len(dir)], dir)
+}
+
+// dirQueue는 작업자들이 나누어 읽을 디렉토리 목록입니다.
+// 읽는 중인 디렉토리가 남아 있으면 하위 디렉토리가 더 들어올 수 있으므로 pop은 기다립니다.
+type dirQueue struct {
+	ctx     context.Context
+	mu      sync.Mutex
+	cond    *sync.Cond
+	dirs    []string
+	pending int // 넣었지만 아직 다 읽지 않은 디렉토리 수
+}
+
+func newDirQueue(ctx context.Context, roots []string) *dirQueue {
+	q := &dirQueue{ctx: ctx, dirs: append([]string(nil), roots...), pending: len(roots)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// wake는 기다리는 작업자를 모두 깨웁니다 (ctx 취소)
+func (q *dirQueue) wake() {
+	q.mu.Lock()
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+// push는 읽을 디렉토리를 추가합니다
+func (q *dirQueue) push(dir string) {
+	q.mu.Lock()
+	q.dirs = append(q.dirs, dir)
+	q.pending++
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// pop은 읽을 디렉토리를 꺼냅니다. 모두 읽었거나 취소되면 false를 반환합니다.
+// 나중에 넣은 디렉토리부터 꺼내므로 (깊이 우선) 목록이 너무 커지지 않습니다.
+func (q *dirQueue) pop() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.dirs) == 0 && q.pending > 0 && q.ctx.Err() == nil {
+		q.cond.Wait()
+	}
+	if len(q.dirs) == 0 || q.ctx.Err() != nil {
+		return "", false
+	}
+	dir := q.dirs[len(q.dirs)-1]
+	q.dirs = q.dirs[:len(q.dirs)-1]
+	return dir, true
+}
+
+// done은 pop으로 꺼낸 디렉토리를 다 읽었음을 알립니다
+func (q *dirQueue) done() {
+	q.mu.Lock()
+	q.pending--
+	finished := q.pending == 0
+	q.mu.Unlock()
+	if finished {
+		q.cond.Broadcast()
+	}
+}
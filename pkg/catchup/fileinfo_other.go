@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package catchup
+
+import (
+	"io/fs"
+	"time"
+)
+
+// fileTimes는 파일의 수정 시각을 반환합니다.
+// 생성 시각은 플랫폼마다 제공 여부가 달라 사용하지 않으며, 보완 검사 이벤트는 모두 WRITE로 기록됩니다.
+func fileTimes(info fs.FileInfo) (modified, created time.Time) {
+	return info.ModTime(), time.Time{}
+}
+
+// equalPath는 두 경로가 같은지 비교합니다
+func equalPath(a, b string) bool {
+	return a == b
+}
@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package catchup
+
+import (
+	"io/fs"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// fileTimes는 파일의 수정 시각과 생성 시각을 반환합니다.
+// 다른 위치에서 복사한 파일은 수정 시각이 원본의 값으로 유지되므로 생성 시각도 함께 확인합니다.
+func fileTimes(info fs.FileInfo) (modified, created time.Time) {
+	modified = info.ModTime()
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		created = time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+	return modified, created
+}
+
+// equalPath는 두 경로가 같은지 대소문자 구분 없이 비교합니다
+func equalPath(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
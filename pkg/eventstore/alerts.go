@@ -0,0 +1,45 @@
+package eventstore
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alert는 alerts 테이블의 행 하나입니다
+type Alert struct {
+	ID        int64     `json:"id"`
+	Timestamp time.Time `json:"timestamp"` // 이벤트 발생 시각
+	Level     string    `json:"level"`     // WARNING, ERROR
+	Source    string    `json:"source"`    // 경고를 만든 검사 (예: blocklist)
+	Label     string    `json:"label,omitempty"`
+	Message   string    `json:"message"`
+	Path      string    `json:"path"`
+	Operation string    `json:"operation"`
+	SHA256    string    `json:"sha256,omitempty"`
+}
+
+// AddAlerts는 경고를 하나의 트랜잭션으로 저장합니다.
+// 경고는 드물게 발생하므로 Writer를 거치지 않고 바로 저장합니다.
+func (s *Store) AddAlerts(alerts []Alert) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, a := range alerts {
+		if a.Timestamp.IsZero() {
+			a.Timestamp = time.Now()
+		}
+		if _, err := tx.Exec(`INSERT INTO alerts (timestamp, level, source, label, message, path, operation, sha256)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			a.Timestamp.Local().Format(storeTimeLayout), a.Level, a.Source, a.Label, a.Message,
+			a.Path, a.Operation, a.SHA256); err != nil {
+			return fmt.Errorf("경고 저장 실패: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("경고 저장 실패: %v", err)
+	}
+	return nil
+}
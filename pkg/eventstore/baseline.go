@@ -0,0 +1,120 @@
+package eventstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoBaseline은 기준선을 아직 만들지 않았음을 나타냅니다
+var ErrNoBaseline = errors.New("기준선이 없습니다 (baseline create로 먼저 만드세요)")
+
+// BaselineFile은 baseline_files 테이블의 행 하나입니다
+type BaselineFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	SHA256  string    `json:"sha256,omitempty"` // 계산하지 못했으면 빈 문자열
+}
+
+// BaselineSnapshot은 기준선을 만든 기록입니다
+type BaselineSnapshot struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Roots     []string  `json:"roots"` // 기준선을 만든 감시 경로
+	Files     int       `json:"files"`
+}
+
+// SaveBaseline은 기존 기준선을 files로 교체하고 기록을 남깁니다 (하나의 트랜잭션)
+func (s *Store) SaveBaseline(roots []string, files []BaselineFile) (BaselineSnapshot, error) {
+	snapshot := BaselineSnapshot{CreatedAt: time.Now(), Roots: roots, Files: len(files)}
+	rootsJSON, err := json.Marshal(roots)
+	if err != nil {
+		return snapshot, fmt.Errorf("기준선 저장 실패: %v", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return snapshot, fmt.Errorf("트랜잭션 시작 실패: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM baseline_files`); err != nil {
+		return snapshot, fmt.Errorf("기준선 저장 실패: %v", err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO baseline_files (path, size, mtime, sha256) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return snapshot, fmt.Errorf("기준선 저장 실패: %v", err)
+	}
+	defer stmt.Close()
+	for _, f := range files {
+		if _, err := stmt.Exec(f.Path, f.Size, f.ModTime.Format(time.RFC3339Nano), f.SHA256); err != nil {
+			return snapshot, fmt.Errorf("기준선 저장 실패 %s: %v", f.Path, err)
+		}
+	}
+
+	result, err := tx.Exec(`INSERT INTO baseline_snapshots (created_at, roots, files) VALUES (?, ?, ?)`,
+		snapshot.CreatedAt.Local().Format(storeTimeLayout), string(rootsJSON), len(files))
+	if err != nil {
+		return snapshot, fmt.Errorf("기준선 기록 실패: %v", err)
+	}
+	if snapshot.ID, err = result.LastInsertId(); err != nil {
+		return snapshot, fmt.Errorf("기준선 기록 실패: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return snapshot, fmt.Errorf("기준선 저장 실패: %v", err)
+	}
+	return snapshot, nil
+}
+
+// Baseline은 마지막 기준선 기록과 파일 목록을 반환합니다. 기준선이 없으면 ErrNoBaseline입니다.
+func (s *Store) Baseline() (BaselineSnapshot, []BaselineFile, error) {
+	var snapshot BaselineSnapshot
+	// 이전 스키마의 데이터베이스를 읽기 전용으로 연 경우 테이블이 없음
+	if ok, err := hasTable(s.db, "baseline_snapshots"); err != nil {
+		return snapshot, nil, err
+	} else if !ok {
+		return snapshot, nil, ErrNoBaseline
+	}
+
+	var createdAt, roots string
+	err := s.db.QueryRow(`SELECT id, created_at, roots, files FROM baseline_snapshots ORDER BY id DESC LIMIT 1`).
+		Scan(&snapshot.ID, &createdAt, &roots, &snapshot.Files)
+	if err == sql.ErrNoRows {
+		return snapshot, nil, ErrNoBaseline
+	}
+	if err != nil {
+		return snapshot, nil, fmt.Errorf("기준선 조회 실패: %v", err)
+	}
+	if snapshot.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return snapshot, nil, err
+	}
+	if err := json.Unmarshal([]byte(roots), &snapshot.Roots); err != nil {
+		return snapshot, nil, fmt.Errorf("기준선 경로 형식 오류: %v", err)
+	}
+
+	rows, err := s.db.Query(`SELECT path, size, mtime, sha256 FROM baseline_files ORDER BY path`)
+	if err != nil {
+		return snapshot, nil, fmt.Errorf("기준선 조회 실패: %v", err)
+	}
+	defer rows.Close()
+
+	var files []BaselineFile
+	for rows.Next() {
+		var f BaselineFile
+		var mtime string
+		if err := rows.Scan(&f.Path, &f.Size, &mtime, &f.SHA256); err != nil {
+			return snapshot, nil, fmt.Errorf("기준선 조회 실패: %v", err)
+		}
+		if f.ModTime, err = time.Parse(time.RFC3339Nano, mtime); err != nil {
+			return snapshot, nil, fmt.Errorf("기준선 수정 시각 형식 오류 %s: %v", f.Path, err)
+		}
+		files = append(files, f)
+	}
+	if err := rows.Err(); err != nil {
+		return snapshot, nil, fmt.Errorf("기준선 조회 실패: %v", err)
+	}
+	return snapshot, files, nil
+}
@@ -0,0 +1,29 @@
+-- 모니터 의존성이 만든 file_events는 path가 UNIQUE여서 같은 파일의 이벤트가 덮어써졌습니다.
+-- 기존 행을 ID와 함께 보존하면서 UNIQUE 제약이 없는 추가 전용 테이블로 교체합니다.
+-- 기존 테이블이 없으면 빈 테이블을 만든 뒤 같은 과정을 거칩니다.
+CREATE TABLE IF NOT EXISTS file_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp DATETIME NOT NULL,
+	path TEXT NOT NULL UNIQUE,
+	operation TEXT NOT NULL,
+	file_type TEXT NOT NULL
+);
+
+ALTER TABLE file_events RENAME TO file_events_legacy;
+
+CREATE TABLE file_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp TEXT NOT NULL,
+	path TEXT NOT NULL,
+	operation TEXT NOT NULL,
+	file_type TEXT NOT NULL
+);
+
+INSERT INTO file_events (id, timestamp, path, operation, file_type)
+	SELECT id, CAST(timestamp AS TEXT), path, operation, file_type
+	FROM file_events_legacy ORDER BY id;
+
+DROP TABLE file_events_legacy;
+
+CREATE INDEX idx_file_events_timestamp ON file_events (timestamp);
+CREATE INDEX idx_file_events_path ON file_events (path);
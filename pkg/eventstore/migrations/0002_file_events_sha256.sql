@@ -0,0 +1,4 @@
+-- 생성/변경된 실행 파일의 SHA-256 (계산하지 않았거나 실패하면 빈 문자열)
+ALTER TABLE file_events ADD COLUMN sha256 TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_file_events_sha256 ON file_events (sha256);
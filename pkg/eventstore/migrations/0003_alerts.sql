@@ -0,0 +1,14 @@
+-- 파일 이벤트 검사(해시 목록 등)에서 발생한 경고
+CREATE TABLE alerts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp TEXT NOT NULL,
+	level TEXT NOT NULL,
+	source TEXT NOT NULL,
+	label TEXT NOT NULL DEFAULT '',
+	message TEXT NOT NULL,
+	path TEXT NOT NULL,
+	operation TEXT NOT NULL,
+	sha256 TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_alerts_timestamp ON alerts (timestamp);
@@ -0,0 +1,4 @@
+-- 같은 경로의 연속된 이벤트를 합친 횟수와 마지막 이벤트 시각
+-- (timestamp는 첫 이벤트 시각, 합치지 않은 이벤트는 event_count 1)
+ALTER TABLE file_events ADD COLUMN event_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE file_events ADD COLUMN last_timestamp TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- 감시 경로의 기준선 (baseline create 시점의 실행 파일 목록)
+-- 기준선은 하나만 유지하며, 다시 만들면 baseline_files를 교체하고 baseline_snapshots에 기록을 추가
+CREATE TABLE baseline_files (
+	path TEXT PRIMARY KEY,
+	size INTEGER NOT NULL,
+	mtime TEXT NOT NULL,
+	sha256 TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE baseline_snapshots (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at TEXT NOT NULL,
+	roots TEXT NOT NULL,
+	files INTEGER NOT NULL
+);
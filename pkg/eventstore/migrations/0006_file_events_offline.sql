@@ -0,0 +1,2 @@
+-- 서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트 표시
+ALTER TABLE file_events ADD COLUMN offline_detected INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,193 @@
+package eventstore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RetentionConfig는 이벤트 보관 기간과 정리 주기 설정입니다
+type RetentionConfig struct {
+	MaxAgeDays           int  `json:"max_age_days"`           // 이보다 오래된 이벤트 삭제, 0이면 기간 제한 없음
+	MaxRows              int  `json:"max_rows"`               // 최신 이벤트를 이 개수만 남김, 0이면 개수 제한 없음
+	PruneIntervalMinutes int  `json:"prune_interval_minutes"` // 정리 작업 주기
+	Archive              bool `json:"archive"`                // 삭제 전에 JSONL.gz 파일로 보관
+	VacuumIntervalHours  int  `json:"vacuum_interval_hours"`  // VACUUM 주기, 0이면 실행하지 않음
+}
+
+// Enabled는 기간 또는 개수 제한이 설정되어 있는지 반환합니다
+func (c RetentionConfig) Enabled() bool {
+	return c.MaxAgeDays > 0 || c.MaxRows > 0
+}
+
+// PruneBatchSize는 Prune 한 번에 삭제하는 최대 행 수입니다.
+// 삭제 트랜잭션이 길어져 이벤트 저장이 밀리지 않도록 나누어 삭제합니다.
+const PruneBatchSize = 50000
+
+// PruneResult는 Prune 한 번의 결과입니다
+type PruneResult struct {
+	Deleted int    // 삭제한 행 수
+	Archive string // 보관 파일 경로 (보관하지 않았으면 빈 문자열)
+	More    bool   // 삭제 대상이 더 남아 있을 수 있음
+}
+
+// Prune은 before보다 오래된 이벤트와, 최신 maxRows개를 넘는 이벤트를 최대 PruneBatchSize개 삭제합니다.
+// before가 0이면 기간 제한을, maxRows가 0이면 개수 제한을 적용하지 않습니다.
+// archiveDir이 비어 있지 않으면 삭제할 행을 먼저 archiveDir의 JSONL.gz 파일로 저장하고,
+// 파일 저장에 실패하면 삭제하지 않습니다.
+func (s *Store) Prune(before time.Time, maxRows int, archiveDir string) (PruneResult, error) {
+	var result PruneResult
+
+	var conds []string
+	var args []interface{}
+	if !before.IsZero() {
+		conds = append(conds, "timestamp < ?")
+		args = append(args, before.Local().Format(storeTimeLayout))
+	}
+	if maxRows > 0 {
+		// 추가 전용 테이블이므로 ID 순서가 저장 순서
+		var cutoff int64
+		err := s.db.QueryRow(`SELECT id FROM file_events ORDER BY id DESC LIMIT 1 OFFSET ?`, maxRows).Scan(&cutoff)
+		switch {
+		case err == nil:
+			conds = append(conds, "id <= ?")
+			args = append(args, cutoff)
+		case err != sql.ErrNoRows:
+			return result, fmt.Errorf("보관 개수 기준 조회 실패: %v", err)
+		}
+	}
+	if len(conds) == 0 {
+		return result, nil
+	}
+	where := " WHERE (" + strings.Join(conds, " OR ") + ")"
+
+	// 삭제 대상의 마지막 ID를 구하고, 보관이 필요하면 같은 범위를 파일로 저장
+	var lastID int64
+	var count int
+	var err error
+	if archiveDir != "" {
+		result.Archive, lastID, count, err = s.archive(where, args, archiveDir)
+	} else {
+		lastID, count, err = s.lastPruneID(where, args)
+	}
+	if err != nil || count == 0 {
+		return result, err
+	}
+
+	res, err := s.db.Exec("DELETE FROM file_events"+where+" AND id <= ?", append(args, lastID)...)
+	if err != nil {
+		return result, fmt.Errorf("이벤트 삭제 실패: %v", err)
+	}
+	result.Deleted = count
+	if n, err := res.RowsAffected(); err == nil {
+		result.Deleted = int(n)
+	}
+	result.More = count >= PruneBatchSize
+	return result, nil
+}
+
+// lastPruneID는 삭제 대상 중 앞쪽 PruneBatchSize개의 마지막 ID와 개수를 반환합니다
+func (s *Store) lastPruneID(where string, args []interface{}) (int64, int, error) {
+	var lastID sql.NullInt64
+	var count int
+	err := s.db.QueryRow(`SELECT MAX(id), COUNT(*) FROM (SELECT id FROM file_events`+where+
+		` ORDER BY id LIMIT ?)`, append(args, PruneBatchSize)...).Scan(&lastID, &count)
+	if err != nil {
+		return 0, 0, fmt.Errorf("삭제 대상 조회 실패: %v", err)
+	}
+	return lastID.Int64, count, nil
+}
+
+// archive는 삭제 대상 중 앞쪽 PruneBatchSize개를 JSONL.gz 파일로 저장하고 파일 경로, 마지막 ID, 개수를 반환합니다.
+// 파일은 임시 이름으로 쓴 뒤 디스크에 반영하고 이름을 바꾸므로 중간에 중단되어도 불완전한 보관 파일이 남지 않습니다.
+func (s *Store) archive(where string, args []interface{}, dir string) (string, int64, int, error) {
+	rows, err := s.db.Query(`SELECT id, CAST(timestamp AS TEXT), path, operation, file_type, sha256, event_count, last_timestamp, offline_detected FROM file_events`+where+
+		` ORDER BY id LIMIT ?`, append(args, PruneBatchSize)...)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("삭제 대상 조회 실패: %v", err)
+	}
+	defer rows.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, 0, fmt.Errorf("보관 디렉토리 생성 실패: %v", err)
+	}
+	path := filepath.Join(dir, "events-"+time.Now().Format("20060102T150405.000")+".jsonl.gz")
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("보관 파일 생성 실패: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(f)
+	bw := bufio.NewWriter(zw)
+	enc := json.NewEncoder(bw)
+
+	var lastID int64
+	count := 0
+	for rows.Next() {
+		r, err := scanRecord(rows)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		if err := enc.Encode(r); err != nil {
+			return "", 0, 0, fmt.Errorf("보관 파일 쓰기 실패: %v", err)
+		}
+		lastID = r.ID
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, 0, fmt.Errorf("이벤트 읽기 실패: %v", err)
+	}
+	if count == 0 {
+		return "", 0, 0, nil
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", 0, 0, fmt.Errorf("보관 파일 쓰기 실패: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, 0, fmt.Errorf("보관 파일 쓰기 실패: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		return "", 0, 0, fmt.Errorf("보관 파일 쓰기 실패: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", 0, 0, fmt.Errorf("보관 파일 쓰기 실패: %v", err)
+	}
+	committed = true
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", 0, 0, fmt.Errorf("보관 파일 이름 변경 실패: %v", err)
+	}
+	return path, lastID, count, nil
+}
+
+// Checkpoint는 WAL 파일의 내용을 데이터베이스 파일에 반영하고 WAL 파일을 비웁니다
+func (s *Store) Checkpoint() error {
+	if _, err := s.db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("WAL 체크포인트 실패: %v", err)
+	}
+	return nil
+}
+
+// Vacuum은 삭제로 생긴 빈 공간을 정리하여 데이터베이스 파일 크기를 줄입니다.
+// 실행 중에는 쓰기가 잠기므로 이벤트 저장은 busy_timeout 범위에서 기다리거나 다음 주기로 미뤄집니다.
+func (s *Store) Vacuum() error {
+	if _, err := s.db.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("VACUUM 실패: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package eventstore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// openPruneTestStore는 2025년 1월 1일부터 하루에 하나씩 n개의 이벤트를 저장한 임시 데이터베이스를 엽니다
+func openPruneTestStore(t *testing.T, n int) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	records := make([]Record, n)
+	for i := range records {
+		records[i] = Record{Timestamp: pruneTestDay(i), Path: `C:\Apps\a.exe`, Operation: "CREATE", FileType: ".exe"}
+	}
+	if err := store.Append(records); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// pruneTestDay는 i번째 이벤트 시각입니다 (2025-01-01 09:00부터 하루 간격, 로컬 시각)
+func pruneTestDay(i int) time.Time {
+	return time.Date(2025, 1, 1+i, 9, 0, 0, 0, time.Local)
+}
+
+// remainingIDs는 남아 있는 이벤트 ID를 순서대로 반환합니다
+func remainingIDs(t *testing.T, store *Store) []int64 {
+	t.Helper()
+	records, err := store.Find(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []int64{}
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  time.Time
+		maxRows int
+		want    []int64
+	}{
+		{"제한 없음", time.Time{}, 0, []int64{1, 2, 3, 4, 5}},
+		{"기간", pruneTestDay(2), 0, []int64{3, 4, 5}},
+		{"개수", time.Time{}, 2, []int64{4, 5}},
+		{"기간 또는 개수", pruneTestDay(1), 3, []int64{3, 4, 5}},
+		{"기간과 개수 모두 여유", pruneTestDay(0), 10, []int64{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		store := openPruneTestStore(t, 5)
+		result, err := store.Prune(tt.before, tt.maxRows, "")
+		if err != nil {
+			t.Errorf("%s: Prune: %v", tt.name, err)
+			continue
+		}
+		if got := remainingIDs(t, store); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: 남은 이벤트 = %v, want %v", tt.name, got, tt.want)
+		}
+		if result.Deleted != 5-len(tt.want) || result.Archive != "" || result.More {
+			t.Errorf("%s: PruneResult = %+v, want 삭제 %d", tt.name, result, 5-len(tt.want))
+		}
+	}
+}
+
+func TestPruneArchive(t *testing.T) {
+	store := openPruneTestStore(t, 5)
+	dir := filepath.Join(t.TempDir(), "archive")
+
+	result, err := store.Prune(pruneTestDay(3), 0, dir)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if result.Deleted != 3 || filepath.Dir(result.Archive) != dir {
+		t.Fatalf("PruneResult = %+v, want 삭제 3, %s의 보관 파일", result, dir)
+	}
+	if got := remainingIDs(t, store); !reflect.DeepEqual(got, []int64{4, 5}) {
+		t.Errorf("남은 이벤트 = %v, want [4 5]", got)
+	}
+
+	// 보관 파일에는 삭제한 행이 모두 들어 있고 임시 파일은 남지 않음
+	f, err := os.Open(result.Archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archived []int64
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("보관 파일 형식 오류: %v", err)
+		}
+		if !r.Timestamp.Equal(pruneTestDay(int(r.ID)-1)) || r.Path != `C:\Apps\a.exe` {
+			t.Errorf("보관된 이벤트 = %+v", r)
+		}
+		archived = append(archived, r.ID)
+	}
+	if !reflect.DeepEqual(archived, []int64{1, 2, 3}) {
+		t.Errorf("보관된 이벤트 = %v, want [1 2 3]", archived)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("임시 파일이 남아 있습니다: %v", matches)
+	}
+
+	// 삭제할 행이 없으면 보관 파일을 만들지 않음
+	result, err = store.Prune(pruneTestDay(3), 0, dir)
+	if err != nil || result.Deleted != 0 || result.Archive != "" {
+		t.Errorf("다시 Prune = %+v, %v, want 삭제 없음", result, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("보관 파일 = %d개, want 1개", len(entries))
+	}
+}
+
+func TestPruneArchiveFailure(t *testing.T) {
+	store := openPruneTestStore(t, 5)
+
+	// 보관 디렉토리를 만들 수 없으면 삭제하지 않음
+	notDir := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := store.Prune(pruneTestDay(3), 2, notDir)
+	if err == nil {
+		t.Fatal("보관에 실패했는데 Prune이 오류를 반환하지 않았습니다")
+	}
+	if result.Deleted != 0 || result.Archive != "" {
+		t.Errorf("PruneResult = %+v, want 삭제 없음", result)
+	}
+	if got := remainingIDs(t, store); !reflect.DeepEqual(got, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("남은 이벤트 = %v, want 모두", got)
+	}
+}
@@ -0,0 +1,216 @@
+package eventstore
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles는 스키마 변경 SQL 파일입니다.
+// 파일 이름은 "<버전>_<이름>.sql" 형식이며 (예: 0002_file_hashes.sql) 버전 순서대로 적용됩니다.
+// 이미 배포된 파일은 수정하지 말고 다음 버전의 파일을 추가합니다.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration은 스키마 버전 하나를 올리는 SQL 파일입니다
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus는 스키마 버전 하나의 적용 상태입니다
+type MigrationStatus struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"applied_at,omitempty"`
+}
+
+// migrations는 버전 순서로 정렬된 내장 스키마 변경 목록입니다
+var migrations = mustLoadMigrations()
+
+// LatestVersion은 이 빌드가 지원하는 가장 높은 스키마 버전입니다
+func LatestVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Migrations는 내장 스키마 변경 목록을 반환합니다
+func Migrations() []Migration {
+	return append([]Migration(nil), migrations...)
+}
+
+// mustLoadMigrations는 내장 SQL 파일을 읽습니다. 파일은 빌드 시 포함되므로 형식 오류는 개발 단계의 실수입니다.
+func mustLoadMigrations() []Migration {
+	list, err := loadMigrations()
+	if err != nil {
+		panic(err)
+	}
+	return list
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Migration
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, rest, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 || rest == "" {
+			return nil, fmt.Errorf("스키마 변경 파일 이름 형식 오류: %s (<버전>_<이름>.sql)", name)
+		}
+
+		data, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, Migration{Version: version, Name: rest, SQL: string(data)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	for i, m := range list {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("스키마 버전이 1부터 연속되지 않습니다: %d (%s)", m.Version, m.Name)
+		}
+	}
+	return list, nil
+}
+
+// ensureVersionTable은 적용된 스키마 버전을 기록하는 테이블을 만듭니다
+func ensureVersionTable(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("schema_version 테이블 생성 실패: %v", err)
+	}
+	return nil
+}
+
+// currentVersion은 적용된 가장 높은 스키마 버전을 반환합니다 (없으면 0)
+func currentVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("스키마 버전 조회 실패: %v", err)
+	}
+	return int(version.Int64), nil
+}
+
+// migrate는 아직 적용되지 않은 스키마 변경을 버전마다 하나의 트랜잭션으로 적용하고, 적용한 목록을 반환합니다.
+// 데이터베이스가 이 빌드보다 새로운 버전이면 이전 버전 프로그램이 데이터를 손상시키지 않도록 오류를 반환합니다.
+func migrate(db *sql.DB) ([]Migration, error) {
+	if err := ensureVersionTable(db); err != nil {
+		return nil, err
+	}
+	version, err := currentVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if latest := LatestVersion(); version > latest {
+		return nil, fmt.Errorf("데이터베이스 스키마 버전(%d)이 이 프로그램이 지원하는 버전(%d)보다 높습니다", version, latest)
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return applied, fmt.Errorf("스키마 버전 %d (%s) 적용 실패: %v", m.Version, m.Name, err)
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.Version, m.Name, time.Now().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Migrate는 데이터베이스를 열어 아직 적용되지 않은 스키마 변경을 적용하고, 적용한 목록을 반환합니다
+func Migrate(path string) ([]Migration, error) {
+	db, err := openReadWrite(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return migrate(db)
+}
+
+// SchemaStatus는 내장 스키마 변경마다 적용 여부를 반환합니다. 데이터베이스를 변경하지 않습니다.
+// 데이터베이스에만 기록된 버전(이 빌드보다 새로운 버전)도 목록에 포함합니다.
+func (s *Store) SchemaStatus() ([]MigrationStatus, error) {
+	applied := make(map[int]MigrationStatus)
+
+	exists, err := hasTable(s.db, "schema_version")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		rows, err := s.db.Query(`SELECT version, name, applied_at FROM schema_version ORDER BY version`)
+		if err != nil {
+			return nil, fmt.Errorf("스키마 버전 조회 실패: %v", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var st MigrationStatus
+			var appliedAt string
+			if err := rows.Scan(&st.Version, &st.Name, &appliedAt); err != nil {
+				return nil, fmt.Errorf("스키마 버전 읽기 실패: %v", err)
+			}
+			st.Applied = true
+			st.AppliedAt, _ = time.Parse(time.RFC3339, appliedAt)
+			applied[st.Version] = st
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var list []MigrationStatus
+	for _, m := range migrations {
+		st, ok := applied[m.Version]
+		if !ok {
+			st = MigrationStatus{Version: m.Version, Name: m.Name}
+		}
+		delete(applied, m.Version)
+		list = append(list, st)
+	}
+	for _, st := range applied {
+		list = append(list, st)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// SchemaVersion은 데이터베이스에 적용된 가장 높은 스키마 버전을 반환합니다
+func (s *Store) SchemaVersion() (int, error) {
+	return currentVersion(s.db)
+}
@@ -0,0 +1,318 @@
+package eventstore
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createLegacyDB는 모니터 의존성이 만들던 스키마(path UNIQUE, schema_version 없음)의 데이터베이스를 만듭니다.
+// 같은 경로를 다시 기록하면 행이 교체되므로 ID에 빈 번호가 생깁니다.
+func createLegacyDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS file_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp DATETIME NOT NULL,
+		path TEXT NOT NULL UNIQUE,
+		operation TEXT NOT NULL,
+		file_type TEXT NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]string{
+		{"2025-01-01 09:00:00", `C:\Apps\a.exe`, "CREATE", ".exe"},
+		{"2025-01-01 09:00:01", `C:\Apps\b.dll`, "CREATE", ".dll"},
+		{"2025-01-01 09:00:02", `C:\Apps\a.exe`, "REMOVE", ".exe"},
+		{"2025-01-01 09:00:03", `C:\Temp\c.exe`, "CREATE", ".exe"},
+	} {
+		if _, err := db.Exec(`INSERT OR REPLACE INTO file_events (timestamp, path, operation, file_type) VALUES (?, ?, ?, ?)`,
+			row[0], row[1], row[2], row[3]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// legacyTime은 이전 스키마에 기록된 2025-01-01 09:00:sec (로컬 시각)입니다
+func legacyTime(sec int) time.Time {
+	return time.Date(2025, 1, 1, 9, 0, sec, 0, time.Local)
+}
+
+func TestMigrateLegacyFileEvents(t *testing.T) {
+	path := createLegacyDB(t)
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	records, err := store.Find(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 기존 행은 ID, 시각과 함께 보존되고 이후 버전에서 추가한 열은 기본값
+	want := []Record{
+		{ID: 2, Timestamp: legacyTime(1), Path: `C:\Apps\b.dll`, Operation: "CREATE", FileType: ".dll", Count: 1, LastTimestamp: legacyTime(1)},
+		{ID: 3, Timestamp: legacyTime(2), Path: `C:\Apps\a.exe`, Operation: "REMOVE", FileType: ".exe", Count: 1, LastTimestamp: legacyTime(2)},
+		{ID: 4, Timestamp: legacyTime(3), Path: `C:\Temp\c.exe`, Operation: "CREATE", FileType: ".exe", Count: 1, LastTimestamp: legacyTime(3)},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("이벤트 = %+v, want %d개", records, len(want))
+	}
+	for i := range want {
+		if !records[i].Timestamp.Equal(want[i].Timestamp) || !records[i].LastTimestamp.Equal(want[i].LastTimestamp) {
+			t.Errorf("이벤트 #%d 시각 = %v %v, want %v", want[i].ID, records[i].Timestamp, records[i].LastTimestamp, want[i].Timestamp)
+		}
+		records[i].Timestamp, records[i].LastTimestamp = want[i].Timestamp, want[i].LastTimestamp
+		if records[i] != want[i] {
+			t.Errorf("이벤트 = %+v, want %+v", records[i], want[i])
+		}
+	}
+
+	// 이전 테이블은 복사 후 삭제
+	if exists, err := hasTable(store.db, "file_events_legacy"); err != nil || exists {
+		t.Errorf("file_events_legacy 테이블이 남아 있습니다 (%v)", err)
+	}
+	if version, err := store.SchemaVersion(); err != nil || version != LatestVersion() {
+		t.Errorf("SchemaVersion = %d, %v, want %d", version, err, LatestVersion())
+	}
+
+	// path UNIQUE 제약이 없어져 같은 경로의 이벤트를 추가하고, ID는 기존 행 다음부터 이어짐
+	if err := store.Append([]Record{{Timestamp: legacyTime(4), Path: `C:\Temp\c.exe`, Operation: "REMOVE", FileType: ".exe"}}); err != nil {
+		t.Fatalf("같은 경로 추가: %v", err)
+	}
+	records, _ = store.Find(Query{PathPrefix: `C:\Temp\c.exe`})
+	if len(records) != 2 || records[0].ID != 4 || records[1].ID != 5 {
+		t.Errorf("같은 경로 이벤트 = %+v, want ID 4, 5", records)
+	}
+	store.Close()
+
+	// 다시 열면 적용할 스키마 변경이 없고 데이터는 그대로
+	applied, err := Migrate(path)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("다시 Migrate = %v, %v, want 적용 없음", applied, err)
+	}
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("다시 Open: %v", err)
+	}
+	defer store.Close()
+	if n, err := store.Count(Query{}); err != nil || n != 4 {
+		t.Errorf("다시 연 뒤 이벤트 수 = %d, %v, want 4", n, err)
+	}
+	var rows int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&rows); err != nil || rows != LatestVersion() {
+		t.Errorf("schema_version 행 = %d, %v, want %d", rows, err, LatestVersion())
+	}
+}
+
+func TestMigrateEmptyDB(t *testing.T) {
+	// 기존 테이블이 없으면 빈 추가 전용 테이블을 만듦
+	store, err := Open(filepath.Join(t.TempDir(), "new.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if n, err := store.Count(Query{}); err != nil || n != 0 {
+		t.Errorf("이벤트 수 = %d, %v, want 0", n, err)
+	}
+	if exists, _ := hasTable(store.db, "file_events_legacy"); exists {
+		t.Error("file_events_legacy 테이블이 남아 있습니다")
+	}
+}
+
+// withMigrations는 테스트 동안 내장 스키마 변경 목록을 list로 바꿉니다
+func withMigrations(t *testing.T, list []Migration) {
+	t.Helper()
+	saved := migrations
+	migrations = list
+	t.Cleanup(func() { migrations = saved })
+}
+
+// openTestDB는 임시 데이터베이스를 읽기/쓰기로 엽니다 (스키마 변경은 적용하지 않음)
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := openReadWrite(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// recordedVersions는 schema_version에 기록된 버전과 이름을 순서대로 반환합니다
+func recordedVersions(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT version, name, applied_at FROM schema_version ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var list []string
+	for rows.Next() {
+		var version int
+		var name, appliedAt string
+		if err := rows.Scan(&version, &name, &appliedAt); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := time.Parse(time.RFC3339, appliedAt); err != nil {
+			t.Errorf("버전 %d applied_at 형식 오류: %v", version, err)
+		}
+		list = append(list, fmt.Sprintf("%d_%s", version, name))
+	}
+	return list
+}
+
+// migrationNames는 스키마 변경 목록을 "<버전>_<이름>" 형식으로 반환합니다
+func migrationNames(list []Migration) []string {
+	var names []string
+	for _, m := range list {
+		names = append(names, fmt.Sprintf("%d_%s", m.Version, m.Name))
+	}
+	return names
+}
+
+func TestLoadMigrations(t *testing.T) {
+	want := []string{
+		"1_append_only_file_events",
+		"2_file_events_sha256",
+		"3_alerts",
+		"4_file_events_coalescing",
+		"5_baseline",
+		"6_file_events_offline",
+	}
+	if got := migrationNames(Migrations()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Migrations = %v, want %v", got, want)
+	}
+	if LatestVersion() != len(want) {
+		t.Errorf("LatestVersion = %d, want %d", LatestVersion(), len(want))
+	}
+}
+
+func TestMigrateOrder(t *testing.T) {
+	db := openTestDB(t)
+
+	applied, err := migrate(db)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	want := migrationNames(Migrations())
+	if got := migrationNames(applied); !reflect.DeepEqual(got, want) {
+		t.Errorf("적용 순서 = %v, want %v", got, want)
+	}
+	if got := recordedVersions(t, db); !reflect.DeepEqual(got, want) {
+		t.Errorf("schema_version = %v, want %v", got, want)
+	}
+
+	// 이미 적용된 버전은 다시 적용하지 않음
+	if applied, err := migrate(db); err != nil || len(applied) != 0 {
+		t.Errorf("다시 migrate = %v, %v, want 적용 없음", migrationNames(applied), err)
+	}
+}
+
+func TestMigrateStepwise(t *testing.T) {
+	db := openTestDB(t)
+	all := Migrations()
+
+	// 이전 빌드가 버전 3까지 적용한 데이터베이스
+	withMigrations(t, all[:3])
+	if _, err := migrate(db); err != nil {
+		t.Fatalf("migrate(1-3): %v", err)
+	}
+	if version, _ := currentVersion(db); version != 3 {
+		t.Fatalf("버전 = %d, want 3", version)
+	}
+
+	// 새 빌드는 남은 버전만 순서대로 적용하고 버전마다 기록
+	migrations = all
+	applied, err := migrate(db)
+	if err != nil {
+		t.Fatalf("migrate(4-6): %v", err)
+	}
+	if got, want := migrationNames(applied), migrationNames(all[3:]); !reflect.DeepEqual(got, want) {
+		t.Errorf("적용 목록 = %v, want %v", got, want)
+	}
+	if got, want := recordedVersions(t, db), migrationNames(all); !reflect.DeepEqual(got, want) {
+		t.Errorf("schema_version = %v, want %v", got, want)
+	}
+}
+
+func TestMigrateRollback(t *testing.T) {
+	db := openTestDB(t)
+	all := Migrations()
+	withMigrations(t, append(all[:len(all):len(all)],
+		Migration{Version: 7, Name: "broken", SQL: `CREATE TABLE partial (id INTEGER);
+			INSERT INTO missing_table VALUES (1);`},
+		Migration{Version: 8, Name: "after", SQL: `CREATE TABLE after_broken (id INTEGER);`},
+	))
+
+	applied, err := migrate(db)
+	if err == nil || !strings.Contains(err.Error(), "스키마 버전 7 (broken) 적용 실패") {
+		t.Fatalf("migrate 오류 = %v, want 버전 7 적용 실패", err)
+	}
+	// 실패 전 버전은 적용된 채로 남고, 실패한 버전의 변경은 모두 되돌리며 이후 버전은 적용하지 않음
+	if got, want := migrationNames(applied), migrationNames(all); !reflect.DeepEqual(got, want) {
+		t.Errorf("적용 목록 = %v, want %v", got, want)
+	}
+	if version, _ := currentVersion(db); version != len(all) {
+		t.Errorf("버전 = %d, want %d", version, len(all))
+	}
+	for _, table := range []string{"partial", "after_broken"} {
+		if exists, _ := hasTable(db, table); exists {
+			t.Errorf("%s 테이블이 만들어졌습니다", table)
+		}
+	}
+
+	// 고친 빌드로 다시 실행하면 실패한 버전부터 적용
+	migrations[len(all)].SQL = `CREATE TABLE partial (id INTEGER);`
+	if applied, err := migrate(db); err != nil || !reflect.DeepEqual(migrationNames(applied), []string{"7_broken", "8_after"}) {
+		t.Errorf("다시 migrate = %v, %v, want [7_broken 8_after]", migrationNames(applied), err)
+	}
+}
+
+func TestOpenRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 이 빌드보다 새로운 프로그램이 기록한 버전
+	newer := LatestVersion() + 1
+	if _, err := store.db.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'future', ?)`,
+		newer, time.Now().Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("스키마 버전(%d)이", newer)) {
+		t.Errorf("Open 오류 = %v, want 스키마 버전이 높다는 오류", err)
+	}
+	if _, err := Migrate(path); err == nil {
+		t.Error("Migrate는 새로운 버전의 데이터베이스를 거부해야 합니다")
+	}
+
+	// 읽기 전용 조회는 가능하고 데이터베이스에만 있는 버전도 상태에 표시
+	store, err = OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	status, err := store.SchemaStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := status[len(status)-1]; len(status) != newer || last.Version != newer || last.Name != "future" || !last.Applied {
+		t.Errorf("SchemaStatus = %+v, want 마지막에 버전 %d future", status, newer)
+	}
+}
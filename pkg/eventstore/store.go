@@ -0,0 +1,366 @@
+package eventstore
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TimeLayout은 file_events.timestamp의 시각 형식입니다 (로컬 시각, 시간대 없음).
+// 이 모듈은 같은 초에 발생한 이벤트의 순서를 보존하도록 밀리초를 덧붙여 저장합니다 (storeTimeLayout).
+const TimeLayout = "2006-01-02 15:04:05"
+
+const storeTimeLayout = TimeLayout + ".000"
+
+// Record는 file_events 테이블의 행 하나입니다
+type Record struct {
+	ID        int64     `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path"`
+	Operation string    `json:"operation"`
+	FileType  string    `json:"file_type"`
+	SHA256    string    `json:"sha256,omitempty"`
+	// 같은 경로의 연속된 이벤트를 합친 경우 이벤트 수와 마지막 이벤트 시각 (Timestamp는 첫 이벤트 시각)
+	Count         int       `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+	// 서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트
+	OfflineDetected bool `json:"offline_detected,omitempty"`
+}
+
+// Store는 파일 이벤트 데이터베이스에 대한 연결입니다
+type Store struct {
+	db *sql.DB
+
+	// sha256Column은 file_events에 sha256 열이 있는지 여부입니다.
+	// 스키마 버전 2 이전의 데이터베이스를 읽기 전용으로 연 경우 false입니다.
+	sha256Column bool
+	// countColumns는 file_events에 event_count, last_timestamp 열이 있는지 여부입니다 (스키마 버전 4 이후)
+	countColumns bool
+	// offlineColumn은 file_events에 offline_detected 열이 있는지 여부입니다 (스키마 버전 6 이후)
+	offlineColumn bool
+}
+
+// Open은 데이터베이스를 읽기/쓰기로 열고 스키마를 최신 버전으로 올립니다.
+func Open(path string) (*Store, error) {
+	db, err := openReadWrite(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db, sha256Column: true, countColumns: true, offlineColumn: true}, nil
+}
+
+// openReadWrite는 데이터베이스 파일을 읽기/쓰기로 엽니다 (없으면 생성).
+// 서비스가 기록하는 동안 events 명령으로 조회할 수 있도록 WAL 모드를 사용합니다.
+func openReadWrite(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("데이터베이스 디렉토리 생성 실패: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("데이터베이스 연결 실패: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("데이터베이스 연결 실패: %v", err)
+	}
+	return db, nil
+}
+
+// OpenReadOnly는 기존 데이터베이스 파일을 읽기 전용으로 엽니다.
+// 다른 서버에서 복사해 온 파일도 그대로 열 수 있으며, 파일이 없으면 새로 만들지 않고 오류를 반환합니다.
+func OpenReadOnly(path string) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("데이터베이스 파일을 열 수 없습니다: %v", err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite URI 파일 이름: Windows 경로는 "/C:/..." 형식으로 지정
+	uriPath := filepath.ToSlash(abs)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+	dsn := "file:" + (&url.URL{Path: uriPath}).EscapedPath() + "?mode=ro"
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("데이터베이스 연결 실패: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("데이터베이스 연결 실패: %v", err)
+	}
+
+	s := &Store{db: db}
+	if s.sha256Column, err = hasColumn(db, "file_events", "sha256"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if s.countColumns, err = hasColumn(db, "file_events", "event_count"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if s.offlineColumn, err = hasColumn(db, "file_events", "offline_detected"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// hasTable은 테이블이 있는지 확인합니다 (이전 스키마의 데이터베이스를 읽기 전용으로 연 경우)
+func hasTable(db *sql.DB, table string) (bool, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("%s 테이블 조회 실패: %v", table, err)
+	}
+	return n > 0, nil
+}
+
+// hasColumn은 테이블에 열이 있는지 확인합니다
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("%s 테이블 구조 조회 실패: %v", table, err)
+	}
+	return n > 0, nil
+}
+
+// Close는 데이터베이스 연결을 닫습니다
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append는 이벤트를 하나의 트랜잭션으로 추가합니다. 기존 행은 변경하지 않습니다.
+func (s *Store) Append(records []Record) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO file_events (timestamp, path, operation, file_type, sha256, event_count, last_timestamp, offline_detected)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("이벤트 저장 준비 실패: %v", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		count, last := r.Count, r.LastTimestamp
+		if count <= 0 {
+			count = 1
+		}
+		if last.IsZero() {
+			last = r.Timestamp
+		}
+		if _, err := stmt.Exec(r.Timestamp.Local().Format(storeTimeLayout), r.Path, r.Operation, r.FileType, r.SHA256,
+			count, last.Local().Format(storeTimeLayout), r.OfflineDetected); err != nil {
+			return fmt.Errorf("이벤트 저장 실패: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("이벤트 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// Query는 이벤트 조회 조건입니다. 비어 있는 조건은 적용하지 않습니다.
+type Query struct {
+	Since      time.Time // 이 시각 이후 (포함)
+	Until      time.Time // 이 시각 이전 (포함하지 않음)
+	PathPrefix string    // 경로 접두사 (대소문자 구분 없음)
+	Operations []string  // 작업 종류 (예: CREATE, REMOVE)
+	FileTypes  []string  // 확장자 (예: .exe)
+	SHA256     string    // 파일 SHA-256 (대소문자 구분 없음)
+	Offline    bool      // 시작 시 보완 검사로 감지한 이벤트만
+	Limit      int       // 최대 행 수, 0이면 제한 없음
+	Offset     int       // 건너뛸 행 수
+	Descending bool      // 최신 이벤트부터 정렬
+}
+
+// where는 조건에 맞는 WHERE 절과 인자를 만듭니다
+func (q Query) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	// 시각은 고정 길이 문자열로 저장되므로 문자열 비교로 범위를 검사
+	if !q.Since.IsZero() {
+		conds = append(conds, "CAST(timestamp AS TEXT) >= ?")
+		args = append(args, q.Since.Local().Format(TimeLayout))
+	}
+	if !q.Until.IsZero() {
+		conds = append(conds, "CAST(timestamp AS TEXT) < ?")
+		args = append(args, q.Until.Local().Format(TimeLayout))
+	}
+	if q.PathPrefix != "" {
+		conds = append(conds, `path LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(q.PathPrefix)+"%")
+	}
+	if len(q.Operations) > 0 {
+		conds = append(conds, "UPPER(operation) IN ("+placeholders(len(q.Operations))+")")
+		for _, op := range q.Operations {
+			args = append(args, strings.ToUpper(op))
+		}
+	}
+	if len(q.FileTypes) > 0 {
+		conds = append(conds, "LOWER(file_type) IN ("+placeholders(len(q.FileTypes))+")")
+		for _, ft := range q.FileTypes {
+			args = append(args, strings.ToLower(ft))
+		}
+	}
+
+	if q.SHA256 != "" {
+		conds = append(conds, "sha256 = ?")
+		args = append(args, strings.ToLower(q.SHA256))
+	}
+	if q.Offline {
+		conds = append(conds, "offline_detected = 1")
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// escapeLike는 LIKE 패턴의 특수 문자(%, _, \)를 이스케이프합니다
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `%`, `\%`)
+	return strings.ReplaceAll(s, `_`, `\_`)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// Find는 조건에 맞는 이벤트를 시각 순서로 반환합니다
+func (s *Store) Find(q Query) ([]Record, error) {
+	where, args := q.where()
+
+	order := "ASC"
+	if q.Descending {
+		order = "DESC"
+	}
+	if !s.canMatch(q) {
+		return nil, nil
+	}
+	query := "SELECT id, CAST(timestamp AS TEXT), path, operation, file_type, " + s.sha256Expr() + ", " + s.countExpr() + ", " + s.offlineExpr() + " FROM file_events" +
+		where + " ORDER BY CAST(timestamp AS TEXT) " + order + ", id " + order
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := q.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite: 제한 없음
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, q.Offset)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("이벤트 조회 실패: %v", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		r, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Count는 Limit/Offset을 제외한 조건에 맞는 이벤트 수를 반환합니다
+func (s *Store) Count(q Query) (int, error) {
+	if !s.canMatch(q) {
+		return 0, nil
+	}
+	where, args := q.where()
+
+	var n int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM file_events"+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("이벤트 수 조회 실패: %v", err)
+	}
+	return n, nil
+}
+
+// canMatch는 조건에 필요한 열이 있는지 확인합니다 (열이 없는 이전 데이터베이스에는 일치하는 이벤트가 없음)
+func (s *Store) canMatch(q Query) bool {
+	return (q.SHA256 == "" || s.sha256Column) && (!q.Offline || s.offlineColumn)
+}
+
+// sha256Expr은 sha256 열을 읽는 SELECT 식입니다 (열이 없는 이전 데이터베이스는 빈 문자열)
+func (s *Store) sha256Expr() string {
+	if s.sha256Column {
+		return "sha256"
+	}
+	return "''"
+}
+
+// countExpr은 event_count, last_timestamp 열을 읽는 SELECT 식입니다 (열이 없는 이전 데이터베이스는 1, 빈 문자열)
+func (s *Store) countExpr() string {
+	if s.countColumns {
+		return "event_count, last_timestamp"
+	}
+	return "1, ''"
+}
+
+// offlineExpr은 offline_detected 열을 읽는 SELECT 식입니다 (열이 없는 이전 데이터베이스는 0)
+func (s *Store) offlineExpr() string {
+	if s.offlineColumn {
+		return "offline_detected"
+	}
+	return "0"
+}
+
+// scanRecord는 "id, timestamp, path, operation, file_type, sha256, event_count, last_timestamp, offline_detected" 순서의 행을 읽습니다
+func scanRecord(rows *sql.Rows) (Record, error) {
+	var r Record
+	var ts, last string
+	if err := rows.Scan(&r.ID, &ts, &r.Path, &r.Operation, &r.FileType, &r.SHA256, &r.Count, &last, &r.OfflineDetected); err != nil {
+		return r, fmt.Errorf("이벤트 읽기 실패: %v", err)
+	}
+	var err error
+	if r.Timestamp, err = parseTimestamp(ts); err != nil {
+		return r, fmt.Errorf("이벤트 #%d 시각 형식 오류: %v", r.ID, err)
+	}
+	r.LastTimestamp = r.Timestamp
+	if last != "" {
+		if r.LastTimestamp, err = parseTimestamp(last); err != nil {
+			return r, fmt.Errorf("이벤트 #%d 시각 형식 오류: %v", r.ID, err)
+		}
+	}
+	return r, nil
+}
+
+// parseTimestamp는 저장된 시각 문자열을 로컬 시각으로 해석합니다.
+// 드라이버가 시간대를 붙여 저장한 경우(RFC 3339)도 처리합니다.
+func parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(TimeLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Local(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("알 수 없는 시각 형식: %q", s)
+}
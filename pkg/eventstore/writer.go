@@ -0,0 +1,128 @@
+package eventstore
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Writer 기본값
+const (
+	DefaultFlushInterval = time.Second
+	DefaultBatchSize     = 200
+	DefaultQueueSize     = 10000
+)
+
+// Writer는 이벤트를 모아 일정 주기 또는 일정 개수마다 한 번에 저장합니다.
+// Record는 큐가 가득 차면 기다리지 않고 이벤트를 버리므로 이벤트 루프를 막지 않습니다.
+type Writer struct {
+	store *Store
+	in    chan Record
+	done  chan struct{}
+
+	// FlushInterval은 모은 이벤트를 저장하는 주기입니다
+	FlushInterval time.Duration
+	// BatchSize는 주기와 관계없이 바로 저장하는 이벤트 수입니다
+	BatchSize int
+	// OnError는 저장 오류를 전달받습니다 (nil이면 무시)
+	OnError func(err error)
+	// ObserveInsert는 저장 한 번의 소요 시간을 전달받습니다 (nil이면 무시)
+	ObserveInsert func(d time.Duration)
+
+	dropped atomic.Uint64
+}
+
+// NewWriter는 store에 저장하는 Writer를 생성합니다. Start로 저장을 시작합니다.
+func NewWriter(store *Store, queueSize int) *Writer {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Writer{
+		store:         store,
+		in:            make(chan Record, queueSize),
+		done:          make(chan struct{}),
+		FlushInterval: DefaultFlushInterval,
+		BatchSize:     DefaultBatchSize,
+	}
+}
+
+// Start는 저장 고루틴을 시작합니다
+func (w *Writer) Start() {
+	go w.run()
+}
+
+// Add는 이벤트를 저장 대기열에 넣습니다. 여러 고루틴에서 동시에 호출할 수 있습니다.
+// ID는 저장할 때 새로 부여되므로 무시됩니다.
+func (w *Writer) Add(r Record) {
+	r.ID = 0
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+
+	select {
+	case w.in <- r:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Dropped는 대기열이 가득 차거나 저장에 계속 실패하여 버린 이벤트 수를 반환합니다
+func (w *Writer) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close는 대기 중인 이벤트를 저장하고 저장 고루틴을 종료합니다. Add 호출이 모두 끝난 뒤에 호출해야 합니다.
+func (w *Writer) Close() {
+	close(w.in)
+	<-w.done
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.FlushInterval)
+	defer ticker.Stop()
+
+	var pending []Record
+	for {
+		select {
+		case r, ok := <-w.in:
+			if !ok {
+				w.flush(&pending)
+				return
+			}
+			pending = append(pending, r)
+			if len(pending) >= w.BatchSize {
+				w.flush(&pending)
+			}
+		case <-ticker.C:
+			w.flush(&pending)
+		}
+	}
+}
+
+// flush는 모은 이벤트를 저장합니다. 실패하면 다음 주기에 다시 시도하되,
+// 데이터베이스가 계속 잠겨 있어도 메모리가 무한히 늘지 않도록 오래된 이벤트부터 버립니다.
+func (w *Writer) flush(pending *[]Record) {
+	if len(*pending) == 0 {
+		return
+	}
+
+	start := time.Now()
+	err := w.store.Append(*pending)
+	if w.ObserveInsert != nil {
+		w.ObserveInsert(time.Since(start))
+	}
+	if err == nil {
+		*pending = (*pending)[:0]
+		return
+	}
+
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+	if limit := cap(w.in); len(*pending) > limit {
+		excess := len(*pending) - limit
+		w.dropped.Add(uint64(excess))
+		*pending = append((*pending)[:0], (*pending)[excess:]...)
+	}
+}
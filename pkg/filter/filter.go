@@ -0,0 +1,264 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DefaultExtensions는 필터가 지정되지 않았을 때 감시할 확장자입니다
+var DefaultExtensions = []string{".exe", ".dll"}
+
+// Config는 service_config.json의 file_filters 설정입니다.
+//
+// 이벤트는 확장자가 Extensions에 포함되거나 Patterns 중 하나와 일치하면 감시 대상이 되고,
+// Exclude 패턴과 일치하면 제외됩니다. 구분자가 없는 패턴은 파일 이름과, 구분자가 있는
+// 패턴은 전체 경로와 비교하며 Exclude 패턴은 상위 디렉토리와 일치해도 제외됩니다.
+// 패턴에는 *, ?, ** 와일드카드와 %VAR% 또는 $VAR 형식의 환경 변수를 사용할 수 있습니다.
+type Config struct {
+	Extensions []string `json:"extensions"`
+	Patterns   []string `json:"patterns"`
+	Exclude    []string `json:"exclude"`
+}
+
+// Filter는 컴파일된 파일 필터입니다
+type Filter struct {
+	extensions map[string]bool
+	patterns   []*pattern
+	exclude    []*pattern
+}
+
+// pattern은 컴파일된 glob 패턴입니다
+type pattern struct {
+	source   string
+	re       *regexp.Regexp
+	baseOnly bool // 파일 이름만 비교
+}
+
+// New는 설정을 검증하고 필터를 컴파일합니다
+func New(config Config) (*Filter, error) {
+	f := &Filter{extensions: make(map[string]bool)}
+
+	extensions := config.Extensions
+	if len(extensions) == 0 && len(config.Patterns) == 0 {
+		extensions = DefaultExtensions
+	}
+
+	for _, ext := range extensions {
+		normalized, err := normalizeExtension(ext)
+		if err != nil {
+			return nil, err
+		}
+		f.extensions[normalized] = true
+	}
+
+	for _, src := range config.Patterns {
+		if err := ValidatePattern(src); err != nil {
+			return nil, err
+		}
+		p, err := compilePattern(src)
+		if err != nil {
+			return nil, err
+		}
+		f.patterns = append(f.patterns, p)
+	}
+
+	for _, src := range config.Exclude {
+		p, err := compilePattern(src)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, p)
+	}
+
+	return f, nil
+}
+
+// Extensions는 하위 모니터에 설정할 확장자 목록을 반환합니다 (패턴의 확장자 포함)
+func (f *Filter) Extensions() []string {
+	set := make(map[string]bool, len(f.extensions))
+	for ext := range f.extensions {
+		set[ext] = true
+	}
+	for _, p := range f.patterns {
+		set[strings.ToLower(filepath.Ext(p.source))] = true
+	}
+
+	list := make([]string, 0, len(set))
+	for ext := range set {
+		list = append(list, ext)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Match는 경로가 감시 대상이면 true를 반환합니다
+func (f *Filter) Match(path string) bool {
+	if f.Excluded(path) {
+		return false
+	}
+
+	if f.extensions[strings.ToLower(filepath.Ext(path))] {
+		return true
+	}
+	for _, p := range f.patterns {
+		if p.match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded는 경로 또는 상위 디렉토리가 제외 패턴과 일치하면 true를 반환합니다
+func (f *Filter) Excluded(path string) bool {
+	for _, p := range f.exclude {
+		for dir := path; ; {
+			if p.match(dir) {
+				return true
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir || parent == "." {
+				break
+			}
+			dir = parent
+		}
+	}
+	return false
+}
+
+// ValidateExtension은 extensions 항목 하나를 검증합니다
+func ValidateExtension(ext string) error {
+	_, err := normalizeExtension(ext)
+	return err
+}
+
+// ValidatePattern은 patterns 항목 하나를 검증합니다.
+// 하위 모니터는 확장자로만 이벤트를 거르므로 패턴은 구체적인 확장자로 끝나야 합니다.
+func ValidatePattern(src string) error {
+	if _, err := compilePattern(src); err != nil {
+		return err
+	}
+	if _, err := normalizeExtension(filepath.Ext(src)); err != nil {
+		return fmt.Errorf("패턴 %q는 와일드카드 없는 확장자로 끝나야 합니다", src)
+	}
+	return nil
+}
+
+// ValidateExclude는 exclude 항목 하나를 검증합니다
+func ValidateExclude(src string) error {
+	_, err := compilePattern(src)
+	return err
+}
+
+// normalizeExtension은 확장자를 소문자 ".ext" 형태로 검증합니다
+func normalizeExtension(ext string) (string, error) {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext == "" || ext == "." {
+		return "", fmt.Errorf("빈 확장자는 사용할 수 없습니다")
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if strings.ContainsAny(ext[1:], `*?[]./\`) {
+		return "", fmt.Errorf("잘못된 확장자: %q", ext)
+	}
+	return ext, nil
+}
+
+// compilePattern은 glob 패턴을 정규식으로 변환합니다
+func compilePattern(src string) (*pattern, error) {
+	expanded := strings.TrimSpace(expandEnv(src))
+	if expanded == "" {
+		return nil, fmt.Errorf("빈 패턴은 사용할 수 없습니다")
+	}
+
+	var b strings.Builder
+	if runtime.GOOS == "windows" {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	for i := 0; i < len(expanded); i++ {
+		c := expanded[i]
+		switch c {
+		case '*':
+			if i+1 < len(expanded) && expanded[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString(`[^/\\]*`)
+			}
+		case '?':
+			b.WriteString(`[^/\\]`)
+		case '/', '\\':
+			b.WriteString(`[/\\]`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 패턴 %q: %v", src, err)
+	}
+	return &pattern{
+		source:   src,
+		re:       re,
+		baseOnly: !strings.ContainsAny(expanded, `/\`),
+	}, nil
+}
+
+// Glob은 다른 패키지(예: 규칙)에서 사용하는 컴파일된 glob 패턴입니다. 문법은 file_filters 패턴과 같습니다.
+type Glob struct {
+	p *pattern
+}
+
+// CompileGlob은 glob 패턴을 컴파일합니다
+func CompileGlob(src string) (*Glob, error) {
+	p, err := compilePattern(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Glob{p: p}, nil
+}
+
+// Match는 경로가 패턴과 일치하는지 확인합니다. 구분자가 없는 패턴은 파일 이름과 비교합니다.
+func (g *Glob) Match(path string) bool {
+	return g.p.match(path)
+}
+
+// String은 원래 패턴을 반환합니다
+func (g *Glob) String() string {
+	return g.p.source
+}
+
+// match는 경로가 패턴과 일치하는지 확인합니다
+func (p *pattern) match(path string) bool {
+	if p.baseOnly {
+		return p.re.MatchString(filepath.Base(path))
+	}
+	return p.re.MatchString(path)
+}
+
+var windowsEnvRe = regexp.MustCompile(`%([A-Za-z0-9_()]+)%`)
+
+// expandEnv는 %VAR%와 $VAR 형식의 환경 변수를 확장합니다
+func expandEnv(s string) string {
+	s = windowsEnvRe.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := os.LookupEnv(m[1 : len(m)-1]); ok {
+			return v
+		}
+		return m
+	})
+	return os.Expand(s, func(name string) string {
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		// 정의되지 않은 변수는 그대로 유지 (예: C:\$Recycle.Bin)
+		return "$" + name
+	})
+}
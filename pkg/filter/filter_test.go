@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// newTestFilter는 설정으로 필터를 컴파일합니다
+func newTestFilter(t *testing.T, config Config) *Filter {
+	t.Helper()
+	f, err := New(config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return f
+}
+
+func TestMatch(t *testing.T) {
+	temp := filepath.Join(t.TempDir(), "Temp")
+	t.Setenv("TEMP", temp)
+	t.Setenv("HJ_TOOLS", "/opt/tools")
+
+	f := newTestFilter(t, Config{
+		Extensions: []string{".exe", "dll"},
+		Patterns:   []string{"jna*.so", "$HJ_TOOLS/bin/*.sh"},
+		Exclude:    []string{`%TEMP%\jna-*`, "**/cache/**", "*.tmp.exe"},
+	})
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"확장자", "/home/user/setup.exe", true},
+		{"점 없이 지정한 확장자", "/home/user/lib.dll", true},
+		{"확장자 대소문자", "/home/user/SETUP.EXE", true},
+		{"다른 확장자", "/home/user/readme.txt", false},
+		{"확장자 없음", "/home/user/Makefile", false},
+		{"파일 이름 패턴", "/usr/lib/jnidispatch/jna123.so", true},
+		{"파일 이름 패턴 불일치", "/usr/lib/libc.so", false},
+		{"$VAR 패턴", "/opt/tools/bin/run.sh", true},
+		{"$VAR 패턴 하위 디렉토리", "/opt/tools/bin/sub/run.sh", false},
+		{"%TEMP% 제외 디렉토리 아래", filepath.Join(temp, "jna-1234", "jnidispatch.dll"), false},
+		{"%TEMP% 제외 파일", filepath.Join(temp, "jna-5678.dll"), false},
+		{"%TEMP%의 다른 파일", filepath.Join(temp, "installer", "setup.exe"), true},
+		{"** 제외", "/home/user/cache/x/y.exe", false},
+		{"파일 이름 제외", "/home/user/a.tmp.exe", false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.path); got != tt.want {
+			t.Errorf("%s: Match(%q) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchWindowsPaths(t *testing.T) {
+	t.Setenv("TEMP", `C:\Users\hj\AppData\Local\Temp`)
+	f := newTestFilter(t, Config{
+		Extensions: []string{".dll"},
+		Exclude:    []string{`%TEMP%\jna-*`, `C:\Windows\**`},
+	})
+
+	// 패턴의 \ 구분자는 / 와 \ 모두와 일치 (\ 구분 경로는 Windows에서만 상위 디렉토리로 나뉨)
+	for _, path := range []string{
+		"C:/Users/hj/AppData/Local/Temp/jna-42/jnidispatch.dll",
+		filepath.FromSlash("C:/Users/hj/AppData/Local/Temp/jna-42/jnidispatch.dll"),
+	} {
+		if f.Match(path) {
+			t.Errorf("%s 아래 파일이 제외되지 않았습니다: %s", `%TEMP%\jna-*`, path)
+		}
+	}
+	if path := filepath.FromSlash("C:/Users/hj/AppData/Local/Temp/other/a.dll"); !f.Match(path) {
+		t.Errorf("제외 패턴과 관계없는 파일이 제외되었습니다: %s", path)
+	}
+
+	// Windows에서는 패턴을 대소문자 구분 없이 비교
+	if path := filepath.FromSlash("C:/Windows/System32/kernel32.dll"); f.Match(path) {
+		t.Errorf("%s 아래 파일이 제외되지 않았습니다: %s", `C:\Windows\**`, path)
+	}
+	lower := filepath.FromSlash("c:/windows/system32/kernel32.dll")
+	if got, want := f.Match(lower), runtime.GOOS != "windows"; got != want {
+		t.Errorf("Match(%q) = %v, want %v (GOOS=%s)", lower, got, want, runtime.GOOS)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("HJ_DIR", "/data")
+	t.Setenv("ProgramFiles(x86)", `C:\Program Files (x86)`)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"%HJ_DIR%/a", "/data/a"},
+		{"$HJ_DIR/a", "/data/a"},
+		{"${HJ_DIR}/a", "/data/a"},
+		{`%ProgramFiles(x86)%\app`, `C:\Program Files (x86)\app`},
+		{"%HJ_UNDEFINED%/a", "%HJ_UNDEFINED%/a"},
+		{`C:\$Recycle.Bin\**`, `C:\$Recycle.Bin\**`},
+	}
+	for _, tt := range tests {
+		if got := expandEnv(tt.in); got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultExtensions(t *testing.T) {
+	f := newTestFilter(t, Config{})
+	if got, want := f.Extensions(), []string{".dll", ".exe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Extensions = %v, want %v", got, want)
+	}
+
+	// 패턴만 지정하면 기본 확장자를 쓰지 않고 패턴의 확장자만 감시
+	f = newTestFilter(t, Config{Patterns: []string{"**/bin/*.SH"}})
+	if got, want := f.Extensions(), []string{".sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Extensions = %v, want %v", got, want)
+	}
+	if f.Match("/opt/app.exe") {
+		t.Error("패턴만 지정했는데 기본 확장자와 일치했습니다")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"빈 확장자", Config{Extensions: []string{" "}}},
+		{"와일드카드 확장자", Config{Extensions: []string{".ex*"}}},
+		{"확장자 없는 패턴", Config{Patterns: []string{"**/bin/*"}}},
+		{"와일드카드 확장자 패턴", Config{Patterns: []string{"*.e?e"}}},
+		{"빈 제외 패턴", Config{Exclude: []string{""}}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.config); err == nil {
+			t.Errorf("%s: New는 오류를 반환해야 합니다", tt.name)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package hashing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config는 파일 해시 계산 설정입니다
+type Config struct {
+	Enabled      bool `json:"enabled"`        // 실행 파일 해시 계산 여부
+	Workers      int  `json:"workers"`        // 동시에 해시를 계산하는 작업자 수
+	MaxSizeMB    int  `json:"max_size_mb"`    // 이보다 큰 파일은 계산하지 않음, 0이면 제한 없음
+	Retries      int  `json:"retries"`        // 파일이 잠겨 있거나 쓰는 중일 때 다시 시도하는 횟수
+	RetryDelayMs int  `json:"retry_delay_ms"` // 첫 재시도 대기 시간, 재시도마다 두 배로 늘어남 (최소 MinRetryDelay)
+}
+
+// 기본값
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 1000
+)
+
+// MinRetryDelay는 첫 재시도 대기 시간의 최솟값입니다.
+// retry_delay_ms가 0이어도 대기 없이 재시도를 반복하지 않도록 이 시간부터 두 배씩 늘립니다.
+const MinRetryDelay = 10 * time.Millisecond
+
+// 해시를 계산하지 못한 사유
+var (
+	ErrTooLarge  = errors.New("파일 크기 제한 초과")
+	ErrQueueFull = errors.New("해시 계산 대기열이 가득 참")
+	ErrStopped   = errors.New("해시 계산이 중단됨")
+)
+
+// Result는 파일 하나의 해시 계산 결과입니다
+type Result struct {
+	Path     string
+	SHA256   string        // 소문자 16진수, 실패하면 빈 문자열
+	Size     int64         // 계산한 파일 크기
+	Attempts int           // 시도 횟수
+	Duration time.Duration // 재시도 대기를 포함한 전체 소요 시간
+	Err      error
+}
+
+type job struct {
+	path string
+	done func(Result)
+}
+
+// Pool은 정해진 수의 작업자로 파일 해시를 계산하는 작업자 풀입니다.
+// Submit은 기다리지 않으므로 이벤트 루프를 막지 않으며, 결과는 작업자 고루틴에서 done으로 전달됩니다.
+type Pool struct {
+	config  Config
+	jobs    chan job
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+
+	// closed 이후에는 jobs에 보내지 않도록 보호 (닫힌 채널에 보내면 panic)
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// NewPool은 작업자를 시작한 Pool을 생성합니다
+func NewPool(config Config) *Pool {
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	p := &Pool{
+		config: config,
+		jobs:   make(chan job, DefaultQueueSize),
+		stop:   make(chan struct{}),
+	}
+	for i := 0; i < config.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+// Submit은 path의 해시 계산을 대기열에 넣습니다.
+// 대기열이 가득 찼거나 풀이 닫혔으면 false를 반환하며 done은 호출되지 않습니다.
+func (p *Pool) Submit(path string, done func(Result)) bool {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job{path: path, done: done}:
+		return true
+	default:
+		p.dropped.Add(1)
+		return false
+	}
+}
+
+// SubmitWait는 대기열에 자리가 날 때까지 기다려 path의 해시 계산을 넣습니다 (시작 시 보완 검사처럼 한꺼번에 많은 파일을 넣는 경우).
+// ctx가 취소되었거나 풀이 닫혔으면 false를 반환하며 done은 호출되지 않습니다.
+func (p *Pool) SubmitWait(ctx context.Context, path string, done func(Result)) bool {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job{path: path, done: done}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Dropped는 대기열이 가득 차 계산하지 못한 파일 수를 반환합니다
+func (p *Pool) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Close는 재시도 대기를 중단하고 대기열에 남은 작업의 결과를 모두 전달한 뒤 작업자를 종료합니다.
+// 재시도 대기 중이던 작업은 ErrStopped로 끝납니다.
+func (p *Pool) Close() {
+	p.closeMu.Lock()
+	if p.closed {
+		p.closeMu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.stop)
+	close(p.jobs)
+	p.closeMu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for j := range p.jobs {
+		j.done(p.hash(j.path))
+	}
+}
+
+// hash는 파일이 잠겨 있거나 쓰는 중이면 대기 시간을 두 배씩 늘리며 다시 시도합니다.
+// 파일이 삭제되었거나 크기 제한을 넘으면 바로 끝냅니다.
+func (p *Pool) hash(path string) Result {
+	start := time.Now()
+	result := Result{Path: path}
+
+	// 마지막 수정 후 첫 재시도 대기 시간만큼 지난 파일만 계산 (쓰기가 끝났다고 판단)
+	quiet := time.Duration(p.config.RetryDelayMs) * time.Millisecond
+	delay := quiet
+	if delay < MinRetryDelay {
+		delay = MinRetryDelay
+	}
+	maxSize := int64(p.config.MaxSizeMB) << 20
+	for {
+		result.Attempts++
+		result.SHA256, result.Size, result.Err = hashStable(path, maxSize, quiet)
+		if result.Err == nil || result.Attempts > p.config.Retries ||
+			errors.Is(result.Err, os.ErrNotExist) || errors.Is(result.Err, ErrTooLarge) {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-p.stop:
+			result.Err = fmt.Errorf("%w: %v", ErrStopped, result.Err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		delay *= 2
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// 파일을 다른 프로세스가 쓰는 중이라 계산하지 않은 사유
+var (
+	errChanged  = errors.New("계산 중 파일이 변경됨")
+	errModified = errors.New("파일이 방금 변경됨 (쓰는 중)")
+)
+
+// hashStable은 파일의 SHA-256을 계산하고, 계산 전후의 크기와 수정 시각이 같을 때만 결과를 반환합니다.
+// 생성 이벤트 직후에는 파일이 아직 비어 있거나 쓰는 중인 경우가 많으므로, 마지막 수정 후 quiet만큼 지나지 않았으면 계산하지 않습니다.
+func hashStable(path string, maxSize int64, quiet time.Duration) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	before, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	if before.IsDir() {
+		return "", 0, fmt.Errorf("디렉토리입니다: %s", path)
+	}
+	if maxSize > 0 && before.Size() > maxSize {
+		return "", before.Size(), ErrTooLarge
+	}
+	if age := time.Since(before.ModTime()); age >= 0 && age < quiet {
+		return "", 0, errModified
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", n, err
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		return "", n, err
+	}
+	if n != after.Size() || !after.ModTime().Equal(before.ModTime()) {
+		return "", n, errChanged
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// File은 파일 하나의 SHA-256을 바로 계산합니다 (재시도 없음). maxSize가 0이면 크기 제한이 없습니다.
+func File(path string, maxSize int64) (string, error) {
+	sum, _, err := hashStable(path, maxSize, 0)
+	return sum, err
+}
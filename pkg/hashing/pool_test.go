@@ -0,0 +1,221 @@
+package hashing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// sha256 "MZ"
+const mzSHA256 = "9b8db510ef42b8ed54a3712636fda55a4f8cfcd5493e20b74ab00cd4f3979f2d"
+
+// writeFile은 임시 디렉토리에 파일을 만들고 수정 시각을 1분 전으로 되돌립니다 (쓰기가 끝난 파일)
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// hashWait는 풀에서 path의 해시를 계산하고 결과를 기다립니다
+func hashWait(t *testing.T, p *Pool, path string) Result {
+	t.Helper()
+	results := make(chan Result, 1)
+	if !p.Submit(path, func(r Result) { results <- r }) {
+		t.Fatal("Submit이 실패했습니다")
+	}
+	select {
+	case r := <-results:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("해시 계산 결과가 전달되지 않았습니다")
+		return Result{}
+	}
+}
+
+func TestPoolHash(t *testing.T) {
+	p := NewPool(Config{Workers: 2})
+	defer p.Close()
+
+	path := writeFile(t, "a.exe", []byte("MZ"))
+	r := hashWait(t, p, path)
+	if r.Err != nil || r.SHA256 != mzSHA256 || r.Size != 2 || r.Attempts != 1 || r.Path != path {
+		t.Errorf("Result = %+v, want %s", r, mzSHA256)
+	}
+	if sum, err := File(path, 0); err != nil || sum != mzSHA256 {
+		t.Errorf("File = %s, %v", sum, err)
+	}
+}
+
+func TestPoolMaxSize(t *testing.T) {
+	p := NewPool(Config{Workers: 1, MaxSizeMB: 1, Retries: 3, RetryDelayMs: 1000})
+	defer p.Close()
+
+	// 크기 제한과 같은 파일은 계산
+	exact := writeFile(t, "exact.exe", make([]byte, 1<<20))
+	if r := hashWait(t, p, exact); r.Err != nil || r.Size != 1<<20 {
+		t.Errorf("1MB 파일 Result = %+v, want 계산", r)
+	}
+
+	// 제한을 넘으면 다시 시도하지 않고 바로 끝냄
+	large := writeFile(t, "large.exe", make([]byte, 1<<20+1))
+	r := hashWait(t, p, large)
+	if !errors.Is(r.Err, ErrTooLarge) || r.SHA256 != "" || r.Size != 1<<20+1 || r.Attempts != 1 {
+		t.Errorf("1MB 초과 파일 Result = %+v, want ErrTooLarge, 시도 1번", r)
+	}
+	if _, err := File(large, 1<<20); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("File 오류 = %v, want ErrTooLarge", err)
+	}
+	if _, err := File(large, 0); err != nil {
+		t.Errorf("제한 없이 File: %v", err)
+	}
+}
+
+func TestPoolNotExist(t *testing.T) {
+	p := NewPool(Config{Workers: 1, Retries: 3, RetryDelayMs: 1000})
+	defer p.Close()
+
+	// 삭제된 파일은 다시 시도하지 않음
+	r := hashWait(t, p, filepath.Join(t.TempDir(), "missing.exe"))
+	if !errors.Is(r.Err, os.ErrNotExist) || r.Attempts != 1 {
+		t.Errorf("Result = %+v, want ErrNotExist, 시도 1번", r)
+	}
+}
+
+func TestPoolRetryWhileWriting(t *testing.T) {
+	p := NewPool(Config{Workers: 1, Retries: 3, RetryDelayMs: 200})
+	defer p.Close()
+
+	// 방금 수정한 파일은 쓰는 중으로 보고 retry_delay_ms 뒤에 다시 계산
+	path := filepath.Join(t.TempDir(), "writing.exe")
+	if err := os.WriteFile(path, []byte("MZ"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := hashWait(t, p, path)
+	if r.Err != nil || r.SHA256 != mzSHA256 || r.Attempts != 2 {
+		t.Errorf("Result = %+v, want 두 번째 시도에서 계산", r)
+	}
+	if r.Duration < 200*time.Millisecond {
+		t.Errorf("소요 시간 = %v, want 200ms 이상", r.Duration)
+	}
+}
+
+func TestPoolBackoff(t *testing.T) {
+	tests := []struct {
+		delayMs int
+		min     time.Duration
+	}{
+		{20, (20 + 40 + 80) * time.Millisecond},
+		// 0이면 MinRetryDelay부터 두 배씩 늘려 대기 없이 반복하지 않음
+		{0, MinRetryDelay + 2*MinRetryDelay + 4*MinRetryDelay},
+	}
+	for _, tt := range tests {
+		p := NewPool(Config{Workers: 1, Retries: 3, RetryDelayMs: tt.delayMs})
+
+		// 디렉토리는 계산할 수 없으므로 재시도 횟수를 모두 사용
+		r := hashWait(t, p, t.TempDir())
+		p.Close()
+		if r.Err == nil || r.Attempts != 4 {
+			t.Errorf("retry_delay_ms=%d: Result = %+v, want 시도 4번 후 실패", tt.delayMs, r)
+		}
+		if r.Duration < tt.min {
+			t.Errorf("retry_delay_ms=%d: 소요 시간 = %v, want %v 이상", tt.delayMs, r.Duration, tt.min)
+		}
+	}
+}
+
+func TestPoolCloseStopsRetry(t *testing.T) {
+	p := NewPool(Config{Workers: 1, Retries: 5, RetryDelayMs: 10000})
+
+	results := make(chan Result, 1)
+	if !p.Submit(t.TempDir(), func(r Result) { results <- r }) {
+		t.Fatal("Submit이 실패했습니다")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// 재시도 대기 중인 작업은 기다리지 않고 ErrStopped로 끝남
+	start := time.Now()
+	p.Close()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Close 소요 시간 = %v", elapsed)
+	}
+	if r := <-results; !errors.Is(r.Err, ErrStopped) || r.Attempts != 1 {
+		t.Errorf("Result = %+v, want ErrStopped", r)
+	}
+
+	if p.Submit(t.TempDir(), func(Result) { t.Error("닫힌 풀의 작업이 실행되었습니다") }) {
+		t.Error("닫힌 풀에 Submit이 성공했습니다")
+	}
+	if p.SubmitWait(context.Background(), t.TempDir(), func(Result) { t.Error("닫힌 풀의 작업이 실행되었습니다") }) {
+		t.Error("닫힌 풀에 SubmitWait가 성공했습니다")
+	}
+	p.Close()
+}
+
+func TestPoolQueueFull(t *testing.T) {
+	p := NewPool(Config{Workers: 1})
+	path := writeFile(t, "a.exe", []byte("MZ"))
+
+	// 작업자를 멈춘 상태에서 대기열을 가득 채움
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Submit(path, func(Result) {
+		close(started)
+		<-release
+	})
+	<-started
+	done := make(chan Result, DefaultQueueSize+1)
+	for i := 0; i < DefaultQueueSize; i++ {
+		if !p.Submit(path, func(r Result) { done <- r }) {
+			t.Fatalf("%d번째 Submit이 실패했습니다", i)
+		}
+	}
+
+	// Submit은 기다리지 않고 버림
+	if p.Submit(path, func(Result) { t.Error("버린 작업이 실행되었습니다") }) {
+		t.Error("대기열이 가득 찼는데 Submit이 성공했습니다")
+	}
+	if p.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", p.Dropped())
+	}
+
+	// SubmitWait는 자리가 날 때까지 기다리고, ctx가 취소되면 넣지 않고 돌아옴
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if p.SubmitWait(ctx, path, func(Result) { t.Error("취소된 작업이 실행되었습니다") }) {
+		t.Error("대기열이 가득 찼는데 SubmitWait가 성공했습니다")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("SubmitWait가 %v 만에 돌아왔습니다, want ctx 취소까지 대기", elapsed)
+	}
+
+	waited := make(chan bool)
+	go func() {
+		waited <- p.SubmitWait(context.Background(), path, func(r Result) { done <- r })
+	}()
+	close(release)
+	if !<-waited {
+		t.Error("자리가 났는데 SubmitWait가 실패했습니다")
+	}
+	p.Close()
+
+	// Close는 대기열에 남은 작업의 결과를 모두 전달
+	if len(done) != DefaultQueueSize+1 {
+		t.Errorf("전달된 결과 = %d, want %d", len(done), DefaultQueueSize+1)
+	}
+	for len(done) > 0 {
+		if r := <-done; r.Err != nil || r.SHA256 != mzSHA256 {
+			t.Fatalf("Result = %+v", r)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+//go:build windows
+// +build windows
+
+package hashing
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+func TestPoolRetryLockedFile(t *testing.T) {
+	path := writeFile(t, "locked.exe", []byte("MZ"))
+
+	// 공유 없이 열어 다른 프로세스가 쓰는 중인 파일처럼 잠금
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := windows.CreateFile(name, windows.GENERIC_READ, 0, nil, windows.OPEN_EXISTING, windows.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		windows.CloseHandle(h)
+	}()
+
+	p := NewPool(Config{Workers: 1, Retries: 5, RetryDelayMs: 50})
+	defer p.Close()
+
+	// 잠금이 풀릴 때까지 대기 시간을 늘리며 다시 시도
+	r := hashWait(t, p, path)
+	if r.Err != nil || r.SHA256 != mzSHA256 || r.Attempts < 2 {
+		t.Errorf("Result = %+v, want 잠금이 풀린 뒤 계산", r)
+	}
+}
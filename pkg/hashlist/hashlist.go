@@ -0,0 +1,190 @@
+package hashlist
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Config는 해시 목록 파일 설정입니다. 비어 있는 경로는 사용하지 않습니다.
+type Config struct {
+	Allowlist string `json:"allowlist"` // 알려진 정상 파일 목록
+	Blocklist string `json:"blocklist"` // 알려진 악성 파일 목록
+}
+
+// Kind는 일치한 목록의 종류입니다
+type Kind string
+
+// 목록 종류 정의
+const (
+	Allow Kind = "allowlist"
+	Block Kind = "blocklist"
+)
+
+// Match는 해시가 목록에서 발견된 결과입니다
+type Match struct {
+	Kind  Kind
+	Label string // 목록에 기록된 설명 (없으면 빈 문자열)
+}
+
+// LoadStats는 목록 파일을 읽은 결과입니다
+type LoadStats struct {
+	Allowed int      // 허용 목록 항목 수
+	Blocked int      // 차단 목록 항목 수
+	Invalid []string // 무시한 잘못된 줄 ("파일:줄 번호: 사유")
+}
+
+// Lists는 메모리에 올린 허용/차단 해시 목록입니다.
+// Lookup은 해시 작업자 고루틴에서, Load는 서비스 루프에서 호출하므로 잠금으로 보호합니다.
+type Lists struct {
+	config Config
+
+	mu    sync.RWMutex
+	allow map[string]string
+	block map[string]string
+}
+
+// New는 빈 목록을 생성합니다. Load로 파일을 읽습니다.
+func New(config Config) *Lists {
+	return &Lists{config: config}
+}
+
+// Paths는 설정된 목록 파일 경로를 반환합니다
+func (l *Lists) Paths() []string {
+	var paths []string
+	for _, p := range []string{l.config.Allowlist, l.config.Blocklist} {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// Load는 목록 파일을 다시 읽어 교체합니다. 파일을 읽을 수 없으면 기존 목록을 유지하고 오류를 반환합니다.
+// 형식이 잘못된 줄은 건너뛰고 LoadStats.Invalid로 알립니다.
+func (l *Lists) Load() (LoadStats, error) {
+	var stats LoadStats
+
+	allow, invalid, err := readOptional(l.config.Allowlist)
+	if err != nil {
+		return stats, err
+	}
+	stats.Invalid = append(stats.Invalid, invalid...)
+
+	block, invalid, err := readOptional(l.config.Blocklist)
+	if err != nil {
+		return stats, err
+	}
+	stats.Invalid = append(stats.Invalid, invalid...)
+
+	l.mu.Lock()
+	l.allow, l.block = allow, block
+	l.mu.Unlock()
+
+	stats.Allowed, stats.Blocked = len(allow), len(block)
+	return stats, nil
+}
+
+// Lookup은 해시가 목록에 있는지 확인합니다. 두 목록에 모두 있으면 차단 목록이 우선합니다.
+func (l *Lists) Lookup(sum string) (Match, bool) {
+	sum = strings.ToLower(sum)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if label, ok := l.block[sum]; ok {
+		return Match{Kind: Block, Label: label}, true
+	}
+	if label, ok := l.allow[sum]; ok {
+		return Match{Kind: Allow, Label: label}, true
+	}
+	return Match{}, false
+}
+
+func readOptional(path string) (map[string]string, []string, error) {
+	if path == "" {
+		return nil, nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("해시 목록을 열 수 없습니다: %v", err)
+	}
+	defer f.Close()
+
+	entries, invalid, err := Parse(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("해시 목록 읽기 실패 %s: %v", path, err)
+	}
+	for i := range invalid {
+		invalid[i] = path + ":" + invalid[i]
+	}
+	return entries, invalid, nil
+}
+
+// Parse는 해시 목록을 읽어 해시(소문자) -> 설명 맵을 반환합니다.
+// 한 줄에 하나씩 "해시", "해시 설명" 또는 CSV "해시,설명" 형식을 사용하며,
+// 빈 줄, '#'으로 시작하는 주석, "sha256"으로 시작하는 CSV 헤더는 건너뜁니다.
+// 형식이 잘못된 줄은 "줄 번호: 사유"로 반환합니다.
+func Parse(r io.Reader) (map[string]string, []string, error) {
+	entries := make(map[string]string)
+	var invalid []string
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff") // 메모장이 저장한 UTF-8 BOM
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sum, label, err := parseLine(line)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%d: %v", n, err))
+			continue
+		}
+		if strings.EqualFold(sum, "sha256") {
+			continue
+		}
+		if err := validateSHA256(sum); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%d: %v", n, err))
+			continue
+		}
+		entries[strings.ToLower(sum)] = label
+	}
+	return entries, invalid, scanner.Err()
+}
+
+// parseLine은 한 줄을 해시와 설명으로 나눕니다
+func parseLine(line string) (string, string, error) {
+	if strings.Contains(line, ",") {
+		fields, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			return "", "", fmt.Errorf("CSV 형식 오류: %v", err)
+		}
+		label := ""
+		if len(fields) > 1 {
+			label = strings.TrimSpace(fields[1])
+		}
+		return strings.TrimSpace(fields[0]), label, nil
+	}
+
+	sum := strings.Fields(line)[0]
+	return sum, strings.TrimSpace(line[len(sum):]), nil
+}
+
+// validateSHA256은 64자리 16진수인지 확인합니다
+func validateSHA256(sum string) error {
+	if len(sum) != 64 {
+		return fmt.Errorf("SHA-256은 64자리 16진수여야 합니다: %q", sum)
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return fmt.Errorf("SHA-256은 64자리 16진수여야 합니다: %q", sum)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package hashlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	sumA = "9b8db510ef42b8ed54a3712636fda55a4f8cfcd5493e20b74ab00cd4f3979f2d"
+	sumB = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	sumC = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+)
+
+func TestParse(t *testing.T) {
+	input := "\ufeff# 알려진 도구 목록\n" +
+		"\n" +
+		"sha256,label\n" +
+		sumA + "\n" +
+		"  " + strings.ToUpper(sumB) + "   설치 프로그램 v2  \n" +
+		sumC + `,"dropper, 변종"` + "\n" +
+		"   # 들여쓴 주석\n"
+
+	entries, invalid, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{
+		sumA: "",
+		sumB: "설치 프로그램 v2",
+		sumC: "dropper, 변종",
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %q, want %q", entries, want)
+	}
+	if len(invalid) != 0 {
+		t.Errorf("invalid = %q, want 없음", invalid)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	input := strings.Join([]string{
+		sumA,
+		sumA[:63],                // 63자리
+		sumA + "0",               // 65자리
+		"z" + sumA[1:],           // 16진수가 아닌 문자
+		`"` + sumB + `,unclosed`, // CSV 따옴표 오류
+		"0x" + sumB[2:],
+		sumC + " 정상",
+	}, "\n")
+
+	entries, invalid, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// 잘못된 줄은 건너뛰고 나머지 줄은 읽음
+	if !reflect.DeepEqual(entries, map[string]string{sumA: "", sumC: "정상"}) {
+		t.Errorf("entries = %q", entries)
+	}
+	var lines []string
+	for _, line := range invalid {
+		n, _, _ := strings.Cut(line, ":")
+		lines = append(lines, n)
+	}
+	if !reflect.DeepEqual(lines, []string{"2", "3", "4", "5", "6"}) {
+		t.Errorf("잘못된 줄 = %q, want 2-6번째 줄", invalid)
+	}
+	if !strings.Contains(invalid[0], "64자리 16진수") || !strings.Contains(invalid[3], "CSV 형식 오류") {
+		t.Errorf("잘못된 줄 사유 = %q", invalid)
+	}
+}
+
+// writeList는 임시 디렉토리에 목록 파일을 만들고 경로를 반환합니다
+func writeList(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLookup(t *testing.T) {
+	dir := t.TempDir()
+	l := New(Config{
+		Allowlist: writeList(t, dir, "allow.txt", sumA+" 정상 도구", sumB+" 양쪽", "invalid"),
+		Blocklist: writeList(t, dir, "block.csv", "sha256,label", sumB+",악성"),
+	})
+	if got := l.Paths(); len(got) != 2 {
+		t.Errorf("Paths = %v", got)
+	}
+
+	stats, err := l.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if stats.Allowed != 2 || stats.Blocked != 1 || len(stats.Invalid) != 1 ||
+		!strings.HasPrefix(stats.Invalid[0], filepath.Join(dir, "allow.txt")+":3: ") {
+		t.Errorf("LoadStats = %+v", stats)
+	}
+
+	tests := []struct {
+		sum   string
+		match Match
+		ok    bool
+	}{
+		{sumA, Match{Kind: Allow, Label: "정상 도구"}, true},
+		// 대소문자 구분 없음
+		{strings.ToUpper(sumA), Match{Kind: Allow, Label: "정상 도구"}, true},
+		// 두 목록에 모두 있으면 차단 목록 우선
+		{sumB, Match{Kind: Block, Label: "악성"}, true},
+		{sumC, Match{}, false},
+	}
+	for _, tt := range tests {
+		if match, ok := l.Lookup(tt.sum); match != tt.match || ok != tt.ok {
+			t.Errorf("Lookup(%s) = %+v, %v, want %+v, %v", tt.sum[:8], match, ok, tt.match, tt.ok)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeList(t, dir, "block.txt", sumA+" 이전")
+	l := New(Config{Blocklist: path})
+	if _, err := l.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 파일이 바뀌면 다시 읽은 목록으로 교체
+	writeList(t, dir, "block.txt", sumC+" 새 항목")
+	if stats, err := l.Load(); err != nil || stats.Blocked != 1 {
+		t.Fatalf("다시 Load = %+v, %v", stats, err)
+	}
+	if _, ok := l.Lookup(sumA); ok {
+		t.Error("삭제된 항목이 남아 있습니다")
+	}
+	if match, ok := l.Lookup(sumC); !ok || match.Label != "새 항목" {
+		t.Errorf("Lookup = %+v, %v, want 새 항목", match, ok)
+	}
+
+	// 파일을 읽을 수 없으면 기존 목록 유지
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(); err == nil {
+		t.Error("파일이 없는데 Load가 오류를 반환하지 않았습니다")
+	}
+	if _, ok := l.Lookup(sumC); !ok {
+		t.Error("Load 실패 후 기존 목록이 사라졌습니다")
+	}
+
+	// 경로를 설정하지 않은 목록은 비어 있음
+	empty := New(Config{})
+	if stats, err := empty.Load(); err != nil || stats.Allowed != 0 || stats.Blocked != 0 {
+		t.Errorf("빈 설정 Load = %+v, %v", stats, err)
+	}
+	if _, ok := empty.Lookup(sumA); ok {
+		t.Error("빈 목록에서 해시가 발견되었습니다")
+	}
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server는 실행 중인 서비스의 상태를 제공하는 HTTP 서버입니다.
+//
+//	/healthz  프로세스가 응답하면 200
+//	/readyz   Ready가 nil을 반환하면 200, 아니면 503과 사유
+//	/status   Status가 반환한 값을 JSON으로 출력
+type Server struct {
+	// Status는 /status 응답 본문을 반환합니다
+	Status func() interface{}
+	// Ready는 서비스가 요청을 처리할 준비가 되었는지 확인합니다 (nil이면 준비됨)
+	Ready func() error
+
+	mux      *http.ServeMux
+	srv      *http.Server
+	listener net.Listener
+}
+
+// NewServer는 기본 엔드포인트가 등록된 서버를 생성합니다
+func NewServer(status func() interface{}, ready func() error) *Server {
+	s := &Server{
+		Status: status,
+		Ready:  ready,
+		mux:    http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+	s.mux.HandleFunc("/status", s.handleStatus)
+	return s
+}
+
+// Handle은 추가 엔드포인트를 등록합니다. Start 전에 호출해야 합니다.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Handler는 등록된 엔드포인트를 처리하는 핸들러를 반환합니다 (테스트에서 httptest와 함께 사용)
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start는 addr에서 연결을 받기 시작합니다. 루프백 주소만 허용합니다.
+// 포트 사용 중 등의 오류는 바로 반환하고, 요청 처리는 별도 고루틴에서 수행합니다.
+func (s *Server) Start(addr string) error {
+	if err := ValidateAddress(addr); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("상태 서버 시작 실패: %v", err)
+	}
+
+	s.listener = listener
+	s.srv = &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go s.srv.Serve(listener)
+	return nil
+}
+
+// Addr은 실제로 연결을 받는 주소를 반환합니다 (포트 0으로 시작한 경우 확인용)
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown은 진행 중인 요청이 끝나기를 기다린 뒤 서버를 중지합니다
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.srv == nil {
+		return nil
+	}
+	return s.srv.Shutdown(ctx)
+}
+
+// ValidateAddress는 주소가 "호스트:포트" 형식의 루프백 주소인지 확인합니다
+func ValidateAddress(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("주소 형식이 잘못되었습니다 (예: 127.0.0.1:9790): %v", err)
+	}
+	if port == "" {
+		return fmt.Errorf("포트가 지정되지 않았습니다: %q", addr)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("루프백 주소만 사용할 수 있습니다 (127.0.0.1, ::1, localhost): %q", host)
+	}
+	return nil
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if s.Ready != nil {
+		if err := s.Ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "not ready: %v\n", err)
+			return
+		}
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	var status interface{}
+	if s.Status != nil {
+		status = s.Status()
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	w.Write([]byte("\n"))
+}
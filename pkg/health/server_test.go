@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// get은 핸들러에 GET 요청을 보내고 상태 코드와 본문을 반환합니다
+func get(t *testing.T, h http.Handler, path string) (int, string, http.Header) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String(), rec.Header()
+}
+
+func TestHealthz(t *testing.T) {
+	s := NewServer(nil, func() error { return errors.New("준비 안 됨") })
+
+	// 준비 상태와 관계없이 프로세스가 응답하면 200
+	code, body, _ := get(t, s.Handler(), "/healthz")
+	if code != http.StatusOK || body != "ok\n" {
+		t.Errorf("/healthz = %d %q, want 200 ok", code, body)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	var stopped atomic.Bool
+	s := NewServer(nil, func() error {
+		if stopped.Load() {
+			return errors.New("모니터 상태 stopped")
+		}
+		return nil
+	})
+
+	code, body, _ := get(t, s.Handler(), "/readyz")
+	if code != http.StatusOK || body != "ok\n" {
+		t.Errorf("실행 중 /readyz = %d %q, want 200 ok", code, body)
+	}
+
+	// 파이프라인이 멈추면 503과 사유
+	stopped.Store(true)
+	code, body, _ = get(t, s.Handler(), "/readyz")
+	if code != http.StatusServiceUnavailable || body != "not ready: 모니터 상태 stopped\n" {
+		t.Errorf("중지 후 /readyz = %d %q, want 503 not ready", code, body)
+	}
+
+	stopped.Store(false)
+	if code, _, _ = get(t, s.Handler(), "/readyz"); code != http.StatusOK {
+		t.Errorf("다시 실행 중 /readyz = %d, want 200", code)
+	}
+
+	// Ready가 없으면 항상 준비됨
+	if code, _, _ := get(t, NewServer(nil, nil).Handler(), "/readyz"); code != http.StatusOK {
+		t.Errorf("Ready 없이 /readyz = %d, want 200", code)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	type status struct {
+		State  string `json:"state"`
+		Events int    `json:"events"`
+	}
+	var events atomic.Int64
+	s := NewServer(func() interface{} {
+		return status{State: "running", Events: int(events.Load())}
+	}, nil)
+
+	events.Store(3)
+	code, body, header := get(t, s.Handler(), "/status")
+	if code != http.StatusOK || header.Get("Content-Type") != "application/json" {
+		t.Fatalf("/status = %d %s", code, header.Get("Content-Type"))
+	}
+	var got status
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("JSON 형식 오류: %v\n%s", err, body)
+	}
+	if got != (status{State: "running", Events: 3}) {
+		t.Errorf("/status = %+v", got)
+	}
+
+	// JSON으로 바꿀 수 없는 값은 500
+	s.Status = func() interface{} { return func() {} }
+	if code, _, _ := get(t, s.Handler(), "/status"); code != http.StatusInternalServerError {
+		t.Errorf("잘못된 상태 /status = %d, want 500", code)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	s := NewServer(nil, nil)
+	s.Handle("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "metrics")
+	}))
+	if code, body, _ := get(t, s.Handler(), "/metrics"); code != http.StatusOK || body != "metrics" {
+		t.Errorf("/metrics = %d %q", code, body)
+	}
+	if code, _, _ := get(t, s.Handler(), "/unknown"); code != http.StatusNotFound {
+		t.Errorf("/unknown = %d, want 404", code)
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		addr  string
+		valid bool
+	}{
+		{"127.0.0.1:9790", true},
+		{"127.1.2.3:9790", true},
+		{"[::1]:9790", true},
+		{"localhost:9790", true},
+		{"0.0.0.0:9790", false},
+		{":9790", false},
+		{"[::]:9790", false},
+		{"192.168.0.10:9790", false},
+		{"example.com:9790", false},
+		{"127.0.0.1", false},
+		{"127.0.0.1:", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateAddress(tt.addr); (err == nil) != tt.valid {
+			t.Errorf("ValidateAddress(%q) = %v, want valid=%v", tt.addr, err, tt.valid)
+		}
+	}
+}
+
+func TestStartLoopbackOnly(t *testing.T) {
+	if err := NewServer(nil, nil).Start("0.0.0.0:0"); err == nil || !strings.Contains(err.Error(), "루프백") {
+		t.Errorf("Start(0.0.0.0:0) 오류 = %v, want 루프백 주소만 허용", err)
+	}
+
+	s := NewServer(nil, nil)
+	if err := s.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Shutdown(context.Background())
+	if !strings.HasPrefix(s.Addr(), "127.0.0.1:") {
+		t.Fatalf("Addr = %q", s.Addr())
+	}
+
+	resp, err := http.Get("http://" + s.Addr() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /healthz = %d, want 200", resp.StatusCode)
+	}
+
+	// 같은 포트는 바로 오류
+	if err := NewServer(nil, nil).Start(s.Addr()); err == nil {
+		t.Error("사용 중인 포트로 Start하면 오류를 반환해야 합니다")
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, err := http.Get("http://" + s.Addr() + "/healthz"); err == nil {
+		t.Error("Shutdown 후에도 요청을 받습니다")
+	}
+}
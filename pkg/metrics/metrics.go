@@ -0,0 +1,289 @@
+package metrics
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"windows_service_module/pkg/winsvc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace는 모든 메트릭 이름의 접두사입니다
+const namespace = "hjsvc"
+
+// OtherFileType은 SetFileTypes로 지정하지 않은 파일 형식의 file_type 레이블 값입니다
+const OtherFileType = "other"
+
+// Metrics는 서비스의 Prometheus 메트릭 모음입니다.
+// 모든 메서드는 nil 수신자에서도 안전하게 호출할 수 있어 메트릭 없이도 파이프라인을 실행할 수 있습니다.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	events           *prometheus.CounterVec
+	eventsFiltered   prometheus.Counter
+	eventsCoalesced  prometheus.Counter
+	watchedPaths     prometheus.Gauge
+	dbInsertDuration prometheus.Histogram
+	hashDuration     prometheus.Histogram
+	hashErrors       prometheus.Counter
+	alerts           *prometheus.CounterVec
+	sinkEvents       *prometheus.CounterVec
+	ruleMatches      *prometheus.CounterVec
+	eventsSuppressed prometheus.Counter
+
+	// fileTypes는 file_type 레이블로 구분할 확장자입니다 (SetFileTypes)
+	fileTypes atomic.Pointer[map[string]bool]
+}
+
+// New는 메트릭을 생성하고 Go 런타임/프로세스 메트릭과 함께 등록합니다
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "file_events_total",
+			Help:      "필터를 통과하여 처리한 파일 이벤트 수",
+		}, []string{"operation", "file_type"}),
+		eventsFiltered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "file_events_filtered_total",
+			Help:      "필터에 의해 제외된 파일 이벤트 수",
+		}),
+		eventsCoalesced: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "file_events_coalesced_total",
+			Help:      "같은 경로의 이전 이벤트에 합쳐져 따로 기록하지 않은 파일 이벤트 수",
+		}),
+		watchedPaths: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "watched_paths",
+			Help:      "감시 중인 경로 수",
+		}),
+		dbInsertDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_insert_duration_seconds",
+			Help:      "이벤트 데이터베이스 저장 소요 시간",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms ~ 4s
+		}),
+		hashDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "hash_duration_seconds",
+			Help:      "파일 SHA-256 계산 소요 시간 (재시도 대기 포함)",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16), // 1ms ~ 32s
+		}),
+		hashErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "hash_errors_total",
+			Help:      "해시를 계산하지 못한 파일 수 (크기 제한, 잠김, 대기열 초과 포함)",
+		}),
+		alerts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alerts_total",
+			Help:      "파일 이벤트에서 발생한 경고 수",
+		}, []string{"source", "level"}),
+		sinkEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sink_events_total",
+			Help:      "이벤트 싱크 전송 결과별 이벤트 수 (sent, error, dropped)",
+		}, []string{"sink", "result"}),
+		ruleMatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rule_matches_total",
+			Help:      "규칙별로 일치한 파일 이벤트 수",
+		}, []string{"rule"}),
+		eventsSuppressed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "file_events_suppressed_total",
+			Help:      "suppress 규칙에 의해 기록하지 않은 파일 이벤트 수",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.events,
+		m.eventsFiltered,
+		m.eventsCoalesced,
+		m.watchedPaths,
+		m.dbInsertDuration,
+		m.hashDuration,
+		m.hashErrors,
+		m.alerts,
+		m.sinkEvents,
+		m.ruleMatches,
+		m.eventsSuppressed,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler는 /metrics 응답 핸들러를 반환합니다
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry는 추가 메트릭을 등록할 레지스트리를 반환합니다
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// SetFileTypes는 file_type 레이블로 구분할 확장자(설정된 필터의 확장자)를 지정합니다.
+// 패턴으로 감시하는 파일은 확장자가 제한되지 않으므로, 그 밖의 확장자는 OtherFileType으로 집계하여 시계열 수를 제한합니다.
+func (m *Metrics) SetFileTypes(types []string) {
+	if m == nil {
+		return
+	}
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		if t != "" {
+			set[t] = true
+		}
+	}
+	m.fileTypes.Store(&set)
+}
+
+// ObserveEvent는 처리한 파일 이벤트를 집계합니다
+func (m *Metrics) ObserveEvent(operation, fileType string) {
+	if m == nil {
+		return
+	}
+	if types := m.fileTypes.Load(); types == nil || !(*types)[fileType] {
+		fileType = OtherFileType
+	}
+	m.events.WithLabelValues(operation, fileType).Inc()
+}
+
+// ObserveFiltered는 필터에 의해 제외된 이벤트를 집계합니다
+func (m *Metrics) ObserveFiltered() {
+	if m == nil {
+		return
+	}
+	m.eventsFiltered.Inc()
+}
+
+// ObserveCoalesced는 같은 경로의 이전 이벤트에 합쳐진 이벤트를 집계합니다
+func (m *Metrics) ObserveCoalesced() {
+	if m == nil {
+		return
+	}
+	m.eventsCoalesced.Inc()
+}
+
+// SetWatchedPaths는 감시 중인 경로 수를 기록합니다
+func (m *Metrics) SetWatchedPaths(n int) {
+	if m == nil {
+		return
+	}
+	m.watchedPaths.Set(float64(n))
+}
+
+// ObserveDBInsert는 이벤트 저장 소요 시간을 기록합니다
+func (m *Metrics) ObserveDBInsert(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.dbInsertDuration.Observe(d.Seconds())
+}
+
+// ObserveHash는 파일 해시 계산 결과를 기록합니다
+func (m *Metrics) ObserveHash(d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.hashErrors.Inc()
+		return
+	}
+	m.hashDuration.Observe(d.Seconds())
+}
+
+// ObserveAlert는 발생한 경고를 집계합니다
+func (m *Metrics) ObserveAlert(source, level string) {
+	if m == nil {
+		return
+	}
+	m.alerts.WithLabelValues(source, level).Inc()
+}
+
+// ObserveSink는 이벤트 싱크 전송 결과(sent, error, dropped)를 집계합니다
+func (m *Metrics) ObserveSink(sink, result string) {
+	if m == nil {
+		return
+	}
+	m.sinkEvents.WithLabelValues(sink, result).Inc()
+}
+
+// ObserveRule은 이벤트와 일치한 규칙을 집계합니다
+func (m *Metrics) ObserveRule(rule string) {
+	if m == nil {
+		return
+	}
+	m.ruleMatches.WithLabelValues(rule).Inc()
+}
+
+// ObserveSuppressed는 suppress 규칙에 의해 기록하지 않은 이벤트를 집계합니다
+func (m *Metrics) ObserveSuppressed() {
+	if m == nil {
+		return
+	}
+	m.eventsSuppressed.Inc()
+}
+
+// RegisterDroppedEvents는 이벤트 공급원에서 버린 이벤트 수를 조회할 함수를 등록합니다
+func (m *Metrics) RegisterDroppedEvents(dropped func() uint64) {
+	m.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "file_events_dropped_total",
+		Help:      "처리가 밀려 버린 파일 이벤트 수",
+	}, func() float64 { return float64(dropped()) }))
+}
+
+// RegisterRestarts는 서비스 재시작 횟수를 등록합니다 (서비스 관리자가 서비스를 시작한 횟수 - 1)
+func (m *Metrics) RegisterRestarts(restarts uint64) {
+	m.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "service_restarts_total",
+		Help:      "서비스가 다시 시작된 횟수",
+	}, func() float64 { return float64(restarts) }))
+}
+
+// RegisterLogger는 로거의 기록 오류와 버린 로그 수를 등록합니다
+func (m *Metrics) RegisterLogger(logger *winsvc.Logger) {
+	m.registry.MustRegister(&loggerCollector{logger: logger})
+}
+
+// loggerCollector는 수집 시점에 Logger.Stats를 읽어 메트릭으로 변환합니다
+type loggerCollector struct {
+	logger *winsvc.Logger
+}
+
+var (
+	logWriteErrorsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "logger", "write_errors_total"),
+		"로그 파일 또는 이벤트 로그 기록 실패 수", nil, nil)
+	logDroppedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "logger", "dropped_total"),
+		"비동기 큐가 가득 차 버린 로그 수", []string{"level"}, nil)
+	logQueueLenDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "logger", "queue_length"),
+		"기록 대기 중인 로그 수", nil, nil)
+)
+
+func (c *loggerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- logWriteErrorsDesc
+	ch <- logDroppedDesc
+	ch <- logQueueLenDesc
+}
+
+func (c *loggerCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.logger.Stats()
+	ch <- prometheus.MustNewConstMetric(logWriteErrorsDesc, prometheus.CounterValue, float64(stats.WriteErrors))
+	for level := winsvc.LogDebug; level <= winsvc.LogError; level++ {
+		ch <- prometheus.MustNewConstMetric(logDroppedDesc, prometheus.CounterValue,
+			float64(stats.DroppedByLevel[level]), level.String())
+	}
+	ch <- prometheus.MustNewConstMetric(logQueueLenDesc, prometheus.GaugeValue, float64(stats.QueueLen))
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveEventFileTypes(t *testing.T) {
+	m := New()
+	m.SetFileTypes([]string{".exe", ".dll", ""})
+
+	for _, fileType := range []string{".exe", ".exe", ".dll", ".tmp", ".x1", ""} {
+		m.ObserveEvent("CREATE", fileType)
+	}
+
+	// 설정한 확장자와 other만 시계열로 남아야 함
+	if n := testutil.CollectAndCount(m.events); n != 3 {
+		t.Errorf("시계열 %d개, want 3개", n)
+	}
+
+	tests := []struct {
+		fileType string
+		want     float64
+	}{
+		{".exe", 2},
+		{".dll", 1},
+		{OtherFileType, 3},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.events.WithLabelValues("CREATE", tt.fileType)); got != tt.want {
+			t.Errorf("file_type=%q: %v, want %v", tt.fileType, got, tt.want)
+		}
+	}
+}
+
+func TestObserveEventNoFileTypes(t *testing.T) {
+	m := New()
+	m.ObserveEvent("WRITE", ".exe")
+	if got := testutil.ToFloat64(m.events.WithLabelValues("WRITE", OtherFileType)); got != 1 {
+		t.Errorf("SetFileTypes 전에는 other로 집계해야 합니다: %v", got)
+	}
+
+	var nilMetrics *Metrics
+	nilMetrics.SetFileTypes([]string{".exe"})
+	nilMetrics.ObserveEvent("WRITE", ".exe")
+}
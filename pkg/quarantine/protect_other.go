@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package quarantine
+
+import "os"
+
+// protectDir은 격리 디렉토리를 소유자(서비스 계정)만 접근할 수 있도록 합니다
+func protectDir(dir string) error {
+	return os.Chmod(dir, 0700)
+}
+
+// protectFile은 격리한 파일의 실행 권한을 없애고 소유자만 읽을 수 있도록 합니다
+func protectFile(path string) error {
+	return os.Chmod(path, 0600)
+}
+
+// unprotectFile은 복원한 파일의 원래 권한을 되돌립니다
+func unprotectFile(path string, mode os.FileMode) error {
+	return os.Chmod(path, mode)
+}
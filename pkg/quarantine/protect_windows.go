@@ -0,0 +1,50 @@
+//go:build windows
+// +build windows
+
+package quarantine
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// protectedSDDL은 SYSTEM과 Administrators만 접근할 수 있고 상위 디렉토리 권한을 상속하지 않는 보안 설명자입니다
+const protectedSDDL = "D:P(A;OICI;FA;;;SY)(A;OICI;FA;;;BA)"
+
+// protectDir은 격리 디렉토리를 SYSTEM과 Administrators만 접근할 수 있도록 합니다
+func protectDir(dir string) error {
+	return setProtectedDACL(dir)
+}
+
+// protectFile은 옮겨 온 파일의 기존 권한을 격리 디렉토리와 같은 권한으로 바꿉니다
+// (이름 바꾸기로 옮긴 파일은 원래 권한을 그대로 가지므로)
+func protectFile(path string) error {
+	return setProtectedDACL(path)
+}
+
+// unprotectFile은 복원한 파일이 다시 상위 디렉토리의 권한을 상속하도록 합니다
+func unprotectFile(path string, mode os.FileMode) error {
+	acl, err := windows.ACLFromEntries(nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.UNPROTECTED_DACL_SECURITY_INFORMATION, nil, nil, acl, nil); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
+
+func setProtectedDACL(path string) error {
+	sd, err := windows.SecurityDescriptorFromString(protectedSDDL)
+	if err != nil {
+		return err
+	}
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return err
+	}
+	return windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION, nil, nil, dacl, nil)
+}
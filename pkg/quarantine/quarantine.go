@@ -0,0 +1,333 @@
+package quarantine
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"windows_service_module/pkg/hashing"
+)
+
+// Config는 격리 설정입니다. 격리 디렉토리는 custom_data_path 아래의 DirName입니다.
+type Config struct {
+	Enabled bool `json:"enabled"` // 규칙의 quarantine 동작으로 파일을 격리 (false이면 경고만 기록)
+}
+
+// DirName은 격리 디렉토리 이름입니다 (custom_data_path 아래)
+const DirName = "quarantine"
+
+// 격리 디렉토리의 파일 확장자 (<ID>.quarantine은 파일 내용, <ID>.json은 메타데이터)
+const (
+	payloadExt  = ".quarantine"
+	metadataExt = ".json"
+)
+
+// Entry는 격리한 파일 하나의 메타데이터입니다 (<ID>.json)
+type Entry struct {
+	ID            string      `json:"id"`
+	OriginalPath  string      `json:"original_path"`
+	SHA256        string      `json:"sha256"`                 // 격리 디렉토리로 옮긴 파일의 해시 (복원할 때 확인)
+	EventSHA256   string      `json:"event_sha256,omitempty"` // 이벤트 처리 중 계산한 해시 (격리 전에 파일이 바뀌었으면 SHA256과 다름)
+	Size          int64       `json:"size"`
+	Mode          os.FileMode `json:"mode"` // 복원할 때 되돌릴 원래 권한
+	QuarantinedAt time.Time   `json:"quarantined_at"`
+	Rule          string      `json:"rule"` // 격리를 요청한 규칙 이름
+}
+
+// Store는 격리 디렉토리입니다. 여러 고루틴에서 동시에 사용할 수 있습니다.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+
+	// writeMetadata는 메타데이터를 기록합니다 (테스트에서 실패를 주입할 수 있음)
+	writeMetadata func(entry Entry) error
+}
+
+// Open은 격리 디렉토리를 만들고 관리자만 접근할 수 있도록 권한을 제한합니다
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("격리 디렉토리 생성 실패 %s: %v", dir, err)
+	}
+	if err := protectDir(dir); err != nil {
+		return nil, fmt.Errorf("격리 디렉토리 권한 설정 실패 %s: %v", dir, err)
+	}
+	s := &Store{dir: dir}
+	s.writeMetadata = s.writeEntry
+	return s, nil
+}
+
+// Dir은 격리 디렉토리 경로를 반환합니다
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Quarantine은 파일을 격리 디렉토리로 옮기고 메타데이터를 기록한 뒤 격리 항목 ID를 반환합니다.
+// 해시는 옮긴 파일에서 다시 계산하여 기록하고, eventSHA256(이벤트 처리 중 계산한 해시)은 참고용으로 따로 기록합니다.
+// 해시 계산이나 메타데이터 기록에 실패하면 파일을 원래 위치로 되돌립니다.
+func (s *Store) Quarantine(path, eventSHA256, rule string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("일반 파일이 아닙니다: %s", path)
+	}
+
+	id, err := newID(time.Now())
+	if err != nil {
+		return "", err
+	}
+	entry := Entry{
+		ID:            id,
+		OriginalPath:  path,
+		EventSHA256:   eventSHA256,
+		Size:          info.Size(),
+		Mode:          info.Mode().Perm(),
+		QuarantinedAt: time.Now(),
+		Rule:          rule,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload := s.payloadPath(id)
+	if err := moveFile(path, payload); err != nil {
+		return "", fmt.Errorf("파일을 격리 디렉토리로 옮길 수 없습니다: %v", err)
+	}
+	rollback := func() {
+		if err := moveFile(payload, path); err == nil {
+			unprotectFile(path, entry.Mode)
+		}
+	}
+	if err := protectFile(payload); err != nil {
+		rollback()
+		return "", fmt.Errorf("격리 파일 권한 설정 실패: %v", err)
+	}
+	// 이벤트의 해시는 격리하기 전에 계산한 것이므로 그 사이에 바뀐 파일을 복원할 때 검증할 수 있도록 옮긴 파일의 해시를 기록
+	if entry.SHA256, err = hashing.File(payload, 0); err != nil {
+		rollback()
+		return "", fmt.Errorf("격리 파일 해시 계산 실패: %v", err)
+	}
+	if info, err := os.Stat(payload); err == nil {
+		entry.Size = info.Size()
+	}
+	if err := s.writeMetadata(entry); err != nil {
+		rollback()
+		return "", err
+	}
+	return id, nil
+}
+
+// List는 격리 항목을 격리한 시각 순서로 반환합니다
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+func (s *Store) list() ([]Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("격리 디렉토리를 읽을 수 없습니다: %v", err)
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != metadataExt {
+			continue
+		}
+		entry, err := s.readEntry(strings.TrimSuffix(f.Name(), metadataExt))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].QuarantinedAt.Before(entries[j].QuarantinedAt) })
+	return entries, nil
+}
+
+// Get은 ID의 격리 항목을 반환합니다
+func (s *Store) Get(id string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readEntry(id)
+}
+
+// Restore는 격리한 파일을 target(비어 있으면 원래 경로)으로 되돌리고 격리 항목을 삭제합니다.
+// 대상 경로에 파일이 이미 있거나 격리된 파일의 해시가 메타데이터와 다르면 오류를 반환합니다.
+func (s *Store) Restore(id, target string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.readEntry(id)
+	if err != nil {
+		return entry, err
+	}
+	if target == "" {
+		target = entry.OriginalPath
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return entry, fmt.Errorf("대상 경로에 파일이 이미 있습니다: %s", target)
+	}
+
+	payload := s.payloadPath(id)
+	if entry.SHA256 != "" {
+		sum, err := hashing.File(payload, 0)
+		if err != nil {
+			return entry, fmt.Errorf("격리된 파일을 읽을 수 없습니다: %v", err)
+		}
+		if !strings.EqualFold(sum, entry.SHA256) {
+			return entry, fmt.Errorf("격리된 파일의 해시가 메타데이터와 다릅니다 (%s)", sum)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return entry, fmt.Errorf("디렉토리 생성 실패 %s: %v", filepath.Dir(target), err)
+	}
+	if err := moveFile(payload, target); err != nil {
+		return entry, fmt.Errorf("파일을 복원할 수 없습니다: %v", err)
+	}
+	if err := unprotectFile(target, entry.Mode); err != nil {
+		return entry, fmt.Errorf("파일을 복원했지만 권한을 되돌릴 수 없습니다: %v", err)
+	}
+	if err := os.Remove(s.metadataPath(id)); err != nil {
+		return entry, fmt.Errorf("파일을 복원했지만 격리 메타데이터를 삭제할 수 없습니다: %v", err)
+	}
+	return entry, nil
+}
+
+// Purge는 before 이전에 격리한 항목(before가 0이면 모두)을 영구 삭제하고 삭제한 항목을 반환합니다
+func (s *Store) Purge(before time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	var purged []Entry
+	for _, entry := range entries {
+		if !before.IsZero() && !entry.QuarantinedAt.Before(before) {
+			continue
+		}
+		if err := os.Remove(s.payloadPath(entry.ID)); err != nil && !os.IsNotExist(err) {
+			return purged, fmt.Errorf("격리 파일 삭제 실패 %s: %v", entry.ID, err)
+		}
+		if err := os.Remove(s.metadataPath(entry.ID)); err != nil {
+			return purged, fmt.Errorf("격리 메타데이터 삭제 실패 %s: %v", entry.ID, err)
+		}
+		purged = append(purged, entry)
+	}
+	return purged, nil
+}
+
+func (s *Store) payloadPath(id string) string {
+	return filepath.Join(s.dir, id+payloadExt)
+}
+
+func (s *Store) metadataPath(id string) string {
+	return filepath.Join(s.dir, id+metadataExt)
+}
+
+// readEntry는 ID의 메타데이터를 읽습니다
+func (s *Store) readEntry(id string) (Entry, error) {
+	var entry Entry
+	if !validID(id) {
+		return entry, fmt.Errorf("잘못된 격리 항목 ID입니다: %q", id)
+	}
+	data, err := os.ReadFile(s.metadataPath(id))
+	if os.IsNotExist(err) {
+		return entry, fmt.Errorf("격리 항목이 없습니다: %s", id)
+	}
+	if err != nil {
+		return entry, fmt.Errorf("격리 메타데이터를 읽을 수 없습니다 %s: %v", id, err)
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, fmt.Errorf("격리 메타데이터 형식 오류 %s: %v", id, err)
+	}
+	return entry, nil
+}
+
+// writeEntry는 메타데이터를 임시 파일에 쓴 뒤 이름을 바꿔 원자적으로 기록합니다
+func (s *Store) writeEntry(entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("격리 메타데이터 직렬화 실패: %v", err)
+	}
+	tmp := s.metadataPath(entry.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("격리 메타데이터 기록 실패: %v", err)
+	}
+	if err := os.Rename(tmp, s.metadataPath(entry.ID)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("격리 메타데이터 기록 실패: %v", err)
+	}
+	return nil
+}
+
+// newID는 격리 시각과 임의 값으로 ID를 만듭니다 (예: 20250101-090000-1a2b3c4d)
+func newID(now time.Time) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("격리 항목 ID 생성 실패: %v", err)
+	}
+	return now.Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// validID는 ID가 격리 디렉토리 밖의 경로를 가리키지 않는지 확인합니다
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// moveFile은 파일 이름을 바꾸고, 다른 볼륨이라 바꿀 수 없으면 복사한 뒤 원본을 삭제합니다
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	if err := os.Remove(src); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
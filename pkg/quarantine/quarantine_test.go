@@ -0,0 +1,236 @@
+package quarantine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/hashing"
+)
+
+// newTestStore는 임시 디렉토리에 격리 디렉토리를 만들고, 격리할 파일을 둘 디렉토리와 함께 반환합니다
+func newTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	root := t.TempDir()
+	s, err := Open(filepath.Join(root, DirName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	files := filepath.Join(root, "files")
+	if err := os.MkdirAll(files, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return s, files
+}
+
+// writeFile은 파일을 만들고 경로를 반환합니다
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestQuarantineRestore(t *testing.T) {
+	s, files := newTestStore(t)
+	path := writeFile(t, files, "setup.exe", "payload")
+	want, _ := hashing.File(path, 0)
+
+	id, err := s.Quarantine(path, "stale-event-hash", "night-download-exe")
+	if err != nil {
+		t.Fatalf("Quarantine: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("원래 경로에 파일이 남아 있습니다: %v", err)
+	}
+
+	entries, err := s.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List = %v, %v, want 1개", entries, err)
+	}
+	e := entries[0]
+	if e.ID != id || e.OriginalPath != path || e.Rule != "night-download-exe" || e.Size != int64(len("payload")) {
+		t.Errorf("격리 항목 = %+v", e)
+	}
+	// 해시는 이벤트의 해시가 아니라 옮긴 파일에서 계산
+	if e.SHA256 != want || e.EventSHA256 != "stale-event-hash" {
+		t.Errorf("SHA256 = %q, EventSHA256 = %q, want %q, stale-event-hash", e.SHA256, e.EventSHA256, want)
+	}
+
+	restored, err := s.Restore(id, "")
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.ID != id {
+		t.Errorf("복원한 항목 ID = %s, want %s", restored.ID, id)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "payload" {
+		t.Errorf("복원한 파일 = %q, %v", data, err)
+	}
+	if entries, _ := s.List(); len(entries) != 0 {
+		t.Errorf("복원 후 격리 항목이 남아 있습니다: %v", entries)
+	}
+	if _, err := s.Get(id); err == nil {
+		t.Error("복원한 항목은 Get에서 찾을 수 없어야 합니다")
+	}
+}
+
+func TestRestoreTargetExists(t *testing.T) {
+	s, files := newTestStore(t)
+	path := writeFile(t, files, "a.exe", "payload")
+	id, err := s.Quarantine(path, "", "r")
+	if err != nil {
+		t.Fatalf("Quarantine: %v", err)
+	}
+
+	// 원래 경로에 새 파일이 생겼으면 덮어쓰지 않음
+	writeFile(t, files, "a.exe", "new")
+	if _, err := s.Restore(id, ""); err == nil || !strings.Contains(err.Error(), "이미 있습니다") {
+		t.Errorf("Restore 오류 = %v, want 대상 경로에 파일이 이미 있음", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("기존 파일이 바뀌었습니다: %q", data)
+	}
+	if _, err := s.Get(id); err != nil {
+		t.Errorf("실패 후 격리 항목이 없어졌습니다: %v", err)
+	}
+
+	// 다른 경로로는 복원 가능 (디렉토리 생성)
+	target := filepath.Join(files, "restored", "a.exe")
+	if _, err := s.Restore(id, target); err != nil {
+		t.Fatalf("Restore(target): %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "payload" {
+		t.Errorf("복원한 파일 = %q", data)
+	}
+}
+
+func TestRestoreHashMismatch(t *testing.T) {
+	s, files := newTestStore(t)
+	id, err := s.Quarantine(writeFile(t, files, "a.exe", "payload"), "", "r")
+	if err != nil {
+		t.Fatalf("Quarantine: %v", err)
+	}
+	if err := os.WriteFile(s.payloadPath(id), []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Restore(id, ""); err == nil || !strings.Contains(err.Error(), "해시") {
+		t.Errorf("Restore 오류 = %v, want 해시 불일치", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	s, files := newTestStore(t)
+	var ids []string
+	for _, name := range []string{"old.exe", "mid.exe", "new.exe"} {
+		id, err := s.Quarantine(writeFile(t, files, name, name), "", "r")
+		if err != nil {
+			t.Fatalf("Quarantine: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	// 격리 시각을 하루씩 차이 나게 바꿈
+	now := time.Now()
+	for i, id := range ids {
+		e, _ := s.Get(id)
+		e.QuarantinedAt = now.Add(time.Duration(i-2) * 24 * time.Hour)
+		if err := s.writeEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purged, err := s.Purge(now.Add(-12 * time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(purged) != 2 || purged[0].ID != ids[0] || purged[1].ID != ids[1] {
+		t.Fatalf("before로 삭제한 항목 = %v, want %v", purged, ids[:2])
+	}
+	for _, id := range ids[:2] {
+		if _, err := os.Stat(s.payloadPath(id)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s 격리 파일이 남아 있습니다", id)
+		}
+	}
+	if entries, _ := s.List(); len(entries) != 1 || entries[0].ID != ids[2] {
+		t.Errorf("남은 항목 = %v, want [%s]", entries, ids[2])
+	}
+
+	// before가 0이면 모두 삭제
+	if purged, err := s.Purge(time.Time{}); err != nil || len(purged) != 1 {
+		t.Errorf("Purge(0) = %v, %v, want 1개", purged, err)
+	}
+	if files, _ := os.ReadDir(s.Dir()); len(files) != 0 {
+		t.Errorf("격리 디렉토리에 파일이 남아 있습니다: %v", files)
+	}
+}
+
+func TestValidID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"20250101-090000-1a2b3c4d", true},
+		{"", false},
+		{"../20250101-090000-1a2b3c4d", false},
+		{"..", false},
+		{`..\x`, false},
+		{"a/b", false},
+		{"20250101-090000-1A2B3C4D", false},
+	}
+	for _, tt := range tests {
+		if got := validID(tt.id); got != tt.want {
+			t.Errorf("validID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+
+	s, _ := newTestStore(t)
+	for _, id := range []string{"../x", "../../etc/passwd"} {
+		if _, err := s.Get(id); err == nil || !strings.Contains(err.Error(), "잘못된 격리 항목 ID") {
+			t.Errorf("Get(%q) 오류 = %v, want 잘못된 ID", id, err)
+		}
+		if _, err := s.Restore(id, ""); err == nil || !strings.Contains(err.Error(), "잘못된 격리 항목 ID") {
+			t.Errorf("Restore(%q) 오류 = %v, want 잘못된 ID", id, err)
+		}
+	}
+}
+
+func TestQuarantineRollback(t *testing.T) {
+	s, files := newTestStore(t)
+	path := writeFile(t, files, "a.exe", "payload")
+	os.Chmod(path, 0755)
+	s.writeMetadata = func(Entry) error { return errors.New("디스크 가득 참") }
+
+	if _, err := s.Quarantine(path, "", "r"); err == nil || !strings.Contains(err.Error(), "디스크 가득 참") {
+		t.Fatalf("Quarantine 오류 = %v, want 메타데이터 기록 실패", err)
+	}
+	// 파일은 원래 위치와 권한으로 되돌아가고 격리 디렉토리에는 아무것도 남지 않음
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("파일이 원래 위치로 돌아오지 않았습니다: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "payload" {
+		t.Errorf("되돌린 파일 = %q", data)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+		t.Errorf("되돌린 파일 권한 = %v, want 0755", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(s.Dir()); len(entries) != 0 {
+		t.Errorf("격리 디렉토리에 파일이 남아 있습니다: %v", entries)
+	}
+}
+
+func TestQuarantineNotRegular(t *testing.T) {
+	s, files := newTestStore(t)
+	if _, err := s.Quarantine(files, "", "r"); err == nil {
+		t.Error("디렉토리는 격리할 수 없어야 합니다")
+	}
+	if _, err := s.Quarantine(filepath.Join(files, "missing.exe"), "", "r"); err == nil {
+		t.Error("없는 파일은 격리할 수 없어야 합니다")
+	}
+}
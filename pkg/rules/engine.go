@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"windows_service_module/pkg/winsvc"
+)
+
+// Event는 규칙과 비교할 파일 이벤트입니다
+type Event struct {
+	Path      string
+	Operation string
+	FileType  string
+	Time      time.Time
+	Size      int64 // 파일 크기, 알 수 없으면 -1 (삭제된 파일 등)
+}
+
+// Alert는 log 동작이 만든 경고입니다
+type Alert struct {
+	Level   winsvc.Level
+	Rule    string
+	Message string
+}
+
+// Result는 이벤트와 일치한 규칙의 동작을 모은 결과입니다
+type Result struct {
+	Rules          []string // 일치한 규칙 이름 (순서대로)
+	Alerts         []Alert
+	Tags           []string
+	Sinks          []string // 이벤트를 보낼 싱크 이름
+	QuarantineRule string   // 격리를 요청한 첫 규칙 이름 (없으면 빈 문자열)
+	Suppress       bool     // 이벤트를 기록하지 않음
+}
+
+// Engine은 규칙 파일에서 읽은 규칙으로 이벤트를 평가합니다.
+// Evaluate는 이벤트 루프에서, Load는 서비스 루프에서 호출하므로 잠금으로 보호합니다.
+type Engine struct {
+	path string
+
+	mu        sync.RWMutex
+	rules     []*rule
+	needsSize bool
+}
+
+// NewEngine은 규칙이 없는 Engine을 생성합니다. Load로 파일을 읽습니다.
+func NewEngine(path string) *Engine {
+	return &Engine{path: path}
+}
+
+// Path는 규칙 파일 경로를 반환합니다
+func (e *Engine) Path() string {
+	return e.path
+}
+
+// Load는 규칙 파일을 다시 읽어 교체하고 사용 중인 규칙 수를 반환합니다.
+// 파일이 없으면 규칙을 모두 제거하며, 형식이나 검증 오류가 있으면 기존 규칙을 유지하고 오류를 반환합니다.
+func (e *Engine) Load() (int, error) {
+	data, err := os.ReadFile(e.path)
+	if os.IsNotExist(err) {
+		e.replace(nil)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("규칙 파일을 읽을 수 없습니다: %v", err)
+	}
+
+	file, err := Parse(filepath.Base(e.path), data)
+	if err != nil {
+		return 0, err
+	}
+	compiled, err := compile(file.Rules)
+	if err != nil {
+		return 0, err
+	}
+	e.replace(compiled)
+	return len(compiled), nil
+}
+
+func (e *Engine) replace(compiled []*rule) {
+	needsSize := false
+	for _, r := range compiled {
+		if r.minSize >= 0 || r.maxSize >= 0 {
+			needsSize = true
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules, e.needsSize = compiled, needsSize
+}
+
+// NeedsSize는 파일 크기 조건을 사용하는 규칙이 있는지 반환합니다 (없으면 크기를 확인하지 않아도 됨)
+func (e *Engine) NeedsSize() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.needsSize
+}
+
+// Sinks는 규칙의 sink 동작에 사용된 싱크 이름을 반환합니다
+func (e *Engine) Sinks() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, r := range e.rules {
+		for _, a := range r.actions {
+			if a.kind == ActionSink && !seen[a.value] {
+				seen[a.value] = true
+				names = append(names, a.value)
+			}
+		}
+	}
+	return names
+}
+
+// Evaluate는 이벤트와 일치하는 모든 규칙의 동작을 모읍니다
+func (e *Engine) Evaluate(event Event) Result {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var result Result
+	for _, r := range e.rules {
+		if !r.match(event) {
+			continue
+		}
+		result.Rules = append(result.Rules, r.name)
+		for _, a := range r.actions {
+			switch a.kind {
+			case ActionLog:
+				result.Alerts = append(result.Alerts, Alert{Level: a.level, Rule: r.name, Message: a.message})
+			case ActionTag:
+				result.Tags = appendUnique(result.Tags, a.value)
+			case ActionSink:
+				result.Sinks = appendUnique(result.Sinks, a.value)
+			case ActionQuarantine:
+				if result.QuarantineRule == "" {
+					result.QuarantineRule = r.name
+				}
+			case ActionSuppress:
+				result.Suppress = true
+			}
+		}
+	}
+	return result
+}
+
+// match는 이벤트가 규칙의 모든 조건을 만족하는지 확인합니다
+func (r *rule) match(event Event) bool {
+	if len(r.paths) > 0 {
+		matched := false
+		for _, g := range r.paths {
+			if g.Match(event.Path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.extensions != nil && !r.extensions[strings.ToLower(filepath.Ext(event.Path))] {
+		return false
+	}
+	if r.operations != nil && !r.operations[strings.ToUpper(event.Operation)] {
+		return false
+	}
+	if r.timeOfDay != nil {
+		t := event.Time.Local()
+		minute := t.Hour()*60 + t.Minute()
+		start, end := r.timeOfDay[0], r.timeOfDay[1]
+		var in bool
+		if start <= end {
+			in = minute >= start && minute < end
+		} else {
+			in = minute >= start || minute < end // 자정을 넘는 시간대
+		}
+		if !in {
+			return false
+		}
+	}
+	if r.minSize >= 0 && (event.Size < 0 || event.Size < r.minSize) {
+		return false
+	}
+	if r.maxSize >= 0 && (event.Size < 0 || event.Size > r.maxSize) {
+		return false
+	}
+	return true
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,283 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/winsvc"
+)
+
+// newTestEngine은 규칙을 컴파일하여 Engine을 생성합니다
+func newTestEngine(t *testing.T, rules ...Rule) *Engine {
+	t.Helper()
+	compiled, err := compile(rules)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	e := NewEngine("")
+	e.replace(compiled)
+	return e
+}
+
+// clock은 오늘 로컬 시각 hh:mm을 반환합니다
+func clock(hour, minute int) time.Time {
+	return time.Date(2025, 1, 1, hour, minute, 0, 0, time.Local)
+}
+
+var logAction = []Action{{Type: ActionLog}}
+
+func TestMatch(t *testing.T) {
+	event := Event{Path: "/home/user/Downloads/setup.exe", Operation: "CREATE", Time: clock(12, 0), Size: 2 << 20}
+	tests := []struct {
+		name  string
+		match Match
+		event Event
+		want  bool
+	}{
+		{"조건 없음", Match{}, event, true},
+		{"glob 일치", Match{Paths: []string{"/home/*/Downloads/**"}}, event, true},
+		{"glob 중 하나", Match{Paths: []string{"/tmp/**", "**/Downloads/*.exe"}}, event, true},
+		{"glob 불일치", Match{Paths: []string{"/home/*/Documents/**"}}, event, false},
+		{"파일 이름 glob", Match{Paths: []string{"setup.*"}}, event, true},
+		{"확장자", Match{Extensions: []string{"msi", ".EXE"}}, event, true},
+		{"확장자 불일치", Match{Extensions: []string{".dll"}}, event, false},
+		{"작업", Match{Operations: []string{"write", "create"}}, event, true},
+		{"작업 불일치", Match{Operations: []string{"REMOVE"}}, event, false},
+		{"최소 크기", Match{MinSize: "1MB"}, event, true},
+		{"최소 크기 미만", Match{MinSize: "3MB"}, event, false},
+		{"최대 크기", Match{MaxSize: "2MB"}, event, true},
+		{"최대 크기 초과", Match{MaxSize: "1MB"}, event, false},
+		{"크기 모름", Match{MaxSize: "1GB"}, Event{Path: event.Path, Operation: "REMOVE", Size: -1}, false},
+		{"모든 조건", Match{
+			Paths:      []string{"**/Downloads/**"},
+			Extensions: []string{".exe"},
+			Operations: []string{"CREATE"},
+			TimeOfDay:  &TimeRange{Start: "09:00", End: "18:00"},
+			MinSize:    "1KB",
+			MaxSize:    "10MB",
+		}, event, true},
+		{"조건 하나 불일치", Match{Extensions: []string{".exe"}, Operations: []string{"WRITE"}}, event, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(t, Rule{Name: "r", Match: tt.match, Actions: logAction})
+			if got := len(e.Evaluate(tt.event).Rules) == 1; got != tt.want {
+				t.Errorf("일치 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchTimeOfDay(t *testing.T) {
+	tests := []struct {
+		start, end string
+		at         time.Time
+		want       bool
+	}{
+		{"09:00", "18:00", clock(9, 0), true}, // start 포함
+		{"09:00", "18:00", clock(17, 59), true},
+		{"09:00", "18:00", clock(18, 0), false}, // end 제외
+		{"09:00", "18:00", clock(8, 59), false},
+		// 자정을 넘는 시간대
+		{"22:00", "06:00", clock(22, 0), true},
+		{"22:00", "06:00", clock(23, 59), true},
+		{"22:00", "06:00", clock(0, 0), true},
+		{"22:00", "06:00", clock(5, 59), true},
+		{"22:00", "06:00", clock(6, 0), false},
+		{"22:00", "06:00", clock(12, 0), false},
+		{"22:00", "06:00", clock(21, 59), false},
+		{"00:00", "24:00", clock(23, 59), true},
+	}
+	for _, tt := range tests {
+		e := newTestEngine(t, Rule{Name: "r", Match: Match{TimeOfDay: &TimeRange{Start: tt.start, End: tt.end}}, Actions: logAction})
+		if got := len(e.Evaluate(Event{Path: "/a.exe", Time: tt.at}).Rules) == 1; got != tt.want {
+			t.Errorf("%s~%s, %s: 일치 = %v, want %v", tt.start, tt.end, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateMultipleRules(t *testing.T) {
+	e := newTestEngine(t,
+		Rule{Name: "exe", Description: "실행 파일", Match: Match{Extensions: []string{".exe"}}, Actions: []Action{
+			{Type: ActionLog, Level: "error"},
+			{Type: ActionTag, Tag: "exe"},
+			{Type: ActionSink, Sink: "soc"},
+		}},
+		Rule{Name: "downloads", Match: Match{Paths: []string{"**/Downloads/**"}}, Actions: []Action{
+			{Type: ActionLog, Level: "info", Message: "다운로드"},
+			{Type: ActionTag, Tag: "exe"},
+			{Type: ActionTag, Tag: "download"},
+			{Type: ActionSink, Sink: "soc"},
+			{Type: ActionQuarantine},
+		}},
+		Rule{Name: "later-quarantine", Match: Match{Operations: []string{"CREATE"}}, Actions: []Action{{Type: ActionQuarantine}}},
+		Rule{Name: "other", Match: Match{Extensions: []string{".dll"}}, Actions: []Action{{Type: ActionSuppress}}},
+	)
+
+	got := e.Evaluate(Event{Path: "/home/u/Downloads/a.exe", Operation: "CREATE", Time: clock(12, 0), Size: -1})
+	want := Result{
+		Rules: []string{"exe", "downloads", "later-quarantine"},
+		Alerts: []Alert{
+			{Level: winsvc.LogError, Rule: "exe", Message: "실행 파일"},
+			{Level: winsvc.LogInfo, Rule: "downloads", Message: "다운로드"},
+		},
+		Tags:           []string{"exe", "download"},
+		Sinks:          []string{"soc"},
+		QuarantineRule: "downloads", // 격리를 요청한 첫 규칙
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := e.Evaluate(Event{Path: "/lib/a.dll", Operation: "WRITE"}); !got.Suppress || len(got.Rules) != 1 {
+		t.Errorf("suppress 규칙 결과 = %+v", got)
+	}
+	if got := e.Evaluate(Event{Path: "/a.txt", Operation: "WRITE"}); len(got.Rules) != 0 {
+		t.Errorf("일치하는 규칙이 없어야 합니다: %+v", got)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	e := newTestEngine(t,
+		Rule{Name: "off", Disabled: true, Match: Match{MinSize: "1KB"}, Actions: []Action{{Type: ActionSink, Sink: "soc"}}},
+		Rule{Name: "on", Actions: logAction},
+	)
+	if got := e.Evaluate(Event{Path: "/a.exe", Size: 4096}).Rules; !reflect.DeepEqual(got, []string{"on"}) {
+		t.Errorf("일치한 규칙 = %v, want [on]", got)
+	}
+	// 사용하지 않는 규칙의 조건과 동작은 반영하지 않음
+	if e.NeedsSize() || len(e.Sinks()) != 0 {
+		t.Errorf("NeedsSize = %v, Sinks = %v, want false, []", e.NeedsSize(), e.Sinks())
+	}
+
+	// 사용하지 않는 규칙도 검증함
+	err := Check([]Rule{{Name: "off", Disabled: true, Actions: []Action{{Type: "delete"}}}})
+	if err == nil {
+		t.Error("disabled 규칙의 오류도 반환해야 합니다")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules := []Rule{
+		{Name: "ok", Match: Match{Operations: []string{"create", "RENAME", "chmod"}}, Actions: logAction},
+		{Name: "", Actions: logAction},
+		{Name: "ok", Actions: logAction},
+		{Name: "no-actions"},
+		{Name: "bad-match", Match: Match{
+			Paths:      []string{" "},
+			Extensions: []string{"*.exe"},
+			Operations: []string{"CREAT", "WRITE"},
+			TimeOfDay:  &TimeRange{Start: "25:00", End: "06:00"},
+			MinSize:    "1XB",
+			MaxSize:    "-1",
+		}, Actions: logAction},
+		{Name: "bad-actions", Actions: []Action{
+			{Type: ActionLog, Level: "fatal"},
+			{Type: ActionTag},
+			{Type: ActionSink, Sink: " "},
+			{Type: "delete"},
+		}},
+	}
+	err := Check(rules)
+	if err == nil {
+		t.Fatal("Check는 오류를 반환해야 합니다")
+	}
+
+	// 모든 문제를 하나의 오류로 모아 반환
+	msg := err.Error()
+	wantProblems := []string{
+		"rules[1]: name이 비어 있습니다",
+		"rules[2] (ok): 같은 이름의 규칙이 이미 있습니다",
+		"rules[3] (no-actions): actions가 비어 있습니다",
+		"rules[4] (bad-match): match.paths",
+		"rules[4] (bad-match): match.extensions",
+		`rules[4] (bad-match): match.operations: 알 수 없는 작업입니다: "CREAT"`,
+		"rules[4] (bad-match): match.time_of_day.start",
+		"rules[4] (bad-match): match.min_size",
+		"rules[4] (bad-match): match.max_size",
+		"rules[5] (bad-actions): actions[0]",
+		"rules[5] (bad-actions): actions[1]",
+		"rules[5] (bad-actions): actions[2]",
+		"rules[5] (bad-actions): actions[3]",
+	}
+	if !strings.HasPrefix(msg, "규칙 검증 실패 (13개 오류)") {
+		t.Errorf("오류 요약 = %q", strings.SplitN(msg, "\n", 2)[0])
+	}
+	for _, want := range wantProblems {
+		if !strings.Contains(msg, want) {
+			t.Errorf("오류에 %q가 없습니다:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "rules[0]") {
+		t.Errorf("올바른 규칙이 오류에 포함되었습니다:\n%s", msg)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	e := NewEngine(path)
+
+	// 파일이 없으면 규칙 없음
+	if n, err := e.Load(); n != 0 || err != nil {
+		t.Fatalf("Load = %d, %v, want 0, nil", n, err)
+	}
+
+	os.WriteFile(path, []byte(`
+rules:
+  - name: big
+    match: {min_size: 1MB}
+    actions: [{type: sink, sink: soc}]
+  - name: off
+    disabled: true
+    actions: [{type: suppress}]
+`), 0644)
+	if n, err := e.Load(); n != 1 || err != nil {
+		t.Fatalf("Load = %d, %v, want 1, nil", n, err)
+	}
+	if !e.NeedsSize() || !reflect.DeepEqual(e.Sinks(), []string{"soc"}) {
+		t.Errorf("NeedsSize = %v, Sinks = %v", e.NeedsSize(), e.Sinks())
+	}
+
+	// 검증 오류가 있으면 기존 규칙 유지
+	os.WriteFile(path, []byte("rules:\n  - name: bad\n    match: {operations: [CREAT]}\n    actions: [{type: log}]\n"), 0644)
+	if _, err := e.Load(); err == nil {
+		t.Fatal("잘못된 규칙 파일은 오류를 반환해야 합니다")
+	}
+	if got := e.Evaluate(Event{Path: "/a.exe", Size: 2 << 20}).Rules; !reflect.DeepEqual(got, []string{"big"}) {
+		t.Errorf("오류 후 규칙 = %v, want [big]", got)
+	}
+
+	// 알 수 없는 항목은 형식 오류
+	os.WriteFile(path, []byte("rules:\n  - name: x\n    when: {}\n    actions: [{type: log}]\n"), 0644)
+	if _, err := e.Load(); err == nil || !strings.Contains(err.Error(), "형식 오류") {
+		t.Errorf("Load 오류 = %v, want 형식 오류", err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"512", 512, true},
+		{"512B", 512, true},
+		{"64kb", 64 << 10, true},
+		{" 10 MB ", 10 << 20, true},
+		{"1GB", 1 << 30, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"1.5MB", 0, false},
+		{"1TB", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/winsvc"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File은 규칙 파일(rules.yaml 또는 rules.json)의 형식입니다
+type File struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule은 이벤트 조건과 조건이 맞을 때 실행할 동작입니다.
+// 일치하는 규칙은 파일에 적힌 순서대로 모두 적용됩니다.
+type Rule struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Match       Match    `json:"match" yaml:"match"`
+	Actions     []Action `json:"actions" yaml:"actions"`
+}
+
+// Match는 규칙 조건입니다. 비어 있는 조건은 확인하지 않으며, 지정한 조건을 모두 만족해야 일치합니다.
+type Match struct {
+	Paths      []string   `json:"paths,omitempty" yaml:"paths,omitempty"`           // glob 패턴 중 하나와 일치 (file_filters 패턴과 같은 문법)
+	Extensions []string   `json:"extensions,omitempty" yaml:"extensions,omitempty"` // 확장자 중 하나
+	Operations []string   `json:"operations,omitempty" yaml:"operations,omitempty"` // CREATE, WRITE, REMOVE 등
+	TimeOfDay  *TimeRange `json:"time_of_day,omitempty" yaml:"time_of_day,omitempty"`
+	MinSize    string     `json:"min_size,omitempty" yaml:"min_size,omitempty"` // 예: "1MB", 파일 크기를 알 수 없으면 일치하지 않음
+	MaxSize    string     `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+}
+
+// TimeRange는 로컬 시각 기준 하루 중 시간대입니다 ("HH:MM", Start 포함, End 제외).
+// End가 Start보다 이르면 자정을 넘는 시간대입니다 (예: 22:00 ~ 06:00).
+type TimeRange struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+}
+
+// Action은 규칙이 일치했을 때 실행할 동작입니다
+type Action struct {
+	Type    string `json:"type" yaml:"type"`                           // log, tag, sink, quarantine, suppress
+	Level   string `json:"level,omitempty" yaml:"level,omitempty"`     // log: debug, info, warning, error
+	Message string `json:"message,omitempty" yaml:"message,omitempty"` // log: 로그 메시지 (기본값은 규칙 설명)
+	Tag     string `json:"tag,omitempty" yaml:"tag,omitempty"`         // tag: 이벤트에 붙일 태그
+	Sink    string `json:"sink,omitempty" yaml:"sink,omitempty"`       // sink: 이벤트를 보낼 싱크 이름
+}
+
+// 동작 종류
+const (
+	ActionLog        = "log"
+	ActionTag        = "tag"
+	ActionSink       = "sink"
+	ActionQuarantine = "quarantine"
+	ActionSuppress   = "suppress"
+)
+
+// Operations는 match.operations에 쓸 수 있는 작업 종류입니다 (파일 감시와 보완 검사가 만드는 작업)
+var Operations = []string{"CREATE", "WRITE", "REMOVE", "RENAME", "CHMOD"}
+
+// Parse는 규칙 파일을 읽습니다. 확장자가 .json이면 JSON, 그 밖에는 YAML로 해석하며 알 수 없는 항목은 오류입니다.
+func Parse(name string, data []byte) (File, error) {
+	var file File
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return file, fmt.Errorf("규칙 파일 형식 오류 %s: %v", name, err)
+		}
+		return file, nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) { // 빈 파일은 규칙 없음
+		return file, fmt.Errorf("규칙 파일 형식 오류 %s: %v", name, err)
+	}
+	return file, nil
+}
+
+// rule은 컴파일된 규칙입니다
+type rule struct {
+	name        string
+	description string
+	paths       []*filter.Glob
+	extensions  map[string]bool
+	operations  map[string]bool
+	timeOfDay   *[2]int // 자정부터의 분 (시작, 끝)
+	minSize     int64   // -1이면 조건 없음
+	maxSize     int64
+	actions     []action
+}
+
+type action struct {
+	kind    string
+	level   winsvc.Level
+	message string
+	value   string // tag 또는 sink 이름
+}
+
+// Check는 규칙을 검증합니다. 문제가 있으면 모든 문제를 규칙 번호, 이름과 함께 하나의 오류로 반환합니다.
+func Check(rules []Rule) error {
+	_, err := compile(rules)
+	return err
+}
+
+// compile은 규칙을 검증하고 사용하지 않는(disabled) 규칙을 제외하여 컴파일합니다
+func compile(rules []Rule) ([]*rule, error) {
+	var compiled []*rule
+	var problems []string
+	names := make(map[string]bool)
+
+	for i, r := range rules {
+		where := fmt.Sprintf("rules[%d]", i)
+		if r.Name != "" {
+			where += " (" + r.Name + ")"
+		}
+		fail := func(format string, args ...interface{}) {
+			problems = append(problems, where+": "+fmt.Sprintf(format, args...))
+		}
+
+		if strings.TrimSpace(r.Name) == "" {
+			fail("name이 비어 있습니다")
+		} else if names[r.Name] {
+			fail("같은 이름의 규칙이 이미 있습니다")
+		}
+		names[r.Name] = true
+		if len(r.Actions) == 0 {
+			fail("actions가 비어 있습니다")
+		}
+
+		c := &rule{name: r.Name, description: r.Description, minSize: -1, maxSize: -1}
+		for _, src := range r.Match.Paths {
+			g, err := filter.CompileGlob(src)
+			if err != nil {
+				fail("match.paths: %v", err)
+				continue
+			}
+			c.paths = append(c.paths, g)
+		}
+		if len(r.Match.Extensions) > 0 {
+			c.extensions = make(map[string]bool)
+			for _, ext := range r.Match.Extensions {
+				if err := filter.ValidateExtension(ext); err != nil {
+					fail("match.extensions: %v", err)
+					continue
+				}
+				ext = strings.ToLower(strings.TrimSpace(ext))
+				if !strings.HasPrefix(ext, ".") {
+					ext = "." + ext
+				}
+				c.extensions[ext] = true
+			}
+		}
+		if len(r.Match.Operations) > 0 {
+			c.operations = make(map[string]bool)
+			for _, op := range r.Match.Operations {
+				op = strings.ToUpper(strings.TrimSpace(op))
+				if !knownOperation(op) {
+					fail("match.operations: 알 수 없는 작업입니다: %q (%s)", op, strings.Join(Operations, ", "))
+					continue
+				}
+				c.operations[op] = true
+			}
+		}
+		if tr := r.Match.TimeOfDay; tr != nil {
+			start, err1 := parseClock(tr.Start)
+			end, err2 := parseClock(tr.End)
+			switch {
+			case err1 != nil:
+				fail("match.time_of_day.start: %v", err1)
+			case err2 != nil:
+				fail("match.time_of_day.end: %v", err2)
+			default:
+				c.timeOfDay = &[2]int{start, end}
+			}
+		}
+		var err error
+		if r.Match.MinSize != "" {
+			if c.minSize, err = ParseSize(r.Match.MinSize); err != nil {
+				fail("match.min_size: %v", err)
+			}
+		}
+		if r.Match.MaxSize != "" {
+			if c.maxSize, err = ParseSize(r.Match.MaxSize); err != nil {
+				fail("match.max_size: %v", err)
+			}
+		}
+
+		for j, a := range r.Actions {
+			compiledAction, err := compileAction(a, r)
+			if err != nil {
+				fail("actions[%d]: %v", j, err)
+				continue
+			}
+			c.actions = append(c.actions, compiledAction)
+		}
+
+		if !r.Disabled {
+			compiled = append(compiled, c)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("규칙 검증 실패 (%d개 오류)\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+	}
+	return compiled, nil
+}
+
+func compileAction(a Action, r Rule) (action, error) {
+	c := action{kind: strings.ToLower(strings.TrimSpace(a.Type))}
+	switch c.kind {
+	case ActionLog:
+		level := a.Level
+		if level == "" {
+			level = "warning"
+		}
+		l, err := winsvc.ParseLevel(level)
+		if err != nil {
+			return c, err
+		}
+		c.level = l
+		c.message = a.Message
+		if c.message == "" {
+			c.message = r.Description
+		}
+		if c.message == "" {
+			c.message = "규칙과 일치하는 파일 이벤트"
+		}
+	case ActionTag:
+		if strings.TrimSpace(a.Tag) == "" {
+			return c, fmt.Errorf("tag 동작에는 tag가 필요합니다")
+		}
+		c.value = strings.TrimSpace(a.Tag)
+	case ActionSink:
+		if strings.TrimSpace(a.Sink) == "" {
+			return c, fmt.Errorf("sink 동작에는 sink 이름이 필요합니다")
+		}
+		c.value = strings.TrimSpace(a.Sink)
+	case ActionQuarantine, ActionSuppress:
+	default:
+		return c, fmt.Errorf("알 수 없는 동작입니다: %q (log, tag, sink, quarantine, suppress)", a.Type)
+	}
+	return c, nil
+}
+
+func knownOperation(op string) bool {
+	for _, known := range Operations {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock은 "HH:MM"을 자정부터의 분으로 변환합니다
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if !ok || err1 != nil || err2 != nil || hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("HH:MM 형식이어야 합니다 (현재 값: %q)", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// ParseSize는 "512", "64KB", "10MB", "1GB" 형식의 크기를 바이트로 변환합니다 (1KB = 1024바이트)
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		value  int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.value
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("크기 형식 오류입니다 (예: 512KB, 10MB)")
+	}
+	return n * multiplier, nil
+}
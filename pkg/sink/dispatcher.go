@@ -0,0 +1,176 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull은 싱크의 전송 대기열이 가득 차 이벤트를 버렸음을 나타냅니다
+var ErrQueueFull = errors.New("싱크 전송 대기열이 가득 참")
+
+// DefaultCloseTimeout은 Close가 대기열에 남은 이벤트를 보내며 기다리는 최대 시간입니다
+const DefaultCloseTimeout = 5 * time.Second
+
+// Output은 Dispatcher에 추가할 싱크 하나의 설정입니다
+type Output struct {
+	Sink      EventSink
+	QueueSize int  // 전송 대기열 크기 (0이면 DefaultQueueSize)
+	RulesOnly bool // 규칙의 sink 동작이 지정한 이벤트만 보냄
+}
+
+// output은 싱크 하나와 그 전송 대기열입니다
+type output struct {
+	sink      EventSink
+	rulesOnly bool
+	queue     chan Event
+	dropped   atomic.Uint64
+}
+
+// Dispatcher는 이벤트를 싱크마다 별도 대기열과 고루틴으로 보냅니다.
+// Send는 기다리지 않으므로 느린 웹훅이나 끊어진 syslog 서버가 이벤트 처리나 다른 싱크를 막지 않습니다.
+type Dispatcher struct {
+	outputs []*output
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	// OnError는 전송 실패를 전달받습니다 (nil이면 무시). 싱크 고루틴에서 호출됩니다.
+	OnError func(sink string, err error)
+	// Observe는 전송 결과를 전달받습니다 (nil이면 무시). 버린 이벤트는 ErrQueueFull입니다.
+	Observe func(sink string, err error)
+
+	closeOnce sync.Once
+}
+
+// NewDispatcher는 싱크마다 전송 대기열을 가진 Dispatcher를 생성합니다. Start로 전송을 시작합니다.
+func NewDispatcher(outputs []Output) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{ctx: ctx, cancel: cancel}
+	for _, o := range outputs {
+		size := DefaultQueueSize
+		if o.QueueSize > 0 {
+			size = o.QueueSize
+		}
+		d.outputs = append(d.outputs, &output{sink: o.Sink, rulesOnly: o.RulesOnly, queue: make(chan Event, size)})
+	}
+	return d
+}
+
+// Start는 싱크마다 전송 고루틴을 시작합니다
+func (d *Dispatcher) Start() {
+	for _, o := range d.outputs {
+		d.wg.Add(1)
+		go d.run(o)
+	}
+}
+
+// Send는 이벤트를 rules_only가 아닌 모든 싱크와 routes에 이름이 있는 싱크의 대기열에 넣습니다.
+// 여러 고루틴에서 동시에 호출할 수 있습니다.
+func (d *Dispatcher) Send(event Event, routes ...string) {
+	for _, o := range d.outputs {
+		if !o.rulesOnly || contains(routes, o.sink.Name()) {
+			d.enqueue(o, event)
+		}
+	}
+}
+
+// SendTo는 이름이 name인 싱크에만 이벤트를 보내고, 해당 싱크가 없으면 false를 반환합니다
+func (d *Dispatcher) SendTo(name string, event Event) bool {
+	for _, o := range d.outputs {
+		if o.sink.Name() == name {
+			d.enqueue(o, event)
+			return true
+		}
+	}
+	return false
+}
+
+// Names는 싱크 이름을 추가한 순서대로 반환합니다
+func (d *Dispatcher) Names() []string {
+	names := make([]string, 0, len(d.outputs))
+	for _, o := range d.outputs {
+		names = append(names, o.sink.Name())
+	}
+	return names
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dispatcher) enqueue(o *output, event Event) {
+	select {
+	case o.queue <- event:
+	default:
+		o.dropped.Add(1)
+		d.observe(o.sink.Name(), ErrQueueFull)
+	}
+}
+
+// Dropped는 대기열이 가득 차 버린 이벤트 수를 싱크 이름별로 반환합니다
+func (d *Dispatcher) Dropped() map[string]uint64 {
+	dropped := make(map[string]uint64, len(d.outputs))
+	for _, o := range d.outputs {
+		dropped[o.sink.Name()] = o.dropped.Load()
+	}
+	return dropped
+}
+
+// Len은 싱크 수를 반환합니다
+func (d *Dispatcher) Len() int {
+	return len(d.outputs)
+}
+
+// Close는 대기열에 남은 이벤트를 timeout 동안 보낸 뒤, 남은 재시도를 취소하고 싱크를 닫습니다.
+// Send 호출이 모두 끝난 뒤에 호출해야 합니다.
+func (d *Dispatcher) Close(timeout time.Duration) {
+	d.closeOnce.Do(func() {
+		for _, o := range d.outputs {
+			close(o.queue)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			d.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			d.cancel()
+			<-done
+		}
+		d.cancel()
+
+		for _, o := range d.outputs {
+			if err := o.sink.Close(); err != nil && d.OnError != nil {
+				d.OnError(o.sink.Name(), err)
+			}
+		}
+	})
+}
+
+func (d *Dispatcher) run(o *output) {
+	defer d.wg.Done()
+	for event := range o.queue {
+		err := o.sink.Send(d.ctx, event)
+		d.observe(o.sink.Name(), err)
+		if err != nil && d.OnError != nil {
+			d.OnError(o.sink.Name(), err)
+		}
+	}
+}
+
+func (d *Dispatcher) observe(name string, err error) {
+	if d.Observe != nil {
+		d.Observe(name, err)
+	}
+}
@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File은 이벤트를 JSON Lines 파일에 한 줄씩 추가하는 싱크입니다.
+// SIEM 수집기(예: Filebeat)가 파일을 따라 읽는 구성에 사용합니다.
+type File struct {
+	name string
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFile은 파일을 추가 모드로 열어 싱크를 생성합니다 (파일과 디렉토리가 없으면 생성)
+func NewFile(config Config) (*File, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("파일 싱크 경로가 비어 있습니다")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, fmt.Errorf("파일 싱크 디렉토리 생성 실패: %v", err)
+	}
+	f, err := os.OpenFile(config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("파일 싱크를 열 수 없습니다: %v", err)
+	}
+	return &File{name: config.Name, path: config.Path, f: f}, nil
+}
+
+func (s *File) Name() string {
+	return s.name
+}
+
+// Send는 이벤트를 한 줄로 기록합니다. 한 번의 Write로 기록하므로 다른 프로그램이 읽는 중에도 줄이 섞이지 않습니다.
+func (s *File) Send(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("이벤트 직렬화 실패: %v", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return fmt.Errorf("파일 싱크가 닫혔습니다: %s", s.path)
+	}
+	if _, err := s.f.Write(line); err != nil {
+		return fmt.Errorf("파일 싱크 쓰기 실패 %s: %v", s.path, err)
+	}
+	return nil
+}
+
+func (s *File) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
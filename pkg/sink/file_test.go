@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readLines는 JSONL 파일의 각 줄을 Event로 읽습니다
+func readLines(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("JSON 한 줄이 아닙니다: %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestFileJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "events.jsonl")
+	s, err := NewFile(Config{Type: TypeFile, Name: "file", Path: path})
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+
+	events := []Event{
+		{Timestamp: testTime, Path: `C:\a.exe`, Operation: "CREATE", Message: "첫 줄\n두 번째 줄", Count: 1},
+		{Timestamp: testTime, Path: "/b.dll", Operation: "WRITE", Count: 4,
+			Alerts: []Alert{{Level: "ERROR", Source: "blocklist", Message: "차단"}}},
+	}
+	for _, event := range events {
+		if err := s.Send(context.Background(), event); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := s.Send(context.Background(), events[0]); err == nil {
+		t.Error("닫힌 싱크의 Send는 오류를 반환해야 합니다")
+	}
+
+	got := readLines(t, path)
+	if len(got) != len(events) {
+		t.Fatalf("%d줄, want %d줄", len(got), len(events))
+	}
+	for i, event := range events {
+		if got[i].Path != event.Path || got[i].Message != event.Message || got[i].Count != event.Count ||
+			!got[i].Timestamp.Equal(event.Timestamp) || len(got[i].Alerts) != len(event.Alerts) {
+			t.Errorf("줄 %d = %+v, want %+v", i, got[i], event)
+		}
+	}
+
+	// 다시 열면 기존 내용 뒤에 추가
+	s, err = NewFile(Config{Type: TypeFile, Name: "file", Path: path})
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+	s.Send(context.Background(), events[0])
+	s.Close()
+	if got := readLines(t, path); len(got) != 3 {
+		t.Errorf("다시 연 뒤 %d줄, want 3줄", len(got))
+	}
+}
@@ -0,0 +1,171 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Event는 싱크로 보내는 파일 이벤트입니다 (웹훅 본문과 JSONL 파일의 한 줄)
+type Event struct {
+	Timestamp     time.Time `json:"timestamp"`      // 첫 이벤트 시각
+	LastTimestamp time.Time `json:"last_timestamp"` // 마지막 이벤트 시각 (합친 이벤트)
+	Count         int       `json:"count"`          // 합친 이벤트 수
+	Host          string    `json:"host"`
+	Service       string    `json:"service"`
+	Level         string    `json:"level"` // INFO, WARNING, ERROR (경고 중 가장 높은 수준)
+	Message       string    `json:"message"`
+	Path          string    `json:"path"`
+	Operation     string    `json:"operation"`
+	FileType      string    `json:"file_type"`
+	SHA256        string    `json:"sha256,omitempty"`
+	HashError     string    `json:"hash_error,omitempty"`
+	Alerts        []Alert   `json:"alerts,omitempty"`
+	Rules         []string  `json:"rules,omitempty"` // 일치한 규칙 이름
+	Tags          []string  `json:"tags,omitempty"`  // 규칙이 붙인 태그
+	// 서비스가 중지된 동안 변경되어 시작 시 보완 검사로 감지한 이벤트 (Timestamp는 파일의 수정/생성 시각)
+	OfflineDetected bool `json:"offline_detected,omitempty"`
+}
+
+// Alert는 이벤트에서 발생한 경고입니다 (예: 차단 목록 해시 일치)
+type Alert struct {
+	Level   string `json:"level"`
+	Source  string `json:"source"`
+	Label   string `json:"label,omitempty"`
+	Message string `json:"message"`
+}
+
+// EventSink는 파일 이벤트를 외부로 보내는 출력입니다.
+// Send는 Dispatcher의 싱크별 고루틴에서 순서대로 호출되며, ctx가 취소되면 재시도를 멈추고 돌아와야 합니다.
+type EventSink interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+	Close() error
+}
+
+// 싱크 종류
+const (
+	TypeWebhook = "webhook"
+	TypeSyslog  = "syslog"
+	TypeFile    = "file"
+)
+
+// Config는 싱크 하나의 설정입니다. Type에 따라 사용하는 필드가 다릅니다.
+type Config struct {
+	Type string `json:"type"` // webhook, syslog, file
+	Name string `json:"name"` // 로그와 메트릭에 표시할 이름 (비어 있으면 종류와 순서로 생성)
+
+	// webhook: JSON POST
+	URL          string            `json:"url,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`        // 추가 요청 헤더 (예: Authorization)
+	TimeoutMs    int               `json:"timeout_ms,omitempty"`     // 요청 하나의 제한 시간
+	Retries      int               `json:"retries,omitempty"`        // 실패 시 다시 시도하는 횟수
+	RetryDelayMs int               `json:"retry_delay_ms,omitempty"` // 첫 재시도 대기 시간, 재시도마다 두 배로 늘어남
+
+	// syslog: RFC 5424
+	Network  string `json:"network,omitempty"`  // udp, tcp
+	Address  string `json:"address,omitempty"`  // 호스트:포트
+	Facility string `json:"facility,omitempty"` // 기본값 local0
+	AppName  string `json:"app_name,omitempty"` // 기본값 서비스 이름
+
+	EnterpriseID string `json:"enterprise_id,omitempty"` // 구조화 데이터 ID의 IANA 기업 번호, 기본값 32473 (RFC 5612 문서용)
+
+	// file: JSON Lines
+	Path string `json:"path,omitempty"`
+
+	QueueSize int  `json:"queue_size,omitempty"` // 전송 대기열 크기, 가득 차면 이벤트를 버림
+	RulesOnly bool `json:"rules_only,omitempty"` // true이면 규칙의 sink 동작이 지정한 이벤트만 받음
+}
+
+// 기본값
+const (
+	DefaultQueueSize    = 1000
+	DefaultTimeoutMs    = 5000
+	DefaultRetryDelayMs = 1000
+	DefaultFacility     = "local0"
+)
+
+// New는 설정에 맞는 싱크를 생성합니다. appName은 syslog app_name의 기본값입니다.
+func New(config Config, appName string) (EventSink, error) {
+	switch config.Type {
+	case TypeWebhook:
+		return NewWebhook(config)
+	case TypeSyslog:
+		if config.AppName == "" {
+			config.AppName = appName
+		}
+		return NewSyslog(config)
+	case TypeFile:
+		return NewFile(config)
+	default:
+		return nil, fmt.Errorf("알 수 없는 싱크 종류입니다: %q (webhook, syslog, file)", config.Type)
+	}
+}
+
+// DisplayName은 설정의 이름을 반환합니다. 비어 있으면 "<종류>-<순서>"입니다.
+func (c Config) DisplayName(index int) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s-%d", c.Type, index+1)
+}
+
+// Validate는 종류별 필수 항목과 값의 범위를 확인하고 문제마다 (필드, 메시지)를 반환합니다
+func (c Config) Validate() map[string]string {
+	problems := make(map[string]string)
+	switch c.Type {
+	case TypeWebhook:
+		if err := validateURL(c.URL); err != nil {
+			problems["url"] = err.Error()
+		}
+	case TypeSyslog:
+		if c.Network != "udp" && c.Network != "tcp" {
+			problems["network"] = fmt.Sprintf("udp 또는 tcp여야 합니다 (현재 값: %q)", c.Network)
+		}
+		if err := validateAddress(c.Address); err != nil {
+			problems["address"] = err.Error()
+		}
+		if c.Facility != "" {
+			if _, err := ParseFacility(c.Facility); err != nil {
+				problems["facility"] = err.Error()
+			}
+		}
+		if c.EnterpriseID != "" {
+			if err := validateEnterpriseID(c.EnterpriseID); err != nil {
+				problems["enterprise_id"] = err.Error()
+			}
+		}
+	case TypeFile:
+		if c.Path == "" {
+			problems["path"] = "비어 있을 수 없습니다"
+		}
+	default:
+		problems["type"] = fmt.Sprintf("webhook, syslog, file 중 하나여야 합니다 (현재 값: %q)", c.Type)
+	}
+
+	if c.TimeoutMs < 0 || c.TimeoutMs > 600000 {
+		problems["timeout_ms"] = fmt.Sprintf("0 이상 600000 이하여야 합니다 (현재 값: %d)", c.TimeoutMs)
+	}
+	if c.Retries < 0 || c.Retries > 20 {
+		problems["retries"] = fmt.Sprintf("0 이상 20 이하여야 합니다 (현재 값: %d)", c.Retries)
+	}
+	if c.RetryDelayMs < 0 || c.RetryDelayMs > 600000 {
+		problems["retry_delay_ms"] = fmt.Sprintf("0 이상 600000 이하여야 합니다 (현재 값: %d)", c.RetryDelayMs)
+	}
+	if c.QueueSize < 0 || c.QueueSize > 1000000 {
+		problems["queue_size"] = fmt.Sprintf("0 이상 1000000 이하여야 합니다 (현재 값: %d)", c.QueueSize)
+	}
+	return problems
+}
+
+// backoff는 delay만큼 기다립니다. ctx가 먼저 취소되면 ctx의 오류를 반환합니다.
+func backoff(ctx context.Context, delay time.Duration) error {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,247 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEnterpriseID는 구조화 데이터 ID(file@<번호>)에 붙이는 기업 번호의 기본값입니다.
+// 32473은 RFC 5612의 문서용 번호이므로 운영 환경에서는 enterprise_id에 IANA에서 받은 번호를 설정해야 합니다.
+const DefaultEnterpriseID = "32473"
+
+// facilities는 syslog facility 이름과 번호입니다 (RFC 5424 6.2.1)
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// ParseFacility는 facility 이름(예: local0)을 번호로 변환합니다
+func ParseFacility(name string) (int, error) {
+	if n, ok := facilities[strings.ToLower(name)]; ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("알 수 없는 syslog facility입니다: %q (예: local0, daemon)", name)
+}
+
+// Syslog은 이벤트를 RFC 5424 형식으로 UDP 또는 TCP syslog 서버에 보내는 싱크입니다.
+// TCP는 RFC 6587 옥텟 카운팅 방식으로 메시지를 구분하며, 연결이 끊어지면 다음 전송에서 다시 연결합니다.
+type Syslog struct {
+	name     string
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	sdSuffix string // "@<기업 번호>"
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog은 syslog 싱크를 생성합니다. 연결은 첫 전송 때 맺습니다.
+func NewSyslog(config Config) (*Syslog, error) {
+	if config.Network != "udp" && config.Network != "tcp" {
+		return nil, fmt.Errorf("syslog network는 udp 또는 tcp여야 합니다: %q", config.Network)
+	}
+	if err := validateAddress(config.Address); err != nil {
+		return nil, err
+	}
+	facilityName := config.Facility
+	if facilityName == "" {
+		facilityName = DefaultFacility
+	}
+	facility, err := ParseFacility(facilityName)
+	if err != nil {
+		return nil, err
+	}
+	enterpriseID := config.EnterpriseID
+	if enterpriseID == "" {
+		enterpriseID = DefaultEnterpriseID
+	}
+	if err := validateEnterpriseID(enterpriseID); err != nil {
+		return nil, err
+	}
+	timeout := config.TimeoutMs
+	if timeout <= 0 {
+		timeout = DefaultTimeoutMs
+	}
+
+	hostname, _ := os.Hostname()
+	return &Syslog{
+		name:     config.Name,
+		network:  config.Network,
+		address:  config.Address,
+		facility: facility,
+		appName:  headerValue(config.AppName, 48),
+		hostname: headerValue(hostname, 255),
+		sdSuffix: "@" + enterpriseID,
+		timeout:  time.Duration(timeout) * time.Millisecond,
+	}, nil
+}
+
+func (s *Syslog) Name() string {
+	return s.name
+}
+
+// Send는 이벤트를 syslog 메시지 하나로 보냅니다. 쓰기에 실패하면 한 번 다시 연결하여 보냅니다.
+func (s *Syslog) Send(ctx context.Context, event Event) error {
+	msg := s.Format(event)
+	if s.network == "tcp" {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			dialer := net.Dialer{Timeout: s.timeout}
+			if s.conn, err = dialer.DialContext(ctx, s.network, s.address); err != nil {
+				s.conn = nil
+				return fmt.Errorf("syslog 서버 연결 실패 %s: %v", s.address, err)
+			}
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+		if _, err = s.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return fmt.Errorf("syslog 전송 실패 %s: %v", s.address, err)
+}
+
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Format은 이벤트를 RFC 5424 메시지로 변환합니다.
+// 예: <134>1 2025-01-01T09:00:00.123456+09:00 host hj-service 1234 FILE_EVENT [file@32473 path="C:\\a.exe" ...] 파일 이벤트 발생
+// 같은 SD-ID는 메시지에 한 번만 쓸 수 있으므로 (RFC 5424 6.3.2) 경고는 모두 alert 요소 하나에
+// 번호를 붙인 항목(level_1, source_1, label_1, level_2, ...)으로 넣습니다.
+func (s *Syslog) Format(event Event) string {
+	msgID := "FILE_EVENT"
+	if len(event.Alerts) > 0 {
+		msgID = "ALERT"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d %s ",
+		s.facility*8+severity(event.Level),
+		event.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+		nilValue(s.hostname), nilValue(s.appName), os.Getpid(), msgID)
+
+	b.WriteString("[file" + s.sdSuffix)
+	writeParam(&b, "path", event.Path)
+	writeParam(&b, "operation", event.Operation)
+	writeParam(&b, "file_type", event.FileType)
+	if event.SHA256 != "" {
+		writeParam(&b, "sha256", event.SHA256)
+	}
+	if event.HashError != "" {
+		writeParam(&b, "hash_error", event.HashError)
+	}
+	if event.Count > 1 {
+		writeParam(&b, "count", strconv.Itoa(event.Count))
+		writeParam(&b, "last_time", event.LastTimestamp.Format(time.RFC3339Nano))
+	}
+	if event.OfflineDetected {
+		writeParam(&b, "offline_detected", "true")
+	}
+	b.WriteString("]")
+	if len(event.Alerts) > 0 {
+		b.WriteString("[alert" + s.sdSuffix)
+		writeParam(&b, "count", strconv.Itoa(len(event.Alerts)))
+		for i, alert := range event.Alerts {
+			n := "_" + strconv.Itoa(i+1)
+			writeParam(&b, "level"+n, alert.Level)
+			writeParam(&b, "source"+n, alert.Source)
+			if alert.Label != "" {
+				writeParam(&b, "label"+n, alert.Label)
+			}
+		}
+		b.WriteString("]")
+	}
+
+	// 메시지가 UTF-8임을 알리는 BOM (RFC 5424 6.4)
+	b.WriteString(" \ufeff")
+	b.WriteString(event.Message)
+	return b.String()
+}
+
+// severity는 로그 수준을 syslog severity로 변환합니다
+func severity(level string) int {
+	switch strings.ToUpper(level) {
+	case "ERROR":
+		return 3
+	case "WARNING":
+		return 4
+	case "DEBUG":
+		return 7
+	default:
+		return 6 // informational
+	}
+}
+
+// writeParam은 구조화 데이터 항목을 추가합니다. 값의 '"', '\', ']'는 이스케이프합니다 (RFC 5424 6.3.3).
+func writeParam(b *strings.Builder, name, value string) {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+	b.WriteString(" " + name + `="` + value + `"`)
+}
+
+// headerValue는 헤더 필드에 쓸 수 있도록 공백과 제어 문자를 제거하고 길이를 제한합니다
+func headerValue(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	return s
+}
+
+// nilValue는 빈 헤더 필드를 NILVALUE("-")로 바꿉니다
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// validateEnterpriseID는 기업 번호가 점으로 구분한 숫자(예: 32473, 32473.1)인지 확인합니다 (RFC 5424 7.2.2)
+func validateEnterpriseID(id string) error {
+	for _, part := range strings.Split(id, ".") {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return fmt.Errorf("점으로 구분한 숫자여야 합니다 (현재 값: %q)", id)
+		}
+	}
+	return nil
+}
+
+// validateAddress는 "호스트:포트" 형식인지 확인합니다
+func validateAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" || port == "" {
+		return fmt.Errorf("호스트:포트 형식이어야 합니다 (현재 값: %q)", address)
+	}
+	return nil
+}
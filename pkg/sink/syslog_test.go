@@ -0,0 +1,204 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2025, 1, 1, 9, 0, 0, 123456000, time.FixedZone("KST", 9*60*60))
+
+func newTestSyslog(t *testing.T, network, address string) *Syslog {
+	t.Helper()
+	s, err := NewSyslog(Config{Type: TypeSyslog, Name: "test", Network: network, Address: address, AppName: "hj service"})
+	if err != nil {
+		t.Fatalf("NewSyslog: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSyslogFormat(t *testing.T) {
+	s := newTestSyslog(t, "udp", "127.0.0.1:514")
+	s.hostname = "PC01"
+	header := func(pri int, msgID string) string {
+		return fmt.Sprintf("<%d>1 2025-01-01T09:00:00.123456+09:00 PC01 hjservice %d %s ", pri, os.Getpid(), msgID)
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{
+			name:  "기본",
+			event: Event{Timestamp: testTime, Level: "INFO", Message: "파일 이벤트", Path: `C:\a.exe`, Operation: "CREATE", FileType: ".exe", Count: 1},
+			want:  header(134, "FILE_EVENT") + `[file@32473 path="C:\\a.exe" operation="CREATE" file_type=".exe"]` + " \ufeff파일 이벤트",
+		},
+		{
+			name: "합친 이벤트와 보완 검사",
+			event: Event{Timestamp: testTime, LastTimestamp: testTime.Add(time.Second), Level: "INFO", Message: "m",
+				Path: `C:\a].exe`, Operation: "WRITE", FileType: ".exe", SHA256: "abc", Count: 3, OfflineDetected: true},
+			want: header(134, "FILE_EVENT") + `[file@32473 path="C:\\a\].exe" operation="WRITE" file_type=".exe" sha256="abc" count="3"` +
+				` last_time="2025-01-01T09:00:01.123456+09:00" offline_detected="true"]` + " \ufeffm",
+		},
+		{
+			name: "경고",
+			event: Event{Timestamp: testTime, Level: "ERROR", Message: "차단", Path: "/a.exe", Operation: "CREATE", FileType: ".exe",
+				Alerts: []Alert{{Level: "ERROR", Source: "blocklist", Label: `say "hi"`, Message: "차단"}}},
+			want: header(131, "ALERT") + `[file@32473 path="/a.exe" operation="CREATE" file_type=".exe"]` +
+				`[alert@32473 count="1" level_1="ERROR" source_1="blocklist" label_1="say \"hi\""]` + " \ufeff차단",
+		},
+		{
+			// 같은 SD-ID는 한 번만 쓸 수 있으므로 경고 여러 개는 요소 하나에 번호를 붙여 넣음
+			name: "경고 두 개",
+			event: Event{Timestamp: testTime, Level: "ERROR", Message: "차단", Path: "/a.exe", Operation: "CREATE", FileType: ".exe",
+				Alerts: []Alert{
+					{Level: "WARNING", Source: "rule", Message: "규칙"},
+					{Level: "ERROR", Source: "blocklist", Label: "dropper", Message: "차단"},
+				}},
+			want: header(131, "ALERT") + `[file@32473 path="/a.exe" operation="CREATE" file_type=".exe"]` +
+				`[alert@32473 count="2" level_1="WARNING" source_1="rule" level_2="ERROR" source_2="blocklist" label_2="dropper"]` + " \ufeff차단",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Format(tt.event); got != tt.want {
+				t.Errorf("Format =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyslogEnterpriseID(t *testing.T) {
+	config := Config{Type: TypeSyslog, Network: "udp", Address: "127.0.0.1:514", EnterpriseID: "12345.1"}
+	s, err := NewSyslog(config)
+	if err != nil {
+		t.Fatalf("NewSyslog: %v", err)
+	}
+	event := Event{Timestamp: testTime, Level: "ERROR", Message: "m", Path: "/a.exe", Operation: "CREATE",
+		Alerts: []Alert{{Level: "ERROR", Source: "rule"}}}
+	if got := s.Format(event); !strings.Contains(got, "[file@12345.1 ") || !strings.Contains(got, "[alert@12345.1 ") {
+		t.Errorf("Format = %q, want 설정한 기업 번호", got)
+	}
+
+	for _, id := range []string{"abc", "32473.", "@32473", "1 2"} {
+		config.EnterpriseID = id
+		if _, err := NewSyslog(config); err == nil {
+			t.Errorf("enterprise_id %q: NewSyslog가 오류를 반환하지 않았습니다", id)
+		}
+		if _, ok := config.Validate()["enterprise_id"]; !ok {
+			t.Errorf("enterprise_id %q: Validate가 문제를 보고하지 않았습니다", id)
+		}
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	s := newTestSyslog(t, "udp", conn.LocalAddr().String())
+
+	event := Event{Timestamp: testTime, Level: "WARNING", Message: "경고", Path: "/a.exe", Operation: "CREATE"}
+	if err := s.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// UDP는 데이터그램 하나가 메시지 하나이며 길이를 붙이지 않음
+	buf := make([]byte, 64<<10)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	if got, want := string(buf[:n]), s.Format(event); got != want {
+		t.Errorf("받은 메시지 =\n%q\nwant\n%q", got, want)
+	}
+	if !strings.HasPrefix(string(buf[:n]), "<132>1 ") {
+		t.Errorf("PRI가 local0.warning(132)이 아닙니다: %q", buf[:n])
+	}
+}
+
+func TestSyslogTCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		r := bufio.NewReader(conn)
+		var msgs []string
+		for len(msgs) < 2 {
+			// RFC 6587: MSG-LEN SP SYSLOG-MSG
+			prefix, err := r.ReadString(' ')
+			if err != nil {
+				break
+			}
+			size, err := strconv.Atoi(strings.TrimSuffix(prefix, " "))
+			if err != nil {
+				break
+			}
+			msg := make([]byte, size)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				break
+			}
+			msgs = append(msgs, string(msg))
+		}
+		received <- msgs
+	}()
+
+	s := newTestSyslog(t, "tcp", ln.Addr().String())
+	events := []Event{
+		{Timestamp: testTime, Level: "INFO", Message: "첫 번째", Path: "/a.exe", Operation: "CREATE"},
+		{Timestamp: testTime, Level: "INFO", Message: "두 번째\n줄바꿈", Path: "/b.dll", Operation: "WRITE"},
+	}
+	for _, event := range events {
+		if err := s.Send(context.Background(), event); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	select {
+	case msgs := <-received:
+		if len(msgs) != len(events) {
+			t.Fatalf("받은 메시지 %d개, want %d개: %q", len(msgs), len(events), msgs)
+		}
+		for i, event := range events {
+			if want := s.Format(event); msgs[i] != want {
+				t.Errorf("메시지 %d =\n%q\nwant\n%q", i, msgs[i], want)
+			}
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("메시지를 받지 못했습니다")
+	}
+}
+
+func TestSyslogConnectError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+
+	s := newTestSyslog(t, "tcp", address)
+	if err := s.Send(context.Background(), Event{}); err == nil || !strings.Contains(err.Error(), "연결 실패") {
+		t.Errorf("Send 오류 = %v, want 연결 실패", err)
+	}
+}
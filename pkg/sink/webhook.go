@@ -0,0 +1,117 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Webhook은 이벤트를 JSON으로 HTTP POST하는 싱크입니다.
+// 연결 오류와 5xx, 429 응답은 대기 시간을 두 배씩 늘리며 다시 시도하고, 그 밖의 4xx 응답은 다시 시도하지 않습니다.
+type Webhook struct {
+	name       string
+	url        string
+	headers    map[string]string
+	retries    int
+	retryDelay time.Duration
+
+	// Client는 요청에 사용하는 HTTP 클라이언트입니다 (테스트 서버의 클라이언트로 교체할 수 있음)
+	Client *http.Client
+}
+
+// NewWebhook은 웹훅 싱크를 생성합니다
+func NewWebhook(config Config) (*Webhook, error) {
+	if err := validateURL(config.URL); err != nil {
+		return nil, err
+	}
+	timeout := config.TimeoutMs
+	if timeout <= 0 {
+		timeout = DefaultTimeoutMs
+	}
+	retryDelay := config.RetryDelayMs
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelayMs
+	}
+	return &Webhook{
+		name:       config.Name,
+		url:        config.URL,
+		headers:    config.Headers,
+		retries:    config.Retries,
+		retryDelay: time.Duration(retryDelay) * time.Millisecond,
+		Client:     &http.Client{Timeout: time.Duration(timeout) * time.Millisecond},
+	}, nil
+}
+
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// Send는 이벤트를 POST하고, 실패하면 설정한 횟수만큼 다시 시도합니다
+func (w *Webhook) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("이벤트 직렬화 실패: %v", err)
+	}
+
+	delay := w.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.retries {
+			return fmt.Errorf("웹훅 전송 실패 (%d회 시도): %v", attempt+1, err)
+		}
+		if err := backoff(ctx, delay); err != nil {
+			return fmt.Errorf("웹훅 전송 중단 (%d회 시도): %v", attempt+1, err)
+		}
+		delay *= 2
+	}
+}
+
+// post는 요청 한 번을 보내고, 실패했으면 다시 시도할 수 있는 오류인지 함께 반환합니다
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // 연결 재사용
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("HTTP %s", resp.Status)
+	default:
+		return false, fmt.Errorf("HTTP %s", resp.Status)
+	}
+}
+
+func (w *Webhook) Close() error {
+	w.Client.CloseIdleConnections()
+	return nil
+}
+
+// validateURL은 http 또는 https 절대 URL인지 확인합니다
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("http:// 또는 https:// URL이어야 합니다 (현재 값: %q)", raw)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusServer는 요청마다 statuses의 상태 코드를 차례로 응답하고 (마지막 값 반복) 요청 시각을 기록하는 테스트 서버입니다
+type statusServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	times    []time.Time
+	bodies   []Event
+	headers  []http.Header
+}
+
+func newStatusServer(t *testing.T, statuses ...int) *statusServer {
+	s := &statusServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+
+		s.mu.Lock()
+		n := len(s.times)
+		s.times = append(s.times, time.Now())
+		s.bodies = append(s.bodies, event)
+		s.headers = append(s.headers, r.Header.Clone())
+		status := s.statuses[len(s.statuses)-1]
+		if n < len(s.statuses) {
+			status = s.statuses[n]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *statusServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.times)
+}
+
+func newTestWebhook(t *testing.T, url string, retries, delayMs int) *Webhook {
+	t.Helper()
+	w, err := NewWebhook(Config{Type: TypeWebhook, Name: "test", URL: url, Retries: retries, RetryDelayMs: delayMs,
+		Headers: map[string]string{"Authorization": "Bearer token"}})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestWebhookRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		requests int
+		wantErr  string // 빈 문자열이면 성공
+	}{
+		{"성공", []int{200}, 3, 1, ""},
+		{"5xx 후 성공", []int{500, 502, 204}, 3, 3, ""},
+		{"429 후 성공", []int{429, 200}, 3, 2, ""},
+		{"5xx 재시도 소진", []int{503}, 2, 3, "3회 시도"},
+		{"4xx는 재시도 안 함", []int{400, 200}, 3, 1, "400"},
+		{"404는 재시도 안 함", []int{404}, 3, 1, "1회 시도"},
+		{"재시도 없음", []int{500, 200}, 0, 1, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStatusServer(t, tt.statuses...)
+			w := newTestWebhook(t, server.URL, tt.retries, 1)
+
+			err := w.Send(context.Background(), Event{Path: `C:\a.exe`, Operation: "CREATE"})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Send: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Send 오류 = %v, want %q 포함", err, tt.wantErr)
+			}
+			if got := server.requests(); got != tt.requests {
+				t.Errorf("요청 %d회, want %d회", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	server := newStatusServer(t, 500, 500, 200)
+	w := newTestWebhook(t, server.URL, 2, 30)
+
+	if err := w.Send(context.Background(), Event{}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	// 재시도 대기 시간은 retry_delay_ms부터 두 배씩 늘어남
+	for i, want := range []time.Duration{30 * time.Millisecond, 60 * time.Millisecond} {
+		if gap := server.times[i+1].Sub(server.times[i]); gap < want {
+			t.Errorf("%d번째 재시도 대기 %v, want %v 이상", i+1, gap, want)
+		}
+	}
+}
+
+func TestWebhookCancel(t *testing.T) {
+	server := newStatusServer(t, 500)
+	w := newTestWebhook(t, server.URL, 5, 10000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := w.Send(ctx, Event{})
+	if err == nil || !strings.Contains(err.Error(), "중단") {
+		t.Errorf("Send 오류 = %v, want 전송 중단", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("취소 후 %v 동안 기다렸습니다", elapsed)
+	}
+	if got := server.requests(); got != 1 {
+		t.Errorf("요청 %d회, want 1회", got)
+	}
+}
+
+func TestWebhookRequest(t *testing.T) {
+	server := newStatusServer(t, 200)
+	w := newTestWebhook(t, server.URL, 0, 0)
+
+	event := Event{Path: `C:\tools\a.exe`, Operation: "WRITE", Count: 2, SHA256: "abc"}
+	if err := w.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	got := server.bodies[0]
+	if got.Path != event.Path || got.Operation != event.Operation || got.Count != 2 || got.SHA256 != "abc" {
+		t.Errorf("본문 = %+v, want %+v", got, event)
+	}
+	h := server.headers[0]
+	if h.Get("Content-Type") != "application/json" || h.Get("Authorization") != "Bearer token" {
+		t.Errorf("헤더 = %v", h)
+	}
+}
@@ -0,0 +1,110 @@
+package winsvc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrServiceNotExist는 요청한 서비스가 등록되어 있지 않을 때 반환됩니다
+var ErrServiceNotExist = errors.New("서비스가 존재하지 않습니다")
+
+// ServiceState는 플랫폼에 독립적인 서비스 상태 값입니다 (Windows svc.State와 같은 값)
+type ServiceState uint32
+
+const (
+	StateStopped ServiceState = iota + 1
+	StateStartPending
+	StateStopPending
+	StateRunning
+	StateContinuePending
+	StatePausePending
+	StatePaused
+)
+
+// String은 상태를 사람이 읽을 수 있는 문자열로 반환합니다
+func (s ServiceState) String() string {
+	switch s {
+	case StateRunning:
+		return "실행 중"
+	case StateStopped:
+		return "중지됨"
+	case StateStartPending:
+		return "시작 중"
+	case StateStopPending:
+		return "중지 중"
+	case StatePausePending:
+		return "일시 중지 중"
+	case StatePaused:
+		return "일시 중지됨"
+	case StateContinuePending:
+		return "계속 중"
+	default:
+		return fmt.Sprintf("알 수 없음 (%d)", uint32(s))
+	}
+}
+
+// ControlCommand는 서비스에 보내는 제어 명령입니다 (Windows svc.Cmd와 같은 값)
+type ControlCommand uint32
+
+const (
+	ControlStop        ControlCommand = 1
+	ControlInterrogate ControlCommand = 4
+	ControlShutdown    ControlCommand = 5
+	ControlParamChange ControlCommand = 6
+)
+
+// StartType은 서비스 시작 유형입니다 (Windows mgr.StartXxx와 같은 값)
+type StartType uint32
+
+const (
+	StartAutomatic StartType = 2
+	StartManual    StartType = 3
+	StartDisabled  StartType = 4
+)
+
+// ServiceStatus는 서비스의 현재 상태입니다
+type ServiceStatus struct {
+	State ServiceState
+}
+
+// ServiceSpec은 서비스 생성 시 사용하는 설정입니다
+type ServiceSpec struct {
+	DisplayName      string
+	Description      string
+	StartType        StartType
+	ServiceStartName string // 빈 값이면 LocalSystem 계정
+}
+
+// RecoveryActionType은 서비스 실패 시 수행할 동작 유형입니다
+type RecoveryActionType int
+
+const (
+	RecoveryNone    RecoveryActionType = 0
+	RecoveryRestart RecoveryActionType = 1
+)
+
+// RecoveryAction은 서비스 실패 시 수행할 복구 동작입니다
+type RecoveryAction struct {
+	Type  RecoveryActionType
+	Delay time.Duration
+}
+
+// ServiceController는 서비스 관리자(SCM 등)에 대한 추상화입니다
+type ServiceController interface {
+	CreateService(name, exePath string, spec ServiceSpec, args ...string) (ServiceHandle, error)
+	OpenService(name string) (ServiceHandle, error)
+	InstallEventSource(name string) error
+	RemoveEventSource(name string) error
+	Disconnect() error
+}
+
+// ServiceHandle은 열려 있는 개별 서비스에 대한 추상화입니다
+type ServiceHandle interface {
+	Start(args ...string) error
+	Control(cmd ControlCommand) (ServiceStatus, error)
+	Query() (ServiceStatus, error)
+	SetRecoveryActions(actions []RecoveryAction, resetPeriod uint32) error
+	Delete() error
+	Close() error
+}
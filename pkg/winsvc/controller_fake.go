@@ -0,0 +1,225 @@
+package winsvc
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FakeService는 FakeController가 관리하는 메모리 내 서비스입니다
+type FakeService struct {
+	Name            string
+	ExePath         string
+	Args            []string
+	Spec            ServiceSpec
+	State           ServiceState
+	RecoveryActions []RecoveryAction
+	ResetPeriod     uint32
+}
+
+// FakeController는 테스트용 메모리 기반 ServiceController 구현입니다.
+// 각 *Err 필드를 설정하면 해당 동작에서 오류를 주입할 수 있습니다.
+type FakeController struct {
+	mu           sync.Mutex
+	Services     map[string]*FakeService
+	EventSources map[string]bool
+
+	ConnectErr     error
+	CreateErr      error
+	OpenErr        error
+	StartErr       error
+	ControlErr     error
+	QueryErr       error
+	RecoveryErr    error
+	DeleteErr      error
+	EventSourceErr error
+
+	// StopHangs를 설정하면 중지 요청 후 서비스가 StopPending에 머뭅니다 (종료 처리가 멈춘 서비스)
+	StopHangs bool
+}
+
+// NewFakeController는 비어 있는 FakeController를 생성합니다
+func NewFakeController() *FakeController {
+	return &FakeController{
+		Services:     make(map[string]*FakeService),
+		EventSources: make(map[string]bool),
+	}
+}
+
+// Connect는 ServiceManager.Connect에 지정할 수 있는 연결 함수입니다
+func (f *FakeController) Connect() (ServiceController, error) {
+	if f.ConnectErr != nil {
+		return nil, f.ConnectErr
+	}
+	return f, nil
+}
+
+// Service는 등록된 서비스를 반환합니다 (없으면 nil)
+func (f *FakeController) Service(name string) *FakeService {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Services[name]
+}
+
+func (f *FakeController) CreateService(name, exePath string, spec ServiceSpec, args ...string) (ServiceHandle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.CreateErr != nil {
+		return nil, f.CreateErr
+	}
+	if _, ok := f.Services[name]; ok {
+		return nil, fmt.Errorf("서비스 %s가 이미 존재합니다", name)
+	}
+	f.Services[name] = &FakeService{
+		Name:    name,
+		ExePath: exePath,
+		Args:    args,
+		Spec:    spec,
+		State:   StateStopped,
+	}
+	return &fakeHandle{f: f, name: name}, nil
+}
+
+func (f *FakeController) OpenService(name string) (ServiceHandle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.OpenErr != nil {
+		return nil, f.OpenErr
+	}
+	if _, ok := f.Services[name]; !ok {
+		return nil, ErrServiceNotExist
+	}
+	return &fakeHandle{f: f, name: name}, nil
+}
+
+func (f *FakeController) InstallEventSource(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.EventSourceErr != nil {
+		return f.EventSourceErr
+	}
+	f.EventSources[name] = true
+	return nil
+}
+
+func (f *FakeController) RemoveEventSource(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.EventSourceErr != nil {
+		return f.EventSourceErr
+	}
+	delete(f.EventSources, name)
+	return nil
+}
+
+func (f *FakeController) Disconnect() error {
+	return nil
+}
+
+// fakeHandle은 FakeController의 서비스를 가리키는 ServiceHandle입니다
+type fakeHandle struct {
+	f    *FakeController
+	name string
+}
+
+// service는 잠금을 가진 상태에서 대상 서비스를 찾습니다
+func (h *fakeHandle) service() (*FakeService, error) {
+	s, ok := h.f.Services[h.name]
+	if !ok {
+		return nil, ErrServiceNotExist
+	}
+	return s, nil
+}
+
+func (h *fakeHandle) Start(args ...string) error {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	if h.f.StartErr != nil {
+		return h.f.StartErr
+	}
+	s, err := h.service()
+	if err != nil {
+		return err
+	}
+	if s.State == StateRunning {
+		return fmt.Errorf("서비스 %s가 이미 실행 중입니다", h.name)
+	}
+	s.State = StateRunning
+	return nil
+}
+
+func (h *fakeHandle) Control(cmd ControlCommand) (ServiceStatus, error) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	if h.f.ControlErr != nil {
+		return ServiceStatus{}, h.f.ControlErr
+	}
+	s, err := h.service()
+	if err != nil {
+		return ServiceStatus{}, err
+	}
+	switch cmd {
+	case ControlStop, ControlShutdown:
+		if s.State != StateRunning {
+			return ServiceStatus{State: s.State}, fmt.Errorf("서비스 %s가 실행 중이 아닙니다", h.name)
+		}
+		s.State = StateStopped
+		if h.f.StopHangs {
+			s.State = StateStopPending
+		}
+	}
+	return ServiceStatus{State: s.State}, nil
+}
+
+func (h *fakeHandle) Query() (ServiceStatus, error) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	if h.f.QueryErr != nil {
+		return ServiceStatus{}, h.f.QueryErr
+	}
+	s, err := h.service()
+	if err != nil {
+		return ServiceStatus{}, err
+	}
+	return ServiceStatus{State: s.State}, nil
+}
+
+func (h *fakeHandle) SetRecoveryActions(actions []RecoveryAction, resetPeriod uint32) error {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	if h.f.RecoveryErr != nil {
+		return h.f.RecoveryErr
+	}
+	s, err := h.service()
+	if err != nil {
+		return err
+	}
+	s.RecoveryActions = append([]RecoveryAction(nil), actions...)
+	s.ResetPeriod = resetPeriod
+	return nil
+}
+
+func (h *fakeHandle) Delete() error {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	if h.f.DeleteErr != nil {
+		return h.f.DeleteErr
+	}
+	if _, err := h.service(); err != nil {
+		return err
+	}
+	delete(h.f.Services, h.name)
+	return nil
+}
+
+func (h *fakeHandle) Close() error {
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build linux
+// +build linux
+
+package winsvc
+
+// serviceArgs는 서비스 관리자가 실행 파일에 전달할 인자입니다
+var serviceArgs = []string{"run"}
+
+// fixedRecoveryActions가 0이면 복구 동작을 MaxRestartAttempts개 만듭니다 (StartLimitBurst로 변환)
+const fixedRecoveryActions = 0
+
+// connectServiceController는 systemd 기반 컨트롤러를 반환합니다
+func connectServiceController() (ServiceController, error) {
+	return NewSystemdController(), nil
+}
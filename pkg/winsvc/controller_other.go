@@ -0,0 +1,17 @@
+//go:build !windows && !linux
+// +build !windows,!linux
+
+package winsvc
+
+import "errors"
+
+// serviceArgs는 서비스 관리자가 실행 파일에 전달할 인자입니다
+var serviceArgs []string
+
+// fixedRecoveryActions가 0이면 복구 동작을 MaxRestartAttempts개 만듭니다
+const fixedRecoveryActions = 0
+
+// connectServiceController는 지원되지 않는 플랫폼에서 오류를 반환합니다
+func connectServiceController() (ServiceController, error) {
+	return nil, errors.New("이 플랫폼에서는 서비스 관리자를 지원하지 않습니다")
+}
@@ -0,0 +1,322 @@
+package winsvc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultSystemdUnitDir는 시스템 유닛 파일이 위치하는 기본 디렉토리입니다
+const DefaultSystemdUnitDir = "/etc/systemd/system"
+
+// SystemdController는 systemd 유닛 파일과 systemctl을 사용하는 ServiceController 구현입니다
+type SystemdController struct {
+	// UnitDir은 유닛 파일을 생성할 디렉토리입니다
+	UnitDir string
+	// Systemctl은 systemctl 명령을 실행합니다 (테스트에서 교체 가능)
+	Systemctl func(args ...string) ([]byte, error)
+}
+
+// NewSystemdController는 기본 경로와 systemctl 실행기를 사용하는 컨트롤러를 생성합니다
+func NewSystemdController() *SystemdController {
+	return &SystemdController{
+		UnitDir:   DefaultSystemdUnitDir,
+		Systemctl: runSystemctl,
+	}
+}
+
+// runSystemctl은 실제 systemctl 명령을 실행합니다
+func runSystemctl(args ...string) ([]byte, error) {
+	return exec.Command("systemctl", args...).CombinedOutput()
+}
+
+// Connect는 ServiceManager.Connect에 지정할 수 있는 연결 함수입니다
+func (c *SystemdController) Connect() (ServiceController, error) {
+	return c, nil
+}
+
+// unitName은 서비스 이름에 대한 유닛 이름을 반환합니다
+func unitName(name string) string {
+	return name + ".service"
+}
+
+// unitPath는 서비스 이름에 대한 유닛 파일 경로를 반환합니다
+func (c *SystemdController) unitPath(name string) string {
+	return filepath.Join(c.UnitDir, unitName(name))
+}
+
+// systemctl은 systemctl을 실행하고 실패 시 출력 내용을 포함한 오류를 반환합니다
+func (c *SystemdController) systemctl(args ...string) (string, error) {
+	out, err := c.Systemctl(args...)
+	if err != nil {
+		return string(out), fmt.Errorf("systemctl %s 실패: %v (%s)",
+			strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+func (c *SystemdController) CreateService(name, exePath string, spec ServiceSpec, args ...string) (ServiceHandle, error) {
+	path := c.unitPath(name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("유닛 파일이 이미 존재합니다: %s", path)
+	}
+
+	if err := os.MkdirAll(c.UnitDir, 0755); err != nil {
+		return nil, fmt.Errorf("유닛 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(renderUnit(exePath, spec, args)), 0644); err != nil {
+		return nil, fmt.Errorf("유닛 파일 생성 실패: %v", err)
+	}
+
+	if _, err := c.systemctl("daemon-reload"); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	// 자동 시작 서비스는 부팅 시 시작되도록 활성화
+	if spec.StartType == StartAutomatic {
+		if _, err := c.systemctl("enable", unitName(name)); err != nil {
+			os.Remove(path)
+			return nil, err
+		}
+	}
+
+	return &systemdService{c: c, name: name}, nil
+}
+
+func (c *SystemdController) OpenService(name string) (ServiceHandle, error) {
+	if _, err := os.Stat(c.unitPath(name)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrServiceNotExist
+		}
+		return nil, err
+	}
+	return &systemdService{c: c, name: name}, nil
+}
+
+// InstallEventSource는 journald가 로그를 수집하므로 아무 작업도 하지 않습니다
+func (c *SystemdController) InstallEventSource(name string) error {
+	return nil
+}
+
+// RemoveEventSource는 journald가 로그를 수집하므로 아무 작업도 하지 않습니다
+func (c *SystemdController) RemoveEventSource(name string) error {
+	return nil
+}
+
+func (c *SystemdController) Disconnect() error {
+	return nil
+}
+
+// renderUnit은 서비스 설정으로 유닛 파일 내용을 생성합니다
+func renderUnit(exePath string, spec ServiceSpec, args []string) string {
+	execStart := make([]string, 0, len(args)+1)
+	for _, a := range append([]string{exePath}, args...) {
+		execStart = append(execStart, quoteUnitArg(a))
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", spec.Description)
+	b.WriteString("After=network.target\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(execStart, " "))
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", quoteUnitArg(filepath.Dir(exePath)))
+	if spec.ServiceStartName != "" {
+		fmt.Fprintf(&b, "User=%s\n", spec.ServiceStartName)
+	}
+	b.WriteString("Restart=no\n")
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// quoteUnitArg는 공백이 포함된 인자를 큰따옴표로 감쌉니다
+func quoteUnitArg(s string) string {
+	if !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// setUnitKeys는 유닛 파일의 지정한 섹션에서 키 값을 교체하거나 추가합니다
+func setUnitKeys(content, section string, keys []string, values map[string]string) string {
+	var out []string
+	current := ""
+	inserted := false
+
+	insert := func() {
+		for _, k := range keys {
+			out = append(out, k+"="+values[k])
+		}
+		inserted = true
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			// 대상 섹션이 끝나기 전에 키를 추가
+			if current == section && !inserted {
+				for len(out) > 0 && out[len(out)-1] == "" {
+					out = out[:len(out)-1]
+				}
+				insert()
+				out = append(out, "")
+			}
+			current = trimmed
+			out = append(out, line)
+			continue
+		}
+
+		if current == section {
+			if k, _, ok := strings.Cut(trimmed, "="); ok {
+				if _, managed := values[strings.TrimSpace(k)]; managed {
+					continue
+				}
+			}
+		}
+		out = append(out, line)
+	}
+
+	if current == section && !inserted {
+		insert()
+	}
+	if !inserted {
+		out = append(out, "", section)
+		insert()
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// systemdService는 systemd 유닛에 대한 ServiceHandle 구현입니다
+type systemdService struct {
+	c    *SystemdController
+	name string
+}
+
+func (h *systemdService) Start(args ...string) error {
+	_, err := h.c.systemctl("start", unitName(h.name))
+	return err
+}
+
+func (h *systemdService) Control(cmd ControlCommand) (ServiceStatus, error) {
+	switch cmd {
+	case ControlStop, ControlShutdown:
+		if _, err := h.c.systemctl("stop", unitName(h.name)); err != nil {
+			return ServiceStatus{}, err
+		}
+	case ControlParamChange:
+		if _, err := h.c.systemctl("reload", unitName(h.name)); err != nil {
+			return ServiceStatus{}, err
+		}
+	case ControlInterrogate:
+	default:
+		return ServiceStatus{}, fmt.Errorf("지원하지 않는 제어 명령: %d", cmd)
+	}
+	return h.Query()
+}
+
+func (h *systemdService) Query() (ServiceStatus, error) {
+	out, err := h.c.systemctl("show", unitName(h.name), "--property=ActiveState")
+	if err != nil {
+		return ServiceStatus{}, err
+	}
+
+	var active string
+	for _, line := range strings.Split(out, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "ActiveState="); ok {
+			active = v
+		}
+	}
+
+	switch active {
+	case "active", "reloading":
+		return ServiceStatus{State: StateRunning}, nil
+	case "activating":
+		return ServiceStatus{State: StateStartPending}, nil
+	case "deactivating":
+		return ServiceStatus{State: StateStopPending}, nil
+	case "inactive", "failed":
+		return ServiceStatus{State: StateStopped}, nil
+	default:
+		return ServiceStatus{}, fmt.Errorf("알 수 없는 ActiveState: %q", active)
+	}
+}
+
+// SetRecoveryActions는 복구 동작을 Restart=on-failure 설정으로 변환합니다.
+// 첫 번째 지연을 RestartSec로, 동작 개수를 StartLimitBurst로 사용하며
+// 제한 구간은 초기화 주기와 전체 지연 합계를 더한 값입니다.
+func (h *systemdService) SetRecoveryActions(actions []RecoveryAction, resetPeriod uint32) error {
+	path := h.c.unitPath(h.name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("유닛 파일 읽기 실패: %v", err)
+	}
+
+	restarts := 0
+	var firstDelay, totalDelay time.Duration
+	for _, a := range actions {
+		if a.Type != RecoveryRestart {
+			continue
+		}
+		if restarts == 0 {
+			firstDelay = a.Delay
+		}
+		restarts++
+		totalDelay += a.Delay
+	}
+
+	content := string(data)
+	if restarts == 0 {
+		content = setUnitKeys(content, "[Service]", []string{"Restart"},
+			map[string]string{"Restart": "no"})
+	} else {
+		interval := time.Duration(resetPeriod)*time.Second + totalDelay
+		content = setUnitKeys(content, "[Service]", []string{"Restart", "RestartSec"},
+			map[string]string{
+				"Restart":    "on-failure",
+				"RestartSec": fmt.Sprintf("%d", int(firstDelay.Seconds())),
+			})
+		content = setUnitKeys(content, "[Unit]", []string{"StartLimitIntervalSec", "StartLimitBurst"},
+			map[string]string{
+				"StartLimitIntervalSec": fmt.Sprintf("%d", int(interval.Seconds())),
+				"StartLimitBurst":       fmt.Sprintf("%d", restarts),
+			})
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("유닛 파일 저장 실패: %v", err)
+	}
+	_, err = h.c.systemctl("daemon-reload")
+	return err
+}
+
+func (h *systemdService) Delete() error {
+	// 비활성화 실패는 무시 (활성화되지 않은 유닛일 수 있음)
+	h.c.systemctl("disable", unitName(h.name))
+
+	if err := os.Remove(h.c.unitPath(h.name)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrServiceNotExist
+		}
+		return fmt.Errorf("유닛 파일 삭제 실패: %v", err)
+	}
+	_, err := h.c.systemctl("daemon-reload")
+	return err
+}
+
+func (h *systemdService) Close() error {
+	return nil
+}
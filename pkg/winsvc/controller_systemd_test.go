@@ -0,0 +1,287 @@
+//go:build linux
+// +build linux
+
+package winsvc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingSystemctl은 systemctl 호출을 기록하고 지정한 결과를 반환합니다
+type recordingSystemctl struct {
+	calls  []string
+	output map[string]string // 명령 → 출력
+	fail   map[string]bool   // 실패할 명령
+}
+
+func (r *recordingSystemctl) run(args ...string) ([]byte, error) {
+	cmd := strings.Join(args, " ")
+	r.calls = append(r.calls, cmd)
+	if r.fail[cmd] {
+		return []byte("Failed to " + cmd), errors.New("exit status 1")
+	}
+	return []byte(r.output[cmd]), nil
+}
+
+func newTestSystemd(t *testing.T) (*SystemdController, *recordingSystemctl) {
+	rec := &recordingSystemctl{output: map[string]string{}, fail: map[string]bool{}}
+	return &SystemdController{UnitDir: t.TempDir(), Systemctl: rec.run}, rec
+}
+
+func TestRenderUnit(t *testing.T) {
+	got := renderUnit("/opt/hj service/hj-service", ServiceSpec{
+		Description:      "파일 감시 서비스",
+		ServiceStartName: "hj",
+	}, []string{"run", `a"b`})
+
+	want := `[Unit]
+Description=파일 감시 서비스
+After=network.target
+
+[Service]
+Type=simple
+ExecStart="/opt/hj service/hj-service" run "a\"b"
+ExecReload=/bin/kill -HUP $MAINPID
+WorkingDirectory="/opt/hj service"
+User=hj
+Restart=no
+
+[Install]
+WantedBy=multi-user.target
+`
+	if got != want {
+		t.Errorf("renderUnit =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := renderUnit("/usr/bin/hj", ServiceSpec{}, nil); strings.Contains(got, "User=") {
+		t.Errorf("ServiceStartName이 없으면 User를 쓰지 않아야 합니다:\n%s", got)
+	}
+}
+
+func TestSetUnitKeys(t *testing.T) {
+	const unit = `[Unit]
+Description=x
+
+[Service]
+Type=simple
+Restart=no
+RestartSec=1
+
+[Install]
+WantedBy=multi-user.target
+`
+	tests := []struct {
+		name    string
+		content string
+		section string
+		keys    []string
+		values  map[string]string
+		want    string
+	}{
+		{
+			name:    "교체",
+			content: unit,
+			section: "[Service]",
+			keys:    []string{"Restart", "RestartSec"},
+			values:  map[string]string{"Restart": "on-failure", "RestartSec": "5"},
+			want: `[Unit]
+Description=x
+
+[Service]
+Type=simple
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`,
+		},
+		{
+			name:    "섹션 끝에 추가",
+			content: unit,
+			section: "[Unit]",
+			keys:    []string{"StartLimitIntervalSec", "StartLimitBurst"},
+			values:  map[string]string{"StartLimitIntervalSec": "75", "StartLimitBurst": "3"},
+			want: `[Unit]
+Description=x
+StartLimitIntervalSec=75
+StartLimitBurst=3
+
+[Service]
+Type=simple
+Restart=no
+RestartSec=1
+
+[Install]
+WantedBy=multi-user.target
+`,
+		},
+		{
+			name:    "마지막 섹션",
+			content: unit,
+			section: "[Install]",
+			keys:    []string{"WantedBy"},
+			values:  map[string]string{"WantedBy": "default.target"},
+			want: `[Unit]
+Description=x
+
+[Service]
+Type=simple
+Restart=no
+RestartSec=1
+
+[Install]
+WantedBy=default.target
+`,
+		},
+		{
+			name:    "섹션 없음",
+			content: "[Unit]\nDescription=x\n",
+			section: "[Service]",
+			keys:    []string{"Restart"},
+			values:  map[string]string{"Restart": "no"},
+			want:    "[Unit]\nDescription=x\n\n[Service]\nRestart=no\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setUnitKeys(tt.content, tt.section, tt.keys, tt.values); got != tt.want {
+				t.Errorf("setUnitKeys =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemdCreateService(t *testing.T) {
+	c, rec := newTestSystemd(t)
+
+	h, err := c.CreateService("hj", "/usr/bin/hj", ServiceSpec{StartType: StartAutomatic}, "run")
+	if err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	defer h.Close()
+
+	data, err := os.ReadFile(filepath.Join(c.UnitDir, "hj.service"))
+	if err != nil {
+		t.Fatalf("유닛 파일: %v", err)
+	}
+	if !strings.Contains(string(data), "ExecStart=/usr/bin/hj run\n") {
+		t.Errorf("ExecStart가 없습니다:\n%s", data)
+	}
+	if want := []string{"daemon-reload", "enable hj.service"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("systemctl 호출 = %q, want %q", rec.calls, want)
+	}
+
+	if _, err := c.CreateService("hj", "/usr/bin/hj", ServiceSpec{}); err == nil {
+		t.Error("유닛 파일이 있으면 오류를 반환해야 합니다")
+	}
+}
+
+func TestSystemdCreateServiceRollback(t *testing.T) {
+	c, rec := newTestSystemd(t)
+	rec.fail["enable hj.service"] = true
+
+	if _, err := c.CreateService("hj", "/usr/bin/hj", ServiceSpec{StartType: StartAutomatic}); err == nil {
+		t.Fatal("enable 실패 시 오류를 반환해야 합니다")
+	}
+	if _, err := os.Stat(filepath.Join(c.UnitDir, "hj.service")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("실패 후 유닛 파일이 남아 있습니다: %v", err)
+	}
+	if _, err := c.OpenService("hj"); !errors.Is(err, ErrServiceNotExist) {
+		t.Errorf("OpenService 오류 = %v, want ErrServiceNotExist", err)
+	}
+}
+
+func TestSystemdSetRecoveryActions(t *testing.T) {
+	c, rec := newTestSystemd(t)
+	h, err := c.CreateService("hj", "/usr/bin/hj", ServiceSpec{})
+	if err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	rec.calls = nil
+
+	actions := []RecoveryAction{
+		{Type: RecoveryRestart, Delay: 5 * time.Second},
+		{Type: RecoveryRestart, Delay: 10 * time.Second},
+		{Type: RecoveryRestart, Delay: 15 * time.Second},
+	}
+	if err := h.SetRecoveryActions(actions, 60); err != nil {
+		t.Fatalf("SetRecoveryActions: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(c.UnitDir, "hj.service"))
+	for _, line := range []string{"Restart=on-failure", "RestartSec=5", "StartLimitIntervalSec=90", "StartLimitBurst=3"} {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("%s가 없습니다:\n%s", line, data)
+		}
+	}
+	if strings.Contains(string(data), "Restart=no") {
+		t.Errorf("기존 Restart=no가 남아 있습니다:\n%s", data)
+	}
+	if want := []string{"daemon-reload"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("systemctl 호출 = %q, want %q", rec.calls, want)
+	}
+
+	// 재시작 동작이 없으면 Restart=no로 되돌림
+	if err := h.SetRecoveryActions(nil, 60); err != nil {
+		t.Fatalf("SetRecoveryActions: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(c.UnitDir, "hj.service"))
+	if !strings.Contains(string(data), "Restart=no\n") || strings.Contains(string(data), "Restart=on-failure") {
+		t.Errorf("Restart=no로 되돌리지 않았습니다:\n%s", data)
+	}
+}
+
+func TestSystemdQuery(t *testing.T) {
+	tests := []struct {
+		active string
+		want   ServiceState
+	}{
+		{"active", StateRunning},
+		{"reloading", StateRunning},
+		{"activating", StateStartPending},
+		{"deactivating", StateStopPending},
+		{"inactive", StateStopped},
+		{"failed", StateStopped},
+	}
+	for _, tt := range tests {
+		c, rec := newTestSystemd(t)
+		rec.output["show hj.service --property=ActiveState"] = "ActiveState=" + tt.active + "\n"
+
+		status, err := (&systemdService{c: c, name: "hj"}).Query()
+		if err != nil || status.State != tt.want {
+			t.Errorf("ActiveState=%s: Query = %v, %v, want %v", tt.active, status.State, err, tt.want)
+		}
+	}
+
+	c, _ := newTestSystemd(t)
+	if _, err := (&systemdService{c: c, name: "hj"}).Query(); err == nil {
+		t.Error("ActiveState가 없으면 오류를 반환해야 합니다")
+	}
+}
+
+func TestSystemdDelete(t *testing.T) {
+	c, rec := newTestSystemd(t)
+	h, err := c.CreateService("hj", "/usr/bin/hj", ServiceSpec{})
+	if err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	rec.calls = nil
+	// 활성화되지 않은 유닛의 disable 실패는 무시
+	rec.fail["disable hj.service"] = true
+
+	if err := h.Delete(); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if want := []string{"disable hj.service", "daemon-reload"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("systemctl 호출 = %q, want %q", rec.calls, want)
+	}
+	if err := h.Delete(); !errors.Is(err, ErrServiceNotExist) {
+		t.Errorf("두 번째 Delete 오류 = %v, want ErrServiceNotExist", err)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build windows
+// +build windows
+
+package winsvc
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceArgs는 서비스 관리자가 실행 파일에 전달할 인자입니다
+var serviceArgs = []string{"is", "auto-started"}
+
+// mgrController는 Windows SCM(mgr)을 사용하는 ServiceController 구현입니다
+type mgrController struct {
+	m *mgr.Mgr
+}
+
+// fixedRecoveryActions는 SCM에 지정하는 복구 동작 개수입니다.
+// SCM은 마지막 동작을 이후 모든 실패에 반복하므로 max_restart_attempts와 관계없이 첫 번째/두 번째/이후 실패의 3개를 지정합니다.
+const fixedRecoveryActions = 3
+
+// connectServiceController는 Windows 서비스 관리자에 연결합니다
+func connectServiceController() (ServiceController, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return &mgrController{m: m}, nil
+}
+
+func (c *mgrController) CreateService(name, exePath string, spec ServiceSpec, args ...string) (ServiceHandle, error) {
+	s, err := c.m.CreateService(name, exePath, mgr.Config{
+		DisplayName:      spec.DisplayName,
+		Description:      spec.Description,
+		StartType:        uint32(spec.StartType),
+		ServiceStartName: spec.ServiceStartName,
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &mgrService{s: s}, nil
+}
+
+func (c *mgrController) OpenService(name string) (ServiceHandle, error) {
+	s, err := c.m.OpenService(name)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+			return nil, ErrServiceNotExist
+		}
+		return nil, err
+	}
+	return &mgrService{s: s}, nil
+}
+
+func (c *mgrController) InstallEventSource(name string) error {
+	return eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info)
+}
+
+func (c *mgrController) RemoveEventSource(name string) error {
+	return eventlog.Remove(name)
+}
+
+func (c *mgrController) Disconnect() error {
+	return c.m.Disconnect()
+}
+
+// mgrService는 mgr.Service를 감싸는 ServiceHandle 구현입니다
+type mgrService struct {
+	s *mgr.Service
+}
+
+func (h *mgrService) Start(args ...string) error {
+	return h.s.Start(args...)
+}
+
+func (h *mgrService) Control(cmd ControlCommand) (ServiceStatus, error) {
+	status, err := h.s.Control(svc.Cmd(cmd))
+	return ServiceStatus{State: ServiceState(status.State)}, err
+}
+
+func (h *mgrService) Query() (ServiceStatus, error) {
+	status, err := h.s.Query()
+	return ServiceStatus{State: ServiceState(status.State)}, err
+}
+
+func (h *mgrService) SetRecoveryActions(actions []RecoveryAction, resetPeriod uint32) error {
+	mgrActions := make([]mgr.RecoveryAction, 0, len(actions))
+	for _, a := range actions {
+		mgrActions = append(mgrActions, mgr.RecoveryAction{
+			Type:  int(a.Type),
+			Delay: a.Delay,
+		})
+	}
+	return h.s.SetRecoveryActions(mgrActions, resetPeriod)
+}
+
+func (h *mgrService) Delete() error {
+	return h.s.Delete()
+}
+
+func (h *mgrService) Close() error {
+	return h.s.Close()
+}
@@ -1,99 +1,341 @@
-//go:build windows
-// +build windows
-
 package winsvc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level은 로그 수준입니다. 값이 클수록 심각한 로그입니다.
+type Level int
 
-	"golang.org/x/sys/windows/svc/debug"
+// 로그 수준 정의
+const (
+	LogDebug Level = iota
+	LogInfo
+	LogWarning
+	LogError
 )
 
-// 로그 상수 정의
+// String은 로그에 출력되는 수준 이름을 반환합니다
+func (l Level) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarning:
+		return "WARNING"
+	case LogError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLevel은 설정 값("debug", "info", "warning", "error")을 로그 수준으로 변환합니다
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LogDebug, nil
+	case "info", "":
+		return LogInfo, nil
+	case "warning", "warn":
+		return LogWarning, nil
+	case "error":
+		return LogError, nil
+	default:
+		return LogInfo, fmt.Errorf("알 수 없는 로그 수준입니다: %q (debug, info, warning, error 중 하나)", s)
+	}
+}
+
+// LogFormat은 로그 파일의 출력 형식입니다
+type LogFormat string
+
+// 로그 형식 정의
 const (
-	LogInfo    = "INFO"
-	LogError   = "ERROR"
-	LogWarning = "WARNING"
+	FormatText LogFormat = "text" // 2006/01/02 15:04:05 [INFO] 메시지 key=value
+	FormatJSON LogFormat = "json" // 한 줄에 JSON 객체 하나 (JSON Lines)
 )
 
-// Logger는 여러 로그 출력을 지원하는 로거입니다
+// ParseFormat은 설정 값("text", "json")을 로그 형식으로 변환합니다
+func ParseFormat(s string) (LogFormat, error) {
+	switch LogFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case FormatText, "":
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("알 수 없는 로그 형식입니다: %q (text, json 중 하나)", s)
+	}
+}
+
+// Field는 로그에 함께 기록하는 키/값 항목입니다
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F는 Field를 생성합니다 (예: winsvc.F("path", path))
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger는 여러 로그 출력을 지원하는 로거입니다.
+// 여러 고루틴에서 동시에 사용할 수 있으며, 사용 중에 설정을 바꿀 때는 Set* 메서드를 사용합니다.
 type Logger struct {
-	EventLog debug.Log   // Windows 이벤트 로그
-	FileLog  *log.Logger // 파일 로거
-	LogFile  *os.File    // 로그 파일 핸들
-	IsDebug  bool        // 디버그 모드 여부
-	LogPath  string      // 로그 파일 경로
+	mu sync.RWMutex // 아래 필드와 로그 파일 교체를 보호
+
+	EventLog EventLog       // Windows 이벤트 로그
+	FileLog  *log.Logger    // 파일 로거
+	LogFile  *RotatingFile  // 로그 파일 핸들
+	IsDebug  bool           // 디버그 모드 여부
+	LogPath  string         // 로그 파일 경로
+	MinLevel Level          // 이 수준 미만의 로그는 기록하지 않음
+	Format   LogFormat      // 로그 파일 출력 형식
+	Rotation RotationConfig // 로그 파일 회전 설정
+
+	queue       *asyncQueue   // 비동기 모드일 때의 로그 큐 (nil이면 동기 기록)
+	writeErrors atomic.Uint64 // 파일/이벤트 로그 기록 실패 수
 }
 
 // NewLogger는 새로운 Logger 인스턴스를 생성합니다
 func NewLogger(logPath string, isDebug bool) *Logger {
 	return &Logger{
-		LogPath: logPath,
-		IsDebug: isDebug,
+		LogPath:  logPath,
+		IsDebug:  isDebug,
+		MinLevel: LogInfo,
+		Format:   FormatText,
 	}
 }
 
 // InitializeFileLogger는 파일 로거를 초기화합니다
 func (l *Logger) InitializeFileLogger() error {
-	// 이미 열려있는 파일이 있다면 닫기
-	if l.LogFile != nil {
-		l.LogFile.Close()
+	l.mu.Lock()
+	oldFile := l.LogFile
+	l.LogFile, l.FileLog = nil, nil
+	logPath, rotation := l.LogPath, l.Rotation
+	eventLog, isDebug := l.EventLog, l.IsDebug
+	l.mu.Unlock()
+
+	// 이미 열려있는 파일이 있다면 닫기 (압축 대기가 로그 기록을 막지 않도록 잠금 밖에서)
+	if oldFile != nil {
+		oldFile.Close()
 	}
 
 	// 로그 디렉토리 생성
-	if err := os.MkdirAll(l.LogPath, 0755); err != nil {
+	if err := os.MkdirAll(logPath, 0755); err != nil {
 		return fmt.Errorf("로그 디렉토리 생성 실패: %v", err)
 	}
 
-	var err error
-	logFilePath := filepath.Join(l.LogPath, "service.log")
-	l.LogFile, err = os.OpenFile(logFilePath,
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	logFilePath := filepath.Join(logPath, "service.log")
+	logFile, err := OpenRotatingFile(logFilePath, rotation)
 	if err != nil {
-		return fmt.Errorf("로그 파일 열기 실패: %v", err)
+		return err
+	}
+	// 회전/압축 오류는 파일 잠금 중에 보고될 수 있으므로 Logger 잠금 없이 이벤트 로그와 콘솔에만 남김
+	logFile.OnError = func(err error) {
+		if eventLog != nil {
+			eventLog.Warning(1, err.Error())
+		}
+		if isDebug {
+			log.Printf("[%s] %v", LogWarning, err)
+		}
 	}
 
-	// 파일에만 로그 출력 (콘솔 출력 제거)
-	l.FileLog = log.New(l.LogFile, "", log.LstdFlags)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 파일에만 로그 출력 (콘솔 출력 제거), 시간은 형식에 맞춰 직접 기록
+	l.LogFile = logFile
+	l.FileLog = log.New(logFile, "", 0)
 
 	// 초기화 확인 로그
-	l.FileLog.Printf("파일 로거가 초기화되었습니다. 경로: %s", logFilePath)
+	l.FileLog.Print(l.format(time.Now(), LogInfo, "파일 로거가 초기화되었습니다",
+		[]Field{F("path", logFilePath)}))
 	return nil
 }
 
-// Close는 로거 리소스를 정리합니다
+// Close는 비동기 큐에 남은 로그를 모두 기록한 뒤 로거 리소스를 정리합니다
 func (l *Logger) Close() {
-	if l.LogFile != nil {
-		l.LogFile.Close()
+	l.StopAsync()
+
+	l.mu.Lock()
+	logFile := l.LogFile
+	l.LogFile, l.FileLog = nil, nil
+	l.mu.Unlock()
+
+	if logFile != nil {
+		logFile.Close()
 	}
 }
 
+// SetLevel은 기록할 최소 로그 수준을 바꿉니다
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	l.MinLevel = level
+	l.mu.Unlock()
+}
+
+// SetFormat은 로그 파일 출력 형식을 바꿉니다
+func (l *Logger) SetFormat(format LogFormat) {
+	l.mu.Lock()
+	l.Format = format
+	l.mu.Unlock()
+}
+
+// SetLogPath는 로그 디렉토리를 바꿉니다. 다음 InitializeFileLogger 호출부터 적용됩니다.
+func (l *Logger) SetLogPath(logPath string) {
+	l.mu.Lock()
+	l.LogPath = logPath
+	l.mu.Unlock()
+}
+
+// SetRotation은 회전 설정을 바꿉니다. 다음 InitializeFileLogger 호출부터 적용됩니다.
+func (l *Logger) SetRotation(rotation RotationConfig) {
+	l.mu.Lock()
+	l.Rotation = rotation
+	l.mu.Unlock()
+}
+
+// SetEventLog는 이벤트 로그 출력 대상을 바꿉니다
+func (l *Logger) SetEventLog(eventLog EventLog) {
+	l.mu.Lock()
+	l.EventLog = eventLog
+	l.mu.Unlock()
+}
+
+// SetDebug는 콘솔 출력 여부를 바꿉니다
+func (l *Logger) SetDebug(isDebug bool) {
+	l.mu.Lock()
+	l.IsDebug = isDebug
+	l.mu.Unlock()
+}
+
 // Log는 로그를 기록합니다
-func (l *Logger) Log(level string, format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+func (l *Logger) Log(level Level, format string, args ...interface{}) {
+	l.LogFields(level, fmt.Sprintf(format, args...))
+}
+
+// LogFields는 메시지와 키/값 항목을 함께 기록합니다.
+// JSON 형식에서는 각 항목이 별도 키로 출력되어 메시지를 파싱하지 않고도 값을 얻을 수 있습니다.
+// 비동기 모드에서는 큐에 넣고 바로 반환합니다.
+func (l *Logger) LogFields(level Level, message string, fields ...Field) {
+	l.mu.RLock()
+	minLevel, queue := l.MinLevel, l.queue
+	l.mu.RUnlock()
+
+	if level < minLevel {
+		return
+	}
+	e := logEntry{Time: time.Now(), Level: level, Message: message, Fields: fields}
+	if queue != nil && queue.enqueue(e) {
+		return
+	}
+	l.write(e)
+}
+
+// logEntry는 기록 대기 중인 로그 한 건입니다
+type logEntry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// write는 로그 한 건을 파일, 이벤트 로그, 콘솔에 기록합니다
+func (l *Logger) write(e logEntry) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	level, message, fields := e.Level, e.Message, e.Fields
 
 	// 파일 로그
 	if l.FileLog != nil {
-		l.FileLog.Printf("[%s] %s", level, message)
+		if err := l.FileLog.Output(0, l.format(e.Time, level, message, fields)); err != nil {
+			l.writeErrors.Add(1)
+		}
 	}
 
-	// 이벤트 로그
-	if l.EventLog != nil {
+	// 이벤트 로그 (디버그 로그는 이벤트 로그에 남기지 않음)
+	if l.EventLog != nil && level > LogDebug {
+		text := message + formatFieldsText(fields)
+		var err error
 		switch level {
 		case LogError:
-			l.EventLog.Error(1, message)
+			err = l.EventLog.Error(1, text)
 		case LogWarning:
-			l.EventLog.Warning(1, message)
+			err = l.EventLog.Warning(1, text)
 		default:
-			l.EventLog.Info(1, message)
+			err = l.EventLog.Info(1, text)
+		}
+		if err != nil {
+			l.writeErrors.Add(1)
 		}
 	}
 
 	// 콘솔 출력 (디버그 모드일 때만)
 	if l.IsDebug {
-		log.Printf("[%s] %s", level, message)
+		log.Printf("[%s] %s%s", level, message, formatFieldsText(fields))
+	}
+}
+
+// format은 설정된 형식으로 로그 한 줄을 만듭니다
+func (l *Logger) format(t time.Time, level Level, message string, fields []Field) string {
+	if l.Format == FormatJSON {
+		return formatJSON(t, level, message, fields)
+	}
+	return fmt.Sprintf("%s [%s] %s%s", t.Format("2006/01/02 15:04:05"), level, message, formatFieldsText(fields))
+}
+
+// formatFieldsText는 항목을 " key=value" 형식으로 이어 붙입니다. 공백이 있는 값은 따옴표로 감쌉니다.
+func formatFieldsText(fields []Field) string {
+	var b strings.Builder
+	for _, f := range fields {
+		value := fmt.Sprintf("%v", f.Value)
+		if value == "" || strings.ContainsAny(value, " \t\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", f.Key, value)
+	}
+	return b.String()
+}
+
+// formatJSON은 time, level, msg 다음에 항목을 순서대로 담은 JSON 객체를 만듭니다
+func formatJSON(t time.Time, level Level, message string, fields []Field) string {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	writeJSONPair(&b, "time", t.Format(time.RFC3339Nano))
+	b.WriteByte(',')
+	writeJSONPair(&b, "level", level.String())
+	b.WriteByte(',')
+	writeJSONPair(&b, "msg", message)
+	for _, f := range fields {
+		b.WriteByte(',')
+		writeJSONPair(&b, f.Key, f.Value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func writeJSONPair(b *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	b.Write(k)
+	b.WriteByte(':')
+
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprintf("%v", value))
 	}
+	b.Write(v)
 }
@@ -0,0 +1,212 @@
+package winsvc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DropPolicy는 비동기 로그 큐가 가득 찼을 때의 처리 방식입니다
+type DropPolicy string
+
+// 큐가 가득 찼을 때의 처리 방식 정의
+const (
+	DropNewest DropPolicy = "drop_newest" // 새 로그를 버림
+	DropOldest DropPolicy = "drop_oldest" // 가장 오래된 대기 로그를 버리고 새 로그를 넣음
+	DropNone   DropPolicy = "block"       // 버리지 않고 자리가 날 때까지 기다림
+)
+
+// ParseDropPolicy는 설정 값("drop_newest", "drop_oldest", "block")을 DropPolicy로 변환합니다
+func ParseDropPolicy(s string) (DropPolicy, error) {
+	switch p := DropPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case DropNewest, DropOldest, DropNone:
+		return p, nil
+	case "":
+		return DropNewest, nil
+	default:
+		return DropNewest, fmt.Errorf("알 수 없는 drop_policy입니다: %q (drop_newest, drop_oldest, block 중 하나)", s)
+	}
+}
+
+// AsyncConfig는 비동기 로그 기록 설정입니다
+type AsyncConfig struct {
+	Enabled    bool       `json:"enabled"`     // 비동기 기록 사용 여부
+	QueueSize  int        `json:"queue_size"`  // 기록 대기 큐 크기
+	DropPolicy DropPolicy `json:"drop_policy"` // 큐가 가득 찼을 때의 처리 방식
+}
+
+// DefaultLogQueueSize는 QueueSize가 0 이하일 때 사용하는 큐 크기입니다
+const DefaultLogQueueSize = 1024
+
+// LoggerStats는 로거의 큐 상태, 버린 로그 수, 기록 실패 수입니다
+type LoggerStats struct {
+	WriteErrors    uint64           // 파일/이벤트 로그 기록 실패 수
+	Async          bool             // 비동기 모드 여부
+	QueueLen       int              // 기록 대기 중인 로그 수
+	QueueCap       int              // 큐 크기
+	Dropped        uint64           // 버린 로그 수 합계
+	DroppedByLevel map[Level]uint64 // 수준별 버린 로그 수
+}
+
+// asyncQueue는 로그를 받아 별도 고루틴에서 기록하는 크기 제한 큐입니다
+type asyncQueue struct {
+	entries chan logEntry
+	policy  DropPolicy
+	done    chan struct{}
+
+	// closed 이후에는 entries에 보내지 않도록 보호 (닫힌 채널에 보내면 panic)
+	closeMu sync.RWMutex
+	closed  bool
+
+	dropped [LogError + 1]atomic.Uint64
+}
+
+// enqueue는 로그를 큐에 넣습니다. 큐가 닫혀 있으면 false를 반환하며 호출자가 직접 기록합니다.
+func (q *asyncQueue) enqueue(e logEntry) bool {
+	q.closeMu.RLock()
+	defer q.closeMu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	switch q.policy {
+	case DropNone:
+		q.entries <- e
+		return true
+
+	case DropOldest:
+		for {
+			select {
+			case q.entries <- e:
+				return true
+			default:
+			}
+			// 가장 오래된 로그를 하나 꺼내 버리고 다시 시도
+			select {
+			case old := <-q.entries:
+				q.countDrop(old.Level)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case q.entries <- e:
+		default:
+			q.countDrop(e.Level)
+		}
+		return true
+	}
+}
+
+func (q *asyncQueue) countDrop(level Level) {
+	if level < LogDebug || level > LogError {
+		level = LogError
+	}
+	q.dropped[level].Add(1)
+}
+
+func (q *asyncQueue) droppedTotal() uint64 {
+	var total uint64
+	for i := range q.dropped {
+		total += q.dropped[i].Load()
+	}
+	return total
+}
+
+// StartAsync는 로그를 큐에 넣고 별도 고루틴에서 기록하는 비동기 모드를 시작합니다.
+// 파일 이벤트가 몰려도 로그를 남기는 쪽(서비스 제어 처리 등)이 디스크 기록을 기다리지 않습니다.
+func (l *Logger) StartAsync(config AsyncConfig) error {
+	policy, err := ParseDropPolicy(string(config.DropPolicy))
+	if err != nil {
+		return err
+	}
+	size := config.QueueSize
+	if size <= 0 {
+		size = DefaultLogQueueSize
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.queue != nil {
+		return fmt.Errorf("비동기 로그 기록이 이미 시작되었습니다")
+	}
+
+	q := &asyncQueue{
+		entries: make(chan logEntry, size),
+		policy:  policy,
+		done:    make(chan struct{}),
+	}
+	l.queue = q
+	go l.drain(q)
+	return nil
+}
+
+// drain은 큐의 로그를 순서대로 기록하고, 큐가 비면 그동안 버린 로그 수를 경고로 남깁니다
+func (l *Logger) drain(q *asyncQueue) {
+	defer close(q.done)
+
+	var reported uint64
+	for e := range q.entries {
+		l.write(e)
+
+		if len(q.entries) > 0 {
+			continue
+		}
+		if total := q.droppedTotal(); total > reported {
+			l.write(logEntry{
+				Time:    time.Now(),
+				Level:   LogWarning,
+				Message: "로그 큐가 가득 차 로그를 버렸습니다",
+				Fields:  []Field{F("dropped", total-reported), F("dropped_total", total)},
+			})
+			reported = total
+		}
+	}
+}
+
+// StopAsync는 큐에 남은 로그를 모두 기록하고 동기 모드로 돌아갑니다
+func (l *Logger) StopAsync() {
+	l.mu.Lock()
+	q := l.queue
+	l.queue = nil
+	l.mu.Unlock()
+
+	if q == nil {
+		return
+	}
+
+	q.closeMu.Lock()
+	q.closed = true
+	close(q.entries)
+	q.closeMu.Unlock()
+
+	<-q.done
+}
+
+// Stats는 비동기 큐 상태, 버린 로그 수, 기록 실패 수를 반환합니다
+func (l *Logger) Stats() LoggerStats {
+	l.mu.RLock()
+	q := l.queue
+	l.mu.RUnlock()
+
+	stats := LoggerStats{
+		WriteErrors:    l.writeErrors.Load(),
+		DroppedByLevel: make(map[Level]uint64),
+	}
+	if q == nil {
+		return stats
+	}
+
+	stats.Async = true
+	stats.QueueLen = len(q.entries)
+	stats.QueueCap = cap(q.entries)
+	for level := LogDebug; level <= LogError; level++ {
+		n := q.dropped[level].Load()
+		stats.DroppedByLevel[level] = n
+		stats.Dropped += n
+	}
+	return stats
+}
@@ -0,0 +1,226 @@
+package winsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingEventLog는 release가 닫힐 때까지 기록을 멈추는 EventLog입니다.
+// 첫 기록을 시작하면 entered로 알려 비동기 기록 고루틴이 멈춘 상태에서 큐를 채울 수 있게 합니다.
+type blockingEventLog struct {
+	mu       sync.Mutex
+	messages []string
+	entered  chan struct{}
+	release  chan struct{}
+	once     sync.Once
+}
+
+func newBlockingEventLog() *blockingEventLog {
+	return &blockingEventLog{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (e *blockingEventLog) record(msg string) error {
+	e.once.Do(func() { close(e.entered) })
+	<-e.release
+	e.mu.Lock()
+	e.messages = append(e.messages, msg)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *blockingEventLog) Info(eid uint32, msg string) error    { return e.record(msg) }
+func (e *blockingEventLog) Warning(eid uint32, msg string) error { return e.record(msg) }
+func (e *blockingEventLog) Error(eid uint32, msg string) error   { return e.record(msg) }
+func (e *blockingEventLog) Close() error                         { return nil }
+
+func (e *blockingEventLog) Messages() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.messages...)
+}
+
+// startBlockedAsync는 첫 로그를 기록하는 중에 멈춘 비동기 로거를 만듭니다
+func startBlockedAsync(t *testing.T, policy DropPolicy, queueSize int) (*Logger, *blockingEventLog) {
+	t.Helper()
+	eventLog := newBlockingEventLog()
+	l := NewLogger(t.TempDir(), false)
+	l.SetEventLog(eventLog)
+	if err := l.StartAsync(AsyncConfig{Enabled: true, QueueSize: queueSize, DropPolicy: policy}); err != nil {
+		t.Fatalf("StartAsync: %v", err)
+	}
+	t.Cleanup(func() {
+		select {
+		case <-eventLog.release:
+		default:
+			close(eventLog.release)
+		}
+		l.Close()
+	})
+
+	l.Log(LogInfo, "m0")
+	select {
+	case <-eventLog.entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("비동기 기록이 시작되지 않았습니다")
+	}
+	return l, eventLog
+}
+
+func TestAsyncDropPolicy(t *testing.T) {
+	tests := []struct {
+		policy  DropPolicy
+		want    string
+		dropped map[Level]uint64
+	}{
+		{DropNewest, "m0,m1,m2", map[Level]uint64{LogWarning: 1, LogError: 1}},
+		{DropOldest, "m0,m3,m4", map[Level]uint64{LogInfo: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			l, eventLog := startBlockedAsync(t, tt.policy, 2)
+
+			l.Log(LogInfo, "m1")
+			l.Log(LogInfo, "m2")
+			l.Log(LogWarning, "m3")
+			l.Log(LogError, "m4")
+
+			stats := l.Stats()
+			if !stats.Async || stats.QueueLen != 2 || stats.QueueCap != 2 || stats.Dropped != 2 {
+				t.Errorf("Stats = %+v, want 큐 2/2, 버린 로그 2", stats)
+			}
+			for level, n := range tt.dropped {
+				if stats.DroppedByLevel[level] != n {
+					t.Errorf("%s 버린 로그 = %d, want %d", level, stats.DroppedByLevel[level], n)
+				}
+			}
+
+			close(eventLog.release)
+			l.StopAsync()
+
+			messages := eventLog.Messages()
+			if len(messages) != 4 {
+				t.Fatalf("기록된 로그 = %q, want 3개와 버린 로그 경고", messages)
+			}
+			if got := strings.Join(messages[:3], ","); got != tt.want {
+				t.Errorf("기록 순서 = %s, want %s", got, tt.want)
+			}
+			if !strings.Contains(messages[3], "로그 큐가 가득 차") || !strings.Contains(messages[3], "dropped=2") {
+				t.Errorf("버린 로그 경고 = %q", messages[3])
+			}
+		})
+	}
+}
+
+func TestAsyncBlockPolicy(t *testing.T) {
+	l, eventLog := startBlockedAsync(t, DropNone, 1)
+	l.Log(LogInfo, "m1")
+
+	// 큐가 가득 차면 버리지 않고 자리가 날 때까지 기다림
+	logged := make(chan struct{})
+	go func() {
+		l.Log(LogInfo, "m2")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+		t.Fatal("큐가 가득 찼는데 Log가 기다리지 않았습니다")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(eventLog.release)
+	<-logged
+	l.StopAsync()
+	if got := strings.Join(eventLog.Messages(), ","); got != "m0,m1,m2" {
+		t.Errorf("기록된 로그 = %s, want m0,m1,m2", got)
+	}
+	if stats := l.Stats(); stats.Dropped != 0 || stats.Async {
+		t.Errorf("Stats = %+v, want 버린 로그 없음, 동기 모드", stats)
+	}
+}
+
+func TestAsyncFlushOnClose(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLogger(dir, false)
+	if err := l.InitializeFileLogger(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.StartAsync(AsyncConfig{Enabled: true, QueueSize: 1000, DropPolicy: DropNone}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.StartAsync(AsyncConfig{Enabled: true}); err == nil {
+		t.Error("두 번째 StartAsync는 오류를 반환해야 합니다")
+	}
+
+	for i := 0; i < 500; i++ {
+		l.Log(LogInfo, "line %d", i)
+	}
+	// Close는 큐에 남은 로그를 모두 기록한 뒤 파일을 닫음
+	l.Close()
+	l.Log(LogInfo, "closed")
+
+	data, err := os.ReadFile(filepath.Join(dir, "service.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 500; i++ {
+		if !strings.Contains(string(data), fmt.Sprintf("line %d\n", i)) {
+			t.Fatalf("line %d가 기록되지 않았습니다", i)
+		}
+	}
+	if strings.Contains(string(data), "closed") {
+		t.Error("Close 이후의 로그가 파일에 기록되었습니다")
+	}
+}
+
+func TestLoggerConcurrentReinitialize(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		t.Run(fmt.Sprintf("async=%v", async), func(t *testing.T) {
+			dir := t.TempDir()
+			l := NewLogger(dir, false)
+			if err := l.InitializeFileLogger(); err != nil {
+				t.Fatal(err)
+			}
+			if async {
+				if err := l.StartAsync(AsyncConfig{Enabled: true, DropPolicy: DropNone}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// 로그를 남기는 동안 파일 로거를 다시 열고 설정을 바꿔도 데이터 경쟁이 없어야 함
+			const writers, lines = 4, 200
+			var wg sync.WaitGroup
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < lines; i++ {
+						l.LogFields(LogInfo, "event", F("writer", w), F("line", i))
+					}
+				}(w)
+			}
+			for i := 0; i < 20; i++ {
+				if err := l.InitializeFileLogger(); err != nil {
+					t.Errorf("InitializeFileLogger: %v", err)
+				}
+				l.SetFormat([]LogFormat{FormatText, FormatJSON}[i%2])
+				l.SetLevel(LogInfo)
+				l.Stats()
+			}
+			wg.Wait()
+			l.Close()
+
+			data, err := os.ReadFile(filepath.Join(dir, "service.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(string(data), "event"); n != writers*lines {
+				t.Errorf("기록된 로그 = %d, want %d", n, writers*lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package winsvc
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotationConfig는 로그 파일 회전 설정입니다
+type RotationConfig struct {
+	MaxSizeMB     int  `json:"max_size_mb"`    // 파일이 이 크기를 넘으면 회전, 0이면 크기 기준 회전 안 함
+	IntervalHours int  `json:"interval_hours"` // 이 주기의 경계(UTC 기준)를 지나면 회전, 0이면 시간 기준 회전 안 함
+	MaxFiles      int  `json:"max_files"`      // 보관할 회전된 파일 수, 0이면 모두 보관
+	Compress      bool `json:"compress"`       // 회전된 파일을 gzip으로 압축
+}
+
+// rotatedTimeFormat은 회전된 파일 이름에 붙는 시각 형식입니다 (이름순 정렬 = 시간순 정렬)
+const rotatedTimeFormat = "20060102T150405.000"
+
+// RotatingFile은 크기와 시간 기준으로 회전하는 로그 파일입니다.
+// 여러 고루틴에서 동시에 Write를 호출해도 안전합니다.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	config   RotationConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// 압축과 오래된 파일 정리는 백그라운드에서 한 번에 하나씩 수행
+	cleanupMu sync.Mutex
+	cleanupWg sync.WaitGroup
+
+	// Now는 현재 시각을 반환합니다 (테스트에서 교체 가능)
+	Now func() time.Time
+	// OnError는 백그라운드 압축/정리 오류를 전달받습니다 (nil이면 무시)
+	OnError func(err error)
+}
+
+// OpenRotatingFile은 로그 파일을 추가 모드로 열고 회전 설정을 적용합니다
+func OpenRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:   path,
+		config: config,
+		Now:    time.Now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open은 로그 파일을 열고 현재 크기와 마지막 기록 시각을 읽습니다
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("로그 파일 열기 실패: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("로그 파일 정보 조회 실패: %v", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.Now()
+	// 기존 내용이 있으면 마지막 기록 시각을 기준으로 시간 경계를 계산
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write는 필요하면 파일을 회전한 뒤 p를 기록합니다
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// shouldRotate는 n 바이트를 더 기록하기 전에 회전해야 하는지 판단합니다
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.config.MaxSizeMB > 0 && f.size+n > int64(f.config.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	if f.config.IntervalHours > 0 {
+		interval := time.Duration(f.config.IntervalHours) * time.Hour
+		next := f.openedAt.Truncate(interval).Add(interval)
+		if !f.Now().Before(next) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotate는 조건과 관계없이 현재 파일을 즉시 회전합니다
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// rotate는 현재 파일을 닫고 시각이 붙은 이름으로 바꾼 뒤 새 파일을 엽니다.
+// Windows에서는 열린 파일의 이름을 바꿀 수 없으므로 먼저 닫습니다.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("로그 파일 닫기 실패: %v", err)
+	}
+	f.file = nil
+
+	rotated := f.rotatedName(f.Now())
+	renameErr := os.Rename(f.path, rotated)
+
+	// 이름 변경에 실패해도 기존 파일에 계속 기록할 수 있도록 다시 엶
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		// 다른 프로세스가 파일을 잡고 있는 경우 등: 로그 기록은 계속하고 다음 주기에 다시 시도
+		f.openedAt = f.Now()
+		f.reportError(fmt.Errorf("로그 파일 회전 실패: %v", renameErr))
+		return nil
+	}
+
+	f.cleanupWg.Add(1)
+	go func() {
+		defer f.cleanupWg.Done()
+		f.cleanup()
+	}()
+	return nil
+}
+
+// rotatedName은 기존 파일과 겹치지 않는 회전 파일 이름을 만듭니다 (예: service-20250101T000000.000.log)
+func (f *RotatingFile) rotatedName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext) + "-" + t.Format(rotatedTimeFormat)
+
+	name := base + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// cleanup은 압축하지 않은 회전 파일을 압축하고 보관 개수를 넘는 오래된 파일을 삭제합니다.
+// 정리 고루틴은 회전한 순서대로 실행된다는 보장이 없으므로 방금 회전한 파일만이 아니라 회전된 파일 전체를 확인합니다.
+func (f *RotatingFile) cleanup() {
+	f.cleanupMu.Lock()
+	defer f.cleanupMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		f.reportError(fmt.Errorf("회전된 로그 파일 조회 실패: %v", err))
+		return
+	}
+
+	if f.config.Compress {
+		for i, backup := range backups {
+			if strings.HasSuffix(backup, ".gz") {
+				continue
+			}
+			if err := compressFile(backup); err != nil {
+				f.reportError(fmt.Errorf("로그 파일 압축 실패: %v", err))
+				continue
+			}
+			backups[i] = backup + ".gz"
+		}
+	}
+
+	if f.config.MaxFiles <= 0 {
+		return
+	}
+	for i := 0; i < len(backups)-f.config.MaxFiles; i++ {
+		if err := os.Remove(backups[i]); err != nil {
+			f.reportError(fmt.Errorf("오래된 로그 파일 삭제 실패: %v", err))
+		}
+	}
+}
+
+// backups는 회전된 파일 목록을 오래된 순서로 반환합니다
+func (f *RotatingFile) backups() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			files = append(files, filepath.Join(filepath.Dir(f.path), name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (f *RotatingFile) reportError(err error) {
+	if f.OnError != nil {
+		f.OnError(err)
+	}
+}
+
+// compressFile은 파일을 path.gz로 압축하고 원본을 삭제합니다
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// 압축 도중 중단되어도 불완전한 .gz 파일이 남지 않도록 임시 파일에 기록
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	gz.Name = filepath.Base(path)
+	_, err = io.Copy(gz, src)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// Close는 파일을 닫고 진행 중인 압축과 정리가 끝날 때까지 기다립니다
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.cleanupWg.Wait()
+	return err
+}
@@ -0,0 +1,246 @@
+package winsvc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openTestRotatingFile은 임시 디렉토리의 service.log를 고정된 시각으로 엽니다.
+// 반환한 함수로 시각을 바꿀 수 있습니다.
+func openTestRotatingFile(t *testing.T, config RotationConfig) (*RotatingFile, func(time.Time)) {
+	t.Helper()
+	now := time.Date(2025, 1, 1, 9, 10, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "service.log")
+	f := &RotatingFile{path: path, config: config, Now: func() time.Time { return now }}
+	if err := f.open(); err != nil {
+		t.Fatal(err)
+	}
+	f.OnError = func(err error) { t.Errorf("OnError: %v", err) }
+	t.Cleanup(func() { f.Close() })
+	return f, func(t time.Time) { now = t }
+}
+
+// backupNames는 회전된 파일 이름을 오래된 순서로 반환합니다
+func backupNames(t *testing.T, f *RotatingFile) []string {
+	t.Helper()
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(backups))
+	for i, b := range backups {
+		names[i] = filepath.Base(b)
+	}
+	return names
+}
+
+// readLog는 로그 파일 내용을 읽습니다. .gz 파일은 압축을 풉니다.
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func write(t *testing.T, f *RotatingFile, s string) {
+	t.Helper()
+	if _, err := f.Write([]byte(s)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+func TestRotateBySize(t *testing.T) {
+	f, _ := openTestRotatingFile(t, RotationConfig{MaxSizeMB: 1})
+	chunk := strings.Repeat("a", 600*1024)
+
+	write(t, f, chunk)
+	if names := backupNames(t, f); len(names) != 0 {
+		t.Fatalf("크기 제한 전에 회전했습니다: %v", names)
+	}
+	// 기록하면 1MB를 넘으므로 먼저 회전
+	write(t, f, chunk)
+	if names := backupNames(t, f); len(names) != 1 || names[0] != "service-20250101T091000.000.log" {
+		t.Fatalf("회전된 파일 = %v, want [service-20250101T091000.000.log]", names)
+	}
+	f.Close()
+
+	if got := readLog(t, f.path); len(got) != len(chunk) {
+		t.Errorf("현재 파일 크기 = %d, want %d", len(got), len(chunk))
+	}
+}
+
+func TestRotateByTime(t *testing.T) {
+	f, setNow := openTestRotatingFile(t, RotationConfig{IntervalHours: 1})
+
+	write(t, f, "first\n")
+	setNow(time.Date(2025, 1, 1, 9, 59, 59, 0, time.UTC))
+	write(t, f, "second\n")
+	if names := backupNames(t, f); len(names) != 0 {
+		t.Fatalf("시간 경계 전에 회전했습니다: %v", names)
+	}
+
+	// 정각(UTC) 경계를 지나면 회전
+	setNow(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	write(t, f, "third\n")
+	names := backupNames(t, f)
+	if len(names) != 1 {
+		t.Fatalf("회전된 파일 = %v, want 1개", names)
+	}
+	f.Close()
+
+	dir := filepath.Dir(f.path)
+	if got := readLog(t, filepath.Join(dir, names[0])); got != "first\nsecond\n" {
+		t.Errorf("회전된 파일 내용 = %q", got)
+	}
+	if got := readLog(t, f.path); got != "third\n" {
+		t.Errorf("현재 파일 내용 = %q", got)
+	}
+}
+
+func TestRotateReopenUsesModTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2025, 1, 1, 8, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	// 이전 실행에서 8시대에 기록한 파일은 9시대에 다시 열어 처음 기록할 때 회전
+	f := &RotatingFile{path: path, config: RotationConfig{IntervalHours: 1},
+		Now: func() time.Time { return time.Date(2025, 1, 1, 9, 5, 0, 0, time.UTC) }}
+	if err := f.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	write(t, f, "new\n")
+	if names := backupNames(t, f); len(names) != 1 {
+		t.Errorf("회전된 파일 = %v, want 1개", names)
+	}
+}
+
+func TestRotateRetentionAndCompress(t *testing.T) {
+	f, setNow := openTestRotatingFile(t, RotationConfig{MaxFiles: 2, Compress: true})
+
+	for i := 0; i < 4; i++ {
+		setNow(time.Date(2025, 1, 1, 9, i, 0, 0, time.UTC))
+		write(t, f, fmt.Sprintf("line %d\n", i))
+		if err := f.Rotate(); err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+	}
+	// Close는 백그라운드 압축과 정리를 기다림
+	f.Close()
+
+	names := backupNames(t, f)
+	want := []string{"service-20250101T090200.000.log.gz", "service-20250101T090300.000.log.gz"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("보관된 파일 = %v, want %v", names, want)
+	}
+	dir := filepath.Dir(f.path)
+	if got := readLog(t, filepath.Join(dir, want[1])); got != "line 3\n" {
+		t.Errorf("압축 파일 내용 = %q, want %q", got, "line 3\n")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("임시 파일이 남아 있습니다: %v", matches)
+	}
+}
+
+func TestRotatedNameUnique(t *testing.T) {
+	f, _ := openTestRotatingFile(t, RotationConfig{})
+
+	// 같은 시각에 여러 번 회전해도 기존 파일을 덮어쓰지 않음
+	for i := 0; i < 3; i++ {
+		write(t, f, fmt.Sprintf("%d\n", i))
+		if err := f.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Close()
+	want := []string{
+		"service-20250101T091000.000-1.log",
+		"service-20250101T091000.000-2.log",
+		"service-20250101T091000.000.log",
+	}
+	if names := backupNames(t, f); strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("회전된 파일 = %v, want %v", names, want)
+	}
+}
+
+func TestRotateConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+	f, err := OpenRotatingFile(path, RotationConfig{Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 회전하는 동안 기록한 줄도 잃어버리거나 섞이지 않아야 함
+	const writers, lines = 4, 200
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				if _, err := fmt.Fprintf(f, "writer=%d line=%d\n", w, i); err != nil {
+					t.Errorf("Write: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	for i := 0; i < 20; i++ {
+		if err := f.Rotate(); err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+	}
+	wg.Wait()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("x")); err != os.ErrClosed {
+		t.Errorf("Close 후 Write 오류 = %v, want os.ErrClosed", err)
+	}
+
+	seen := make(map[string]bool)
+	files, _ := f.backups()
+	for _, file := range append(files, path) {
+		scanner := bufio.NewScanner(strings.NewReader(readLog(t, file)))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if seen[line] || !strings.HasPrefix(line, "writer=") {
+				t.Errorf("%s: 잘못되거나 중복된 줄 %q", filepath.Base(file), line)
+			}
+			seen[line] = true
+		}
+	}
+	if len(seen) != writers*lines {
+		t.Errorf("기록된 줄 = %d, want %d", len(seen), writers*lines)
+	}
+}
@@ -1,18 +1,11 @@
-//go:build windows
-// +build windows
-
 package winsvc
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
-
-	"golang.org/x/sys/windows/svc"
-	"golang.org/x/sys/windows/svc/debug"
-	"golang.org/x/sys/windows/svc/eventlog"
-	"golang.org/x/sys/windows/svc/mgr"
 )
 
 // ServiceConfig는 서비스 설정 정보를 담는 구조체
@@ -25,19 +18,80 @@ type ServiceConfig struct {
 	MaxRestartAttempts int
 }
 
-// ServiceManager는 Windows 서비스 관리를 위한 구조체
+// EventLog는 Windows 이벤트 로그(debug.Log)와 같은 형태의 로그 출력 인터페이스입니다
+type EventLog interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+// ServiceManager는 서비스 관리를 위한 구조체 (Windows SCM 또는 systemd)
 type ServiceManager struct {
 	Config  *ServiceConfig
-	Elog    debug.Log
+	Elog    EventLog
 	IsDebug bool
+	// Connect는 서비스 관리자에 연결합니다 (테스트에서는 FakeController.Connect로 교체)
+	Connect func() (ServiceController, error)
+	// PollInterval은 중지 대기 시 상태 확인 간격입니다
+	PollInterval time.Duration
+	// StopTimeout은 서비스가 중지될 때까지 기다리는 최대 시간입니다 (0이면 DefaultStopTimeout)
+	StopTimeout time.Duration
 }
 
+// DefaultStopTimeout은 서비스 중지를 기다리는 기본 최대 시간입니다
+const DefaultStopTimeout = 30 * time.Second
+
 // NewServiceManager는 새로운 ServiceManager 인스턴스를 생성합니다
 func NewServiceManager(config *ServiceConfig) *ServiceManager {
 	return &ServiceManager{
-		Config:  config,
-		IsDebug: false,
+		Config:       config,
+		IsDebug:      false,
+		Connect:      connectServiceController,
+		PollInterval: 500 * time.Millisecond,
+		StopTimeout:  DefaultStopTimeout,
+	}
+}
+
+// connect는 서비스 관리자에 연결합니다
+func (sm *ServiceManager) connect() (ServiceController, error) {
+	m, err := sm.Connect()
+	if err != nil {
+		return nil, fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
+	}
+	return m, nil
+}
+
+// openService는 설정된 서비스를 엽니다
+func (sm *ServiceManager) openService(m ServiceController) (ServiceHandle, error) {
+	s, err := m.OpenService(sm.Config.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("서비스 %s를 열 수 없습니다: %w", sm.Config.ServiceName, err)
 	}
+	return s, nil
+}
+
+// waitStopped는 서비스가 완전히 중지될 때까지 대기합니다.
+// StopTimeout 안에 중지되지 않으면 (종료 처리가 멈춘 서비스 등) 오류를 반환합니다.
+func (sm *ServiceManager) waitStopped(s ServiceHandle, status ServiceStatus) error {
+	timeout := sm.StopTimeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	var err error
+	for status.State != StateStopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("서비스가 %v 안에 중지되지 않았습니다 (현재 상태: %s)", timeout, status.State)
+		}
+		time.Sleep(sm.PollInterval)
+		status, err = s.Query()
+		if err != nil {
+			return fmt.Errorf("서비스 상태를 확인할 수 없습니다: %v", err)
+		}
+	}
+	return nil
 }
 
 // Install은 서비스를 설치합니다
@@ -47,9 +101,9 @@ func (sm *ServiceManager) Install() error {
 		return fmt.Errorf("실행 파일 경로를 가져올 수 없습니다: %v", err)
 	}
 
-	m, err := mgr.Connect()
+	m, err := sm.connect()
 	if err != nil {
-		return fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
+		return err
 	}
 	defer m.Disconnect()
 
@@ -58,14 +112,17 @@ func (sm *ServiceManager) Install() error {
 		s.Close()
 		return fmt.Errorf("서비스 %s가 이미 존재합니다", sm.Config.ServiceName)
 	}
+	if !errors.Is(err, ErrServiceNotExist) {
+		return fmt.Errorf("서비스 %s를 확인할 수 없습니다: %v", sm.Config.ServiceName, err)
+	}
 
 	// 서비스 생성
-	s, err = m.CreateService(sm.Config.ServiceName, exepath, mgr.Config{
+	s, err = m.CreateService(sm.Config.ServiceName, exepath, ServiceSpec{
 		DisplayName:      sm.Config.ServiceName,
 		Description:      sm.Config.ServiceDescription,
-		StartType:        mgr.StartAutomatic,
+		StartType:        StartAutomatic,
 		ServiceStartName: "", // LocalSystem 계정
-	}, "is", "auto-started")
+	}, serviceArgs...)
 	if err != nil {
 		return fmt.Errorf("서비스를 생성할 수 없습니다: %v", err)
 	}
@@ -75,18 +132,14 @@ func (sm *ServiceManager) Install() error {
 	if sm.Config.RestartOnFailure {
 		// 재시작 설정은 서비스가 생성된 후 SetRecoveryActions 함수를 사용하여 설정
 		// 일부 Windows 버전에서는 지원되지 않을 수 있음
-		err = s.SetRecoveryActions([]mgr.RecoveryAction{
-			{Type: mgr.ServiceRestart, Delay: time.Duration(sm.Config.RestartDelay) * time.Second},
-			{Type: mgr.ServiceRestart, Delay: time.Duration(sm.Config.RestartDelay*2) * time.Second},
-			{Type: mgr.ServiceRestart, Delay: time.Duration(sm.Config.RestartDelay*3) * time.Second},
-		}, uint32(60)) // 60초 동안 오류가 없으면 카운터 리셋
+		err = s.SetRecoveryActions(sm.recoveryActions(), uint32(60)) // 60초 동안 오류가 없으면 카운터 리셋
 		if err != nil {
 			log.Printf("서비스 재시작 정책 설정 실패(무시됨): %v", err)
 		}
 	}
 
 	// 이벤트 로그 생성
-	err = eventlog.InstallAsEventCreate(sm.Config.ServiceName, eventlog.Error|eventlog.Warning|eventlog.Info)
+	err = m.InstallEventSource(sm.Config.ServiceName)
 	if err != nil {
 		s.Delete()
 		return fmt.Errorf("이벤트 로그 설치 실패: %v", err)
@@ -96,34 +149,49 @@ func (sm *ServiceManager) Install() error {
 	return nil
 }
 
+// recoveryActions는 재시작 정책 설정으로 복구 동작 목록을 만듭니다. 지연은 RestartDelay의 배수로 늘어납니다.
+// 동작 개수는 fixedRecoveryActions가 있으면 그 값(Windows는 기존처럼 3개, SCM이 마지막 동작을 이후 실패에 반복),
+// 없으면 MaxRestartAttempts입니다 (systemd는 동작 개수를 StartLimitBurst로 사용).
+func (sm *ServiceManager) recoveryActions() []RecoveryAction {
+	attempts := fixedRecoveryActions
+	if attempts <= 0 {
+		attempts = sm.Config.MaxRestartAttempts
+	}
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	actions := make([]RecoveryAction, 0, attempts)
+	for i := 1; i <= attempts; i++ {
+		actions = append(actions, RecoveryAction{
+			Type:  RecoveryRestart,
+			Delay: time.Duration(sm.Config.RestartDelay*i) * time.Second,
+		})
+	}
+	return actions
+}
+
 // Remove는 서비스를 제거합니다
 func (sm *ServiceManager) Remove() error {
-	m, err := mgr.Connect()
+	m, err := sm.connect()
 	if err != nil {
-		return fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
+		return err
 	}
 	defer m.Disconnect()
 
-	s, err := m.OpenService(sm.Config.ServiceName)
+	s, err := sm.openService(m)
 	if err != nil {
-		return fmt.Errorf("서비스 %s를 열 수 없습니다: %v", sm.Config.ServiceName, err)
+		return err
 	}
 	defer s.Close()
 
 	// 서비스 중지
-	status, err := s.Control(svc.Stop)
+	status, err := s.Control(ControlStop)
 	if err != nil {
 		// 중지 실패는 무시하고 계속 진행
 		fmt.Printf("서비스를 중지하는 중 오류 발생 (무시됨): %v\n", err)
-	} else {
-		// 서비스가 완전히 중지될 때까지 대기
-		for status.State != svc.Stopped {
-			time.Sleep(500 * time.Millisecond)
-			status, err = s.Query()
-			if err != nil {
-				break
-			}
-		}
+	} else if err := sm.waitStopped(s, status); err != nil {
+		fmt.Printf("서비스 중지 대기 중 오류 발생 (무시됨): %v\n", err)
 	}
 
 	// 서비스 삭제
@@ -133,7 +201,7 @@ func (sm *ServiceManager) Remove() error {
 	}
 
 	// 이벤트 로그 제거
-	err = eventlog.Remove(sm.Config.ServiceName)
+	err = m.RemoveEventSource(sm.Config.ServiceName)
 	if err != nil {
 		return fmt.Errorf("이벤트 로그를 제거할 수 없습니다: %v", err)
 	}
@@ -144,15 +212,15 @@ func (sm *ServiceManager) Remove() error {
 
 // Start는 서비스를 시작합니다
 func (sm *ServiceManager) Start() error {
-	m, err := mgr.Connect()
+	m, err := sm.connect()
 	if err != nil {
-		return fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
+		return err
 	}
 	defer m.Disconnect()
 
-	s, err := m.OpenService(sm.Config.ServiceName)
+	s, err := sm.openService(m)
 	if err != nil {
-		return fmt.Errorf("서비스 %s를 열 수 없습니다: %v", sm.Config.ServiceName, err)
+		return err
 	}
 	defer s.Close()
 
@@ -167,30 +235,26 @@ func (sm *ServiceManager) Start() error {
 
 // Stop은 서비스를 중지합니다
 func (sm *ServiceManager) Stop() error {
-	m, err := mgr.Connect()
+	m, err := sm.connect()
 	if err != nil {
-		return fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
+		return err
 	}
 	defer m.Disconnect()
 
-	s, err := m.OpenService(sm.Config.ServiceName)
+	s, err := sm.openService(m)
 	if err != nil {
-		return fmt.Errorf("서비스 %s를 열 수 없습니다: %v", sm.Config.ServiceName, err)
+		return err
 	}
 	defer s.Close()
 
-	status, err := s.Control(svc.Stop)
+	status, err := s.Control(ControlStop)
 	if err != nil {
 		return fmt.Errorf("서비스를 중지할 수 없습니다: %v", err)
 	}
 
 	// 서비스가 완전히 중지될 때까지 대기
-	for status.State != svc.Stopped {
-		time.Sleep(500 * time.Millisecond)
-		status, err = s.Query()
-		if err != nil {
-			return fmt.Errorf("서비스 상태를 확인할 수 없습니다: %v", err)
-		}
+	if err := sm.waitStopped(s, status); err != nil {
+		return err
 	}
 
 	fmt.Printf("서비스 '%s'가 중지되었습니다.\n", sm.Config.ServiceName)
@@ -199,79 +263,32 @@ func (sm *ServiceManager) Stop() error {
 
 // Status는 서비스 상태를 확인합니다
 func (sm *ServiceManager) Status() error {
-	m, err := mgr.Connect()
-	if err != nil {
-		return fmt.Errorf("서비스 관리자에 연결할 수 없습니다: %v", err)
-	}
-	defer m.Disconnect()
-
-	s, err := m.OpenService(sm.Config.ServiceName)
-	if err != nil {
-		return fmt.Errorf("서비스 %s를 열 수 없습니다: %v", sm.Config.ServiceName, err)
-	}
-	defer s.Close()
-
-	status, err := s.Query()
+	state, err := sm.QueryState()
 	if err != nil {
-		return fmt.Errorf("서비스 상태를 확인할 수 없습니다: %v", err)
-	}
-
-	var state string
-	switch status.State {
-	case svc.Running:
-		state = "실행 중"
-	case svc.Stopped:
-		state = "중지됨"
-	case svc.StartPending:
-		state = "시작 중"
-	case svc.StopPending:
-		state = "중지 중"
-	case svc.PausePending:
-		state = "일시 중지 중"
-	case svc.Paused:
-		state = "일시 중지됨"
-	case svc.ContinuePending:
-		state = "계속 중"
-	default:
-		state = fmt.Sprintf("알 수 없음 (%d)", status.State)
+		return err
 	}
 
 	fmt.Printf("서비스 '%s'의 상태: %s\n", sm.Config.ServiceName, state)
 	return nil
 }
 
-// Run은 서비스를 실행합니다
-func (sm *ServiceManager) Run(handler svc.Handler) error {
-	var err error
-
-	if sm.IsDebug {
-		sm.Elog = debug.New(sm.Config.ServiceName)
-	} else {
-		sm.Elog, err = eventlog.Open(sm.Config.ServiceName)
-		if err != nil {
-			return fmt.Errorf("이벤트 로그를 열 수 없습니다: %v", err)
-		}
+// QueryState는 서비스의 현재 상태를 반환합니다
+func (sm *ServiceManager) QueryState() (ServiceState, error) {
+	m, err := sm.connect()
+	if err != nil {
+		return 0, err
 	}
-	defer sm.Elog.Close()
-
-	sm.Elog.Info(1, fmt.Sprintf("서비스 '%s'를 시작합니다.", sm.Config.ServiceName))
+	defer m.Disconnect()
 
-	run := svc.Run
-	if sm.IsDebug {
-		run = debug.Run
+	s, err := sm.openService(m)
+	if err != nil {
+		return 0, err
 	}
+	defer s.Close()
 
-	err = run(sm.Config.ServiceName, handler)
+	status, err := s.Query()
 	if err != nil {
-		sm.Elog.Error(1, fmt.Sprintf("서비스 실행 실패: %v", err))
-		return err
+		return 0, fmt.Errorf("서비스 상태를 확인할 수 없습니다: %v", err)
 	}
-
-	sm.Elog.Info(1, fmt.Sprintf("서비스 '%s'가 종료되었습니다.", sm.Config.ServiceName))
-	return nil
-}
-
-// IsWindowsService는 현재 프로세스가 Windows 서비스로 실행 중인지 확인합니다
-func IsWindowsService() (bool, error) {
-	return svc.IsWindowsService()
+	return status.State, nil
 }
@@ -0,0 +1,223 @@
+package winsvc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testServiceName = "TestService"
+
+// newTestManager는 FakeController에 연결하는 ServiceManager를 생성합니다
+func newTestManager(fake *FakeController) *ServiceManager {
+	sm := NewServiceManager(&ServiceConfig{
+		ServiceName:        testServiceName,
+		ServiceDescription: "테스트 서비스",
+		RestartOnFailure:   true,
+		RestartDelay:       5,
+		MaxRestartAttempts: 3,
+	})
+	sm.Connect = fake.Connect
+	sm.PollInterval = time.Millisecond
+	return sm
+}
+
+func TestInstall(t *testing.T) {
+	fake := NewFakeController()
+	sm := newTestManager(fake)
+
+	if err := sm.Install(); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	s := fake.Service(testServiceName)
+	if s == nil {
+		t.Fatal("서비스가 등록되지 않았습니다")
+	}
+	if s.Spec.StartType != StartAutomatic {
+		t.Errorf("StartType = %v, want StartAutomatic", s.Spec.StartType)
+	}
+	if len(s.RecoveryActions) != 3 || s.ResetPeriod != 60 {
+		t.Errorf("복구 동작 = %v (reset %d), want 3개 (reset 60)", s.RecoveryActions, s.ResetPeriod)
+	}
+	if !fake.EventSources[testServiceName] {
+		t.Error("이벤트 원본이 등록되지 않았습니다")
+	}
+
+	if err := sm.Install(); err == nil || !strings.Contains(err.Error(), "이미 존재") {
+		t.Errorf("두 번째 Install 오류 = %v, want 이미 존재", err)
+	}
+}
+
+func TestInstallErrors(t *testing.T) {
+	errInjected := errors.New("주입된 오류")
+	tests := []struct {
+		name   string
+		inject func(*FakeController)
+		want   string
+	}{
+		{"connect", func(f *FakeController) { f.ConnectErr = errInjected }, "서비스 관리자에 연결할 수 없습니다"},
+		{"open", func(f *FakeController) { f.OpenErr = errInjected }, "확인할 수 없습니다"},
+		{"create", func(f *FakeController) { f.CreateErr = errInjected }, "서비스를 생성할 수 없습니다"},
+		{"event source", func(f *FakeController) { f.EventSourceErr = errInjected }, "이벤트 로그 설치 실패"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeController()
+			tt.inject(fake)
+			sm := newTestManager(fake)
+
+			err := sm.Install()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Install 오류 = %v, want %q 포함", err, tt.want)
+			}
+			// 실패하면 만든 서비스와 이벤트 원본이 남지 않아야 함
+			if fake.Service(testServiceName) != nil {
+				t.Error("실패 후 서비스가 남아 있습니다")
+			}
+			if len(fake.EventSources) != 0 {
+				t.Errorf("실패 후 이벤트 원본이 남아 있습니다: %v", fake.EventSources)
+			}
+		})
+	}
+}
+
+func TestInstallRecoveryErrorIgnored(t *testing.T) {
+	fake := NewFakeController()
+	fake.RecoveryErr = errors.New("지원하지 않음")
+	sm := newTestManager(fake)
+
+	if err := sm.Install(); err != nil {
+		t.Fatalf("재시작 정책 실패는 무시해야 합니다: %v", err)
+	}
+	if fake.Service(testServiceName) == nil || !fake.EventSources[testServiceName] {
+		t.Error("서비스와 이벤트 원본이 등록되지 않았습니다")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	for _, state := range []ServiceState{StateRunning, StateStopped} {
+		t.Run(state.String(), func(t *testing.T) {
+			fake := NewFakeController()
+			sm := newTestManager(fake)
+			if err := sm.Install(); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			fake.Service(testServiceName).State = state
+
+			// 중지된 서비스의 중지 요청 오류는 무시하고 제거
+			if err := sm.Remove(); err != nil {
+				t.Fatalf("Remove: %v", err)
+			}
+			if fake.Service(testServiceName) != nil {
+				t.Error("서비스가 삭제되지 않았습니다")
+			}
+			if fake.EventSources[testServiceName] {
+				t.Error("이벤트 원본이 제거되지 않았습니다")
+			}
+		})
+	}
+}
+
+func TestRemoveMissing(t *testing.T) {
+	fake := NewFakeController()
+	sm := newTestManager(fake)
+
+	err := sm.Remove()
+	if !errors.Is(err, ErrServiceNotExist) {
+		t.Fatalf("Remove 오류 = %v, want ErrServiceNotExist", err)
+	}
+}
+
+func TestRemoveDeleteError(t *testing.T) {
+	fake := NewFakeController()
+	sm := newTestManager(fake)
+	if err := sm.Install(); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	fake.DeleteErr = errors.New("액세스 거부")
+
+	if err := sm.Remove(); err == nil {
+		t.Fatal("삭제 실패 시 오류를 반환해야 합니다")
+	}
+	// 서비스가 남아 있으면 이벤트 원본도 유지
+	if !fake.EventSources[testServiceName] {
+		t.Error("서비스 삭제에 실패했는데 이벤트 원본이 제거되었습니다")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	fake := NewFakeController()
+	sm := newTestManager(fake)
+	if err := sm.Install(); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+
+	if err := sm.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if state, err := sm.QueryState(); err != nil || state != StateRunning {
+		t.Fatalf("QueryState = %v, %v, want 실행 중", state, err)
+	}
+	if err := sm.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if state, err := sm.QueryState(); err != nil || state != StateStopped {
+		t.Fatalf("QueryState = %v, %v, want 중지됨", state, err)
+	}
+	if err := sm.Stop(); err == nil {
+		t.Error("중지된 서비스의 Stop은 오류를 반환해야 합니다")
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	fake := NewFakeController()
+	fake.StopHangs = true
+	sm := newTestManager(fake)
+	sm.StopTimeout = 20 * time.Millisecond
+	if err := sm.Install(); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if err := sm.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	start := time.Now()
+	err := sm.Stop()
+	if err == nil || !strings.Contains(err.Error(), "중지되지 않았습니다") {
+		t.Fatalf("Stop 오류 = %v, want 제한 시간 초과", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Stop이 %v 동안 기다렸습니다", elapsed)
+	}
+}
+
+func TestRecoveryActions(t *testing.T) {
+	tests := []struct {
+		max  int
+		want int
+	}{
+		{0, 3},
+		{1, 1},
+		{5, 5},
+	}
+	for _, tt := range tests {
+		sm := newTestManager(NewFakeController())
+		sm.Config.MaxRestartAttempts = tt.max
+		want := tt.want
+		if fixedRecoveryActions > 0 {
+			// Windows는 max_restart_attempts와 관계없이 고정 개수
+			want = fixedRecoveryActions
+		}
+
+		actions := sm.recoveryActions()
+		if len(actions) != want {
+			t.Fatalf("max %d: 복구 동작 %d개, want %d개", tt.max, len(actions), want)
+		}
+		for i, a := range actions {
+			if d := time.Duration(5*(i+1)) * time.Second; a.Type != RecoveryRestart || a.Delay != d {
+				t.Errorf("max %d: actions[%d] = %+v, want 재시작 %v", tt.max, i, a, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build windows
+// +build windows
+
+package winsvc
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/debug"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// Run은 서비스를 실행합니다
+func (sm *ServiceManager) Run(handler svc.Handler) error {
+	var err error
+
+	if sm.IsDebug {
+		sm.Elog = debug.New(sm.Config.ServiceName)
+	} else {
+		sm.Elog, err = eventlog.Open(sm.Config.ServiceName)
+		if err != nil {
+			return fmt.Errorf("이벤트 로그를 열 수 없습니다: %v", err)
+		}
+	}
+	defer sm.Elog.Close()
+
+	sm.Elog.Info(1, fmt.Sprintf("서비스 '%s'를 시작합니다.", sm.Config.ServiceName))
+
+	run := svc.Run
+	if sm.IsDebug {
+		run = debug.Run
+	}
+
+	err = run(sm.Config.ServiceName, handler)
+	if err != nil {
+		sm.Elog.Error(1, fmt.Sprintf("서비스 실행 실패: %v", err))
+		return err
+	}
+
+	sm.Elog.Info(1, fmt.Sprintf("서비스 '%s'가 종료되었습니다.", sm.Config.ServiceName))
+	return nil
+}
+
+// IsWindowsService는 현재 프로세스가 Windows 서비스로 실행 중인지 확인합니다
+func IsWindowsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// StartupReporter는 Startup의 진행 상황을 서비스 상태 채널(svc.Handler.Execute의 changes)로 보내는 함수를 반환합니다
+func StartupReporter(changes chan<- svc.Status) func(StartupStatus) {
+	return func(s StartupStatus) {
+		changes <- svc.Status{
+			State:      svc.State(s.State),
+			CheckPoint: s.CheckPoint,
+			WaitHint:   uint32(s.WaitHint / time.Millisecond),
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package winsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 시작 진행 보고 기본값
+const (
+	DefaultStartupWaitHint = 10 * time.Second // 단계에 WaitHint가 없을 때
+	DefaultStopWaitHint    = 30 * time.Second // 시작 실패 후 정리 작업에 필요한 시간
+)
+
+// StartupStatus는 시작 중인 서비스의 진행 상황입니다 (Windows svc.Status의 State, CheckPoint, WaitHint).
+// SCM은 WaitHint 안에 CheckPoint가 올라가지 않으면 서비스가 응답하지 않는 것으로 보고 시작 실패로 처리합니다.
+type StartupStatus struct {
+	State      ServiceState
+	CheckPoint uint32
+	WaitHint   time.Duration
+}
+
+// StartupPhase는 서비스 시작 단계 하나입니다
+type StartupPhase struct {
+	Name     string
+	WaitHint time.Duration // 다음 진행 보고까지 기다릴 시간 (0이면 DefaultStartupWaitHint)
+	// Timeout이 지나도 끝나지 않으면 ctx를 취소하고 기다리지 않고 바로 실패로 처리합니다 (0이면 제한 없음).
+	// 그 뒤에 Run이 끝나도 결과는 무시하며, ctx를 따르지 않는 Run은 Startup.Wait로 끝나기를 기다려야 합니다.
+	Timeout  time.Duration
+	ExitCode uint32 // 실패하면 반환하는 서비스 전용 종료 코드 (ServiceSpecificExitCode)
+	Run      func(ctx context.Context) error
+}
+
+// StartupError는 시작 단계의 실패입니다
+type StartupError struct {
+	Phase    string
+	ExitCode uint32
+	Err      error
+}
+
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("시작 단계 %s 실패 (종료 코드 %d): %v", e.Phase, e.ExitCode, e.Err)
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// Startup은 서비스 시작 단계를 차례로 실행하며 단계마다 StartPending 진행 상황을 보고합니다.
+// 단계가 WaitHint보다 오래 걸리면 WaitHint의 절반마다 CheckPoint를 올려 보고하므로
+// 큰 감시 경로를 준비하는 동안에도 SCM의 시작 제한 시간에 걸리지 않습니다.
+//
+// Report는 Run을 호출한 고루틴에서만 차례로 호출되며, Windows에서는 StartupReporter로 서비스 상태 채널에 보냅니다.
+// 테스트에서는 채널에 보내는 함수를 지정하여 보고 순서를 확인할 수 있습니다.
+type Startup struct {
+	Phases       []StartupPhase
+	Report       func(StartupStatus) // nil이면 보고하지 않음 (systemd, 콘솔)
+	Logger       *Logger             // nil이면 단계별 소요 시간을 기록하지 않음
+	StopWaitHint time.Duration       // 실패 후 StopPending 보고의 WaitHint (0이면 DefaultStopWaitHint)
+
+	checkPoint uint32
+	abandoned  <-chan struct{} // 제한 시간을 넘겨 실패한 단계의 Run이 끝나면 닫힘
+}
+
+// Run은 단계를 차례로 실행합니다. 단계가 실패하면 StopPending을 보고하고 *StartupError를 반환하며,
+// 호출한 쪽은 정리 작업을 마친 뒤 StartupError.ExitCode로 종료해야 합니다.
+// 모든 단계가 끝나면 nil을 반환하며 Running 보고는 호출한 쪽에서 합니다 (받을 제어 요청 지정).
+func (s *Startup) Run(ctx context.Context) error {
+	for _, phase := range s.Phases {
+		if err := s.runPhase(ctx, phase); err != nil {
+			s.report(StateStopPending, s.stopWaitHint())
+			return err
+		}
+	}
+	return nil
+}
+
+// Wait는 제한 시간을 넘겨 실패한 단계가 아직 실행 중이면 끝날 때까지 최대 timeout 동안 기다립니다.
+// 실행 중인 단계가 없거나 기다리는 동안 끝나면 true를 반환합니다 (timeout이 0 이하이면 StopWaitHint).
+// 단계가 쓰는 자원을 정리하기 전에 Run을 호출한 고루틴에서 호출해야 하며, false이면 단계가 아직 자원을
+// 쓰고 있을 수 있으므로 정리하지 않고 종료해야 합니다.
+func (s *Startup) Wait(timeout time.Duration) bool {
+	if s.abandoned == nil {
+		return true
+	}
+	if timeout <= 0 {
+		timeout = s.stopWaitHint()
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-s.abandoned:
+		s.abandoned = nil
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// stopWaitHint는 실패 후 정리 작업에 필요한 시간입니다
+func (s *Startup) stopWaitHint() time.Duration {
+	if s.StopWaitHint <= 0 {
+		return DefaultStopWaitHint
+	}
+	return s.StopWaitHint
+}
+
+// runPhase는 단계 하나를 별도 고루틴에서 실행하고 끝날 때까지 진행 상황을 보고합니다
+func (s *Startup) runPhase(ctx context.Context, phase StartupPhase) error {
+	hint := phase.WaitHint
+	if hint <= 0 {
+		hint = DefaultStartupWaitHint
+	}
+	s.report(StateStartPending, hint)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var timeout <-chan time.Time
+	if phase.Timeout > 0 {
+		timer := time.NewTimer(phase.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("패닉: %v", r)
+			}
+		}()
+		done <- phase.Run(ctx)
+	}()
+
+	ticker := time.NewTicker(hint / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return &StartupError{Phase: phase.Name, ExitCode: phase.ExitCode, Err: err}
+			}
+			if s.Logger != nil {
+				s.Logger.LogFields(LogInfo, "시작 단계 완료",
+					F("phase", phase.Name), F("duration_ms", time.Since(start).Milliseconds()))
+			}
+			return nil
+
+		case <-ticker.C:
+			s.report(StateStartPending, hint)
+
+		case <-timeout:
+			// 고루틴은 ctx 취소(defer)로 멈추기를 기대하며, done은 버퍼가 있어 늦게 끝나도 막히지 않음.
+			// ctx를 따르지 않는 단계가 정리 작업과 겹치지 않도록 Wait에서 finished를 기다림
+			s.abandoned = finished
+			if s.Logger != nil {
+				s.Logger.LogFields(LogWarning, "시작 단계가 제한 시간을 넘어 취소합니다",
+					F("phase", phase.Name), F("timeout_ms", phase.Timeout.Milliseconds()))
+			}
+			return &StartupError{Phase: phase.Name, ExitCode: phase.ExitCode,
+				Err: fmt.Errorf("%v 안에 끝나지 않았습니다", phase.Timeout)}
+		}
+	}
+}
+
+// report는 CheckPoint를 올려 진행 상황을 보고합니다
+func (s *Startup) report(state ServiceState, hint time.Duration) {
+	s.checkPoint++
+	if s.Report != nil {
+		s.Report(StartupStatus{State: state, CheckPoint: s.checkPoint, WaitHint: hint})
+	}
+}
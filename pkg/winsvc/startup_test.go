@@ -0,0 +1,223 @@
+package winsvc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runStartup은 보고를 changes 채널로 받는 Startup을 실행하고 받은 보고와 결과를 반환합니다
+// (Windows 서비스의 svc.Status 채널과 같은 방식)
+func runStartup(t *testing.T, s *Startup) ([]StartupStatus, error) {
+	t.Helper()
+	changes := make(chan StartupStatus, 1000)
+	s.Report = func(status StartupStatus) { changes <- status }
+
+	err := s.Run(context.Background())
+	close(changes)
+	var statuses []StartupStatus
+	for status := range changes {
+		statuses = append(statuses, status)
+	}
+	return statuses, err
+}
+
+// checkCheckPoints는 CheckPoint가 보고마다 1씩 올라가는지 확인합니다
+func checkCheckPoints(t *testing.T, statuses []StartupStatus) {
+	t.Helper()
+	for i, status := range statuses {
+		if status.CheckPoint != uint32(i+1) {
+			t.Errorf("보고 %d의 CheckPoint = %d, want %d", i, status.CheckPoint, i+1)
+		}
+	}
+}
+
+func nop(ctx context.Context) error { return nil }
+
+func TestStartupCheckPoints(t *testing.T) {
+	var ran []string
+	phase := func(name string, d time.Duration) func(context.Context) error {
+		return func(ctx context.Context) error {
+			ran = append(ran, name)
+			time.Sleep(d)
+			return nil
+		}
+	}
+	statuses, err := runStartup(t, &Startup{Phases: []StartupPhase{
+		{Name: "fast", WaitHint: time.Second, Run: phase("fast", 0)},
+		{Name: "slow", WaitHint: 40 * time.Millisecond, Run: phase("slow", 150*time.Millisecond)},
+		{Name: "default", Run: phase("default", 0)},
+	}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.Join(ran, ",") != "fast,slow,default" {
+		t.Errorf("실행 순서 = %v", ran)
+	}
+
+	checkCheckPoints(t, statuses)
+	// fast 1번, slow는 시작과 WaitHint의 절반(20ms)마다, default 1번
+	if len(statuses) < 5 {
+		t.Fatalf("보고 %d번, want 진행 보고 포함 5번 이상: %+v", len(statuses), statuses)
+	}
+	for _, status := range statuses {
+		if status.State != StateStartPending {
+			t.Errorf("상태 = %v, want 시작 중", status.State)
+		}
+	}
+	if statuses[0].WaitHint != time.Second {
+		t.Errorf("fast WaitHint = %v, want 1s", statuses[0].WaitHint)
+	}
+	for _, status := range statuses[1 : len(statuses)-1] {
+		if status.WaitHint != 40*time.Millisecond {
+			t.Errorf("slow WaitHint = %v, want 40ms", status.WaitHint)
+		}
+	}
+	if last := statuses[len(statuses)-1]; last.WaitHint != DefaultStartupWaitHint {
+		t.Errorf("default WaitHint = %v, want %v", last.WaitHint, DefaultStartupWaitHint)
+	}
+}
+
+func TestStartupPhaseError(t *testing.T) {
+	errInjected := errors.New("데이터베이스를 열 수 없음")
+	thirdRan := false
+	statuses, err := runStartup(t, &Startup{
+		StopWaitHint: 7 * time.Second,
+		Phases: []StartupPhase{
+			{Name: "directories", ExitCode: 101, Run: nop},
+			{Name: "pipeline", ExitCode: 103, Run: func(ctx context.Context) error { return errInjected }},
+			{Name: "monitor", ExitCode: 104, Run: func(ctx context.Context) error { thirdRan = true; return nil }},
+		},
+	})
+
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) {
+		t.Fatalf("Run 오류 = %v, want *StartupError", err)
+	}
+	if startupErr.Phase != "pipeline" || startupErr.ExitCode != 103 || !errors.Is(err, errInjected) {
+		t.Errorf("StartupError = %+v, want pipeline, 103", startupErr)
+	}
+	if thirdRan {
+		t.Error("실패한 단계 다음 단계가 실행되었습니다")
+	}
+
+	checkCheckPoints(t, statuses)
+	last := statuses[len(statuses)-1]
+	if last.State != StateStopPending || last.WaitHint != 7*time.Second {
+		t.Errorf("마지막 보고 = %+v, want 중지 중, WaitHint 7s", last)
+	}
+}
+
+func TestStartupTimeout(t *testing.T) {
+	release := make(chan struct{})
+	canceled := make(chan bool, 1)
+	start := time.Now()
+	statuses, err := runStartup(t, &Startup{Phases: []StartupPhase{
+		{
+			Name:     "monitor",
+			WaitHint: time.Second,
+			Timeout:  30 * time.Millisecond,
+			ExitCode: 104,
+			// ctx 취소를 바로 따르지 않고 늦게 성공하는 단계
+			Run: func(ctx context.Context) error {
+				<-release
+				canceled <- ctx.Err() != nil
+				return nil
+			},
+		},
+	}})
+	elapsed := time.Since(start)
+	close(release)
+
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) {
+		t.Fatalf("Run 오류 = %v, want *StartupError", err)
+	}
+	if startupErr.Phase != "monitor" || startupErr.ExitCode != 104 || !strings.Contains(err.Error(), "30ms 안에 끝나지 않았습니다") {
+		t.Errorf("StartupError = %v", err)
+	}
+	// 단계가 끝나기를 기다리지 않고 바로 반환
+	if elapsed > time.Second {
+		t.Errorf("제한 시간 후 %v 동안 기다렸습니다", elapsed)
+	}
+	if last := statuses[len(statuses)-1]; last.State != StateStopPending || last.WaitHint != DefaultStopWaitHint {
+		t.Errorf("마지막 보고 = %+v, want 중지 중, WaitHint %v", last, DefaultStopWaitHint)
+	}
+
+	select {
+	case wasCanceled := <-canceled:
+		if !wasCanceled {
+			t.Error("제한 시간이 지나면 단계의 ctx를 취소해야 합니다")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("단계가 끝나지 않았습니다")
+	}
+}
+
+func TestStartupWait(t *testing.T) {
+	// ctx를 따르지 않고 제한 시간이 지난 뒤에도 자원을 쓰는 단계 (newAgent, Agent.Start)
+	var used []string
+	release := make(chan struct{})
+	s := &Startup{Phases: []StartupPhase{
+		{
+			Name:     "pipeline",
+			Timeout:  30 * time.Millisecond,
+			ExitCode: 103,
+			Run: func(ctx context.Context) error {
+				<-release
+				time.Sleep(50 * time.Millisecond)
+				used = append(used, "phase")
+				return nil
+			},
+		},
+	}}
+	if _, err := runStartup(t, s); err == nil {
+		t.Fatal("제한 시간이 지났는데 Run이 오류를 반환하지 않았습니다")
+	}
+
+	// 단계가 끝나지 않으면 timeout까지만 기다림
+	start := time.Now()
+	if s.Wait(50 * time.Millisecond) {
+		t.Error("단계가 실행 중인데 Wait가 true를 반환했습니다")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Wait 소요 시간 = %v, want 50ms", elapsed)
+	}
+
+	// 단계가 끝난 뒤에 정리 (겹치면 -race가 used의 경쟁 상태를 보고)
+	close(release)
+	if !s.Wait(5 * time.Second) {
+		t.Fatal("단계가 끝났는데 Wait가 false를 반환했습니다")
+	}
+	used = append(used, "cleanup")
+	if strings.Join(used, ",") != "phase,cleanup" {
+		t.Errorf("순서 = %v, want 단계가 끝난 뒤 정리", used)
+	}
+
+	// 기다릴 단계가 없으면 바로 반환
+	for _, s := range []*Startup{s, {}} {
+		if !s.Wait(time.Nanosecond) {
+			t.Error("실행 중인 단계가 없는데 Wait가 false를 반환했습니다")
+		}
+	}
+}
+
+func TestStartupPanic(t *testing.T) {
+	statuses, err := runStartup(t, &Startup{Phases: []StartupPhase{
+		{Name: "logger", ExitCode: 102, Run: func(ctx context.Context) error { panic("nil 로거") }},
+	}})
+
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) {
+		t.Fatalf("Run 오류 = %v, want *StartupError", err)
+	}
+	if startupErr.Phase != "logger" || startupErr.ExitCode != 102 || !strings.Contains(err.Error(), "패닉: nil 로거") {
+		t.Errorf("StartupError = %v", err)
+	}
+	checkCheckPoints(t, statuses)
+	if len(statuses) != 2 || statuses[1].State != StateStopPending {
+		t.Errorf("보고 = %+v, want 시작 중, 중지 중", statuses)
+	}
+}
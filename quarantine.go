@@ -0,0 +1,30 @@
+package main
+
+import (
+	"path/filepath"
+
+	"windows_service_module/pkg/quarantine"
+	"windows_service_module/pkg/winsvc"
+)
+
+// quarantineStore는 규칙이 격리를 요청한 파일을 보관하는 격리 디렉토리입니다 (quarantine.enabled가 false이면 nil)
+var quarantineStore *quarantine.Store
+
+// quarantineDir은 custom_data_path 아래의 격리 디렉토리 경로를 반환합니다
+func quarantineDir(cfg *ServiceConfig) string {
+	return filepath.Join(cfg.CustomDataPath, quarantine.DirName)
+}
+
+// initQuarantine은 격리가 설정되어 있으면 격리 디렉토리를 엽니다
+func initQuarantine(cfg *ServiceConfig) error {
+	if !cfg.Quarantine.Enabled {
+		return nil
+	}
+	store, err := quarantine.Open(quarantineDir(cfg))
+	if err != nil {
+		return err
+	}
+	quarantineStore = store
+	logger.Log(winsvc.LogInfo, "격리 디렉토리: %s", store.Dir())
+	return nil
+}
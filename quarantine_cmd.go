@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"windows_service_module/pkg/quarantine"
+)
+
+// runQuarantineCommand는 격리한 파일을 조회, 복원, 삭제합니다.
+// 서비스 관리자 없이 실행되며, 격리 디렉토리는 설정의 custom_data_path 아래이거나 --dir로 지정합니다.
+func runQuarantineCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "restore" && args[0] != "purge") {
+		fmt.Fprintln(os.Stderr, "사용법: quarantine list [--format table|json] | restore <ID> [--to 경로] | purge [--before 시각] [--dir 격리 디렉토리]")
+		return 1
+	}
+	sub := args[0]
+	args = args[1:]
+
+	// restore는 ID를 플래그 앞에 받음
+	var id string
+	if sub == "restore" && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("quarantine "+sub, flag.ContinueOnError)
+	dir := fs.String("dir", "", "격리 디렉토리 (기본값: 설정의 custom_data_path/quarantine)")
+	format := fs.String("format", "table", "list 출력 형식 (table, json)")
+	to := fs.String("to", "", "restore 대상 경로 (기본값: 원래 경로)")
+	before := fs.String("before", "", "purge: 이 시각 이전에 격리한 항목만 삭제 (예: 720h, 2025-01-02, 기본값: 모두)")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if sub == "restore" && id == "" {
+		if id = fs.Arg(0); id == "" {
+			fmt.Fprintln(os.Stderr, "사용법: quarantine restore <ID> [--to 경로]")
+			return 1
+		}
+	}
+
+	path := *dir
+	if path == "" {
+		cfg, err := loadOfflineConfig("--dir")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		path = quarantineDir(cfg)
+	}
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "격리 디렉토리를 열 수 없습니다: %v\n", err)
+		return 1
+	}
+	store, err := quarantine.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	switch sub {
+	case "list":
+		entries, err := store.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		switch *format {
+		case "json":
+			err = writeQuarantineJSON(os.Stdout, entries)
+		case "table":
+			err = writeQuarantineTable(os.Stdout, entries)
+		default:
+			fmt.Fprintf(os.Stderr, "알 수 없는 출력 형식: %s (table, json 중 하나)\n", *format)
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "출력 실패: %v\n", err)
+			return 1
+		}
+
+	case "restore":
+		entry, err := store.Restore(id, *to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		target := *to
+		if target == "" {
+			target = entry.OriginalPath
+		}
+		fmt.Printf("복원됨: %s -> %s\n", entry.ID, target)
+
+	case "purge":
+		cutoff, err := parseTimeArg(*before, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "--before: %v\n", err)
+			return 1
+		}
+		purged, err := store.Purge(cutoff)
+		for _, entry := range purged {
+			fmt.Printf("삭제됨: %s %s\n", entry.ID, entry.OriginalPath)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("격리 항목 %d개를 삭제했습니다\n", len(purged))
+	}
+	return 0
+}
+
+// writeQuarantineTable은 격리 항목을 표로 출력합니다
+func writeQuarantineTable(out io.Writer, entries []quarantine.Entry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(out, "격리된 파일이 없습니다")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\t격리 시각\t규칙\t크기\tSHA256\t원래 경로")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			e.ID, e.QuarantinedAt.Format("2006-01-02 15:04:05"), e.Rule, e.Size, shortHash(e.SHA256), e.OriginalPath)
+	}
+	return w.Flush()
+}
+
+// writeQuarantineJSON은 격리 항목을 JSON 배열로 출력합니다
+func writeQuarantineJSON(out io.Writer, entries []quarantine.Entry) error {
+	if entries == nil {
+		entries = []quarantine.Entry{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"windows_service_module/pkg/winsvc"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDelay는 설정 파일 변경 후 다시 로드하기까지 기다리는 시간입니다.
+// 편집기가 파일을 여러 번 나눠 쓰는 경우를 한 번의 로드로 합칩니다.
+const configReloadDelay = 500 * time.Millisecond
+
+// liveConfigFields는 서비스 재시작 없이 적용할 수 있는 설정 필드입니다
+var liveConfigFields = map[string]bool{
+	"monitoring_path":  true,
+	"file_filters":     true,
+	"log_level":        true,
+	"log_format":       true,
+	"retention":        true,
+	"event_coalescing": true,
+	"baseline":         true,
+}
+
+// configChange는 설정 필드 하나의 변경 내용입니다
+type configChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// String은 변경 내용을 "필드: 이전 -> 이후" 형식으로 반환합니다
+func (c configChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, jsonValue(c.Old), jsonValue(c.New))
+}
+
+// jsonValue는 로그 출력을 위해 값을 JSON 문자열로 변환합니다
+func jsonValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// diffConfig는 두 설정을 JSON 필드 단위로 비교합니다
+func diffConfig(oldCfg, newCfg *ServiceConfig) []configChange {
+	var changes []configChange
+
+	ov := reflect.ValueOf(*oldCfg)
+	nv := reflect.ValueOf(*newCfg)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		oldValue := ov.Field(i).Interface()
+		newValue := nv.Field(i).Interface()
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, configChange{Field: name, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// reloadConfig는 설정 파일을 다시 읽어 변경 사항을 실행 중인 파이프라인에 적용합니다
+func reloadConfig() {
+	newConfig, err := loadConfig(configPath, true)
+	if err != nil {
+		logger.Log(winsvc.LogError, "설정 다시 로드 실패 (기존 설정 유지): %v", err)
+		return
+	}
+	if err := resolvePaths(newConfig); err != nil {
+		logger.Log(winsvc.LogError, "설정 다시 로드 실패 (기존 설정 유지): %v", err)
+		return
+	}
+
+	changes := diffConfig(config, newConfig)
+	if len(changes) == 0 {
+		logger.Log(winsvc.LogInfo, "설정 파일을 다시 읽었습니다. 변경 사항이 없습니다.")
+		return
+	}
+
+	// 실시간 적용 가능한 필드만 반영하고 나머지는 재시작 시 적용
+	applied := *config
+	applied.MonitoringPath = newConfig.MonitoringPath
+	applied.FileFilters = newConfig.FileFilters
+	applied.LogLevel = newConfig.LogLevel
+	applied.LogFormat = newConfig.LogFormat
+	applied.Retention = newConfig.Retention
+	applied.EventCoalescing = newConfig.EventCoalescing
+	applied.Baseline = newConfig.Baseline
+
+	agentConfig, err := newAgentConfig(&applied)
+	if err != nil {
+		logger.Log(winsvc.LogError, "설정 다시 로드 실패 (기존 설정 유지): %v", err)
+		return
+	}
+	if err := agentInstance.Reconfigure(agentConfig); err != nil {
+		logger.Log(winsvc.LogError, "설정 적용 실패: %v", err)
+		return
+	}
+
+	applyLogSettings(&applied)
+
+	for _, c := range changes {
+		if liveConfigFields[c.Field] {
+			logger.Log(winsvc.LogInfo, "설정 변경 적용: %s", c)
+		} else {
+			logger.Log(winsvc.LogWarning, "설정 변경 (서비스를 다시 시작해야 적용됩니다): %s", c)
+		}
+	}
+	setConfig(&applied)
+}
+
+// watchConfigFile은 ctx가 취소될 때까지 설정 파일을 감시하고 변경되면 requests로 알립니다
+func watchConfigFile(ctx context.Context, path string, requests chan<- struct{}) error {
+	return watchFiles(ctx, []string{path}, requests)
+}
+
+// watchFiles는 ctx가 취소될 때까지 파일들을 감시하고, 하나라도 변경되면 configReloadDelay 후 requests로 알립니다
+func watchFiles(ctx context.Context, paths []string, requests chan<- struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("파일 감시자 생성 실패: %v", err)
+	}
+
+	// 편집기가 파일을 교체하는 경우에도 감지하도록 디렉토리를 감시
+	watched := make(map[string]bool)
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		watched[path] = true
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("파일 감시 실패 %s: %v", path, err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(configReloadDelay)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !watched[filepath.Clean(event.Name)] {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					timer.Reset(configReloadDelay)
+				}
+
+			case <-timer.C:
+				// 처리 대기 중인 요청이 있으면 합침
+				select {
+				case requests <- struct{}{}:
+				default:
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Log(winsvc.LogWarning, "파일 감시 오류: %v", err)
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
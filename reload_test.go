@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/eventstore"
+	"windows_service_module/pkg/winsvc"
+)
+
+// reloadTestConfig는 감시 경로, 확장자, 로그 수준으로 설정 파일 내용을 만듭니다
+func reloadTestConfig(dataDir, monitoringPath, extension, logLevel string) string {
+	return fmt.Sprintf(`{
+		"service_description": "reload test",
+		"monitoring_path": [%s],
+		"database_path": %s,
+		"log_path": %s,
+		"custom_data_path": %s,
+		"log_level": %q,
+		"file_filters": {"extensions": [%q]}
+	}`, quoteJSON(monitoringPath), quoteJSON(filepath.Join(dataDir, "events.db")),
+		quoteJSON(filepath.Join(dataDir, "logs")), quoteJSON(dataDir), logLevel, extension)
+}
+
+// startReloadTest는 설정 파일로 서비스 전역 상태를 초기화하고 파이프라인을 실행합니다.
+// 테스트가 끝나면 파이프라인을 중지하고 전역 상태를 되돌립니다.
+func startReloadTest(t *testing.T, content string) {
+	t.Helper()
+	path := writeConfig(t, content)
+	cfg, err := loadConfig(path, true)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if err := resolvePaths(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath, config = path, cfg
+	logger = winsvc.NewLogger(cfg.LogPath, false)
+	applyLogSettings(cfg)
+	if eventStore, err = eventstore.Open(cfg.DatabasePath); err != nil {
+		t.Fatal(err)
+	}
+	eventWriter = eventstore.NewWriter(eventStore, 100)
+	eventWriter.Start()
+
+	agentConfig, err := newAgentConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentInstance = agent.New(agentConfig, agent.NewMonitorSource(), logger)
+	if err := agentInstance.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- agentInstance.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		eventWriter.Close()
+		eventStore.Close()
+		configPath, config, logger = "", nil, nil
+		eventStore, eventWriter, agentInstance = nil, nil, nil
+	})
+}
+
+// waitProcessed는 처리한 이벤트 수가 want가 될 때까지 기다립니다
+func waitProcessed(t *testing.T, want uint64) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if agentInstance.Status().EventsProcessed == want {
+			return
+		}
+	}
+	t.Fatalf("처리한 이벤트 수 = %d, want %d", agentInstance.Status().EventsProcessed, want)
+}
+
+func TestReloadConfig(t *testing.T) {
+	dataDir, oldDir, newDir := t.TempDir(), t.TempDir(), t.TempDir()
+	startReloadTest(t, reloadTestConfig(dataDir, oldDir, ".exe", "info"))
+	oldConfig := config
+
+	// 감시 경로, 확장자, 로그 수준과 재시작해야 적용되는 service_name 변경
+	content := reloadTestConfig(dataDir, newDir, ".dll", "debug")
+	content = content[:1] + `"service_name": "hj-reload",` + content[1:]
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloadConfig()
+
+	if config == oldConfig {
+		t.Fatal("설정이 교체되지 않았습니다")
+	}
+	if !reflect.DeepEqual(config.MonitoringPath, []string{newDir}) {
+		t.Errorf("monitoring_path = %v, want [%s]", config.MonitoringPath, newDir)
+	}
+	if !reflect.DeepEqual(config.FileFilters.Extensions, []string{".dll"}) {
+		t.Errorf("file_filters.extensions = %v, want [.dll]", config.FileFilters.Extensions)
+	}
+	if config.ServiceName != oldConfig.ServiceName {
+		t.Errorf("service_name = %q, 재시작 전에는 %q를 유지해야 합니다", config.ServiceName, oldConfig.ServiceName)
+	}
+	if logger.MinLevel != winsvc.LogDebug {
+		t.Errorf("로그 수준 = %v, want DEBUG", logger.MinLevel)
+	}
+	status := agentInstance.Status()
+	if !reflect.DeepEqual(status.WatchedPaths, []string{newDir}) || !reflect.DeepEqual(status.Extensions, []string{".dll"}) {
+		t.Errorf("파이프라인 설정 = %v %v, want [%s] [.dll]", status.WatchedPaths, status.Extensions, newDir)
+	}
+
+	// 감시를 다시 시작하지 않고 새 경로와 확장자로 이벤트를 받음
+	for _, path := range []string{
+		filepath.Join(oldDir, "old.dll"),
+		filepath.Join(newDir, "skip.exe"),
+		filepath.Join(newDir, "new.dll"),
+	} {
+		// 빈 파일을 만들어 파일마다 CREATE 이벤트만 발생
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitProcessed(t, 1)
+	time.Sleep(100 * time.Millisecond)
+	if status := agentInstance.Status(); status.EventsProcessed != 1 {
+		t.Errorf("처리한 이벤트 수 = %d, want 1 (새 경로의 .dll만)", status.EventsProcessed)
+	}
+	if status := agentInstance.Status(); status.State != agent.StateRunning {
+		t.Errorf("파이프라인 상태 = %s (%s), want running", status.State, status.LastError)
+	}
+}
+
+func TestReloadConfigRejectsInvalid(t *testing.T) {
+	dataDir, dir := t.TempDir(), t.TempDir()
+	startReloadTest(t, reloadTestConfig(dataDir, dir, ".exe", "info"))
+	oldConfig := config
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"JSON 오류", `{"monitoring_path": [`},
+		{"로그 수준", reloadTestConfig(dataDir, dir, ".exe", "verbose")},
+		{"확장자", reloadTestConfig(dataDir, dir, ".e*e", "debug")},
+		{"없는 감시 경로", reloadTestConfig(dataDir, filepath.Join(dir, "missing"), ".dll", "debug")},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		reloadConfig()
+
+		if config != oldConfig {
+			t.Errorf("%s: 유효하지 않은 설정이 적용되었습니다", tt.name)
+		}
+		if logger.MinLevel != winsvc.LogInfo {
+			t.Errorf("%s: 로그 수준 = %v, want INFO", tt.name, logger.MinLevel)
+		}
+		status := agentInstance.Status()
+		if !reflect.DeepEqual(status.WatchedPaths, []string{dir}) || !reflect.DeepEqual(status.Extensions, []string{".exe"}) {
+			t.Errorf("%s: 파이프라인 설정 = %v %v, want 기존 설정", tt.name, status.WatchedPaths, status.Extensions)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"windows_service_module/pkg/rules"
+	"windows_service_module/pkg/winsvc"
+)
+
+// rulesEngine은 파일 이벤트에 적용하는 규칙입니다 (설정 파일과 같은 디렉토리의 규칙 파일)
+var rulesEngine *rules.Engine
+
+// rulesFileNames는 규칙 파일 이름 후보입니다. 먼저 있는 파일을 사용하며, 없으면 첫 번째 이름을 감시합니다.
+var rulesFileNames = []string{"rules.yaml", "rules.yml", "rules.json"}
+
+// rulesFilePath는 설정 파일과 같은 디렉토리의 규칙 파일 경로를 반환합니다
+func rulesFilePath(configPath string) string {
+	dir := filepath.Dir(configPath)
+	for _, name := range rulesFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, rulesFileNames[0])
+}
+
+// initRules는 규칙 파일을 읽습니다. 규칙 파일은 변경될 때마다 다시 읽습니다.
+func initRules() {
+	rulesEngine = rules.NewEngine(rulesFilePath(configPath))
+	reloadRules()
+}
+
+// reloadRules는 규칙 파일을 다시 읽습니다. 읽지 못하면 기존 규칙을 계속 사용합니다.
+func reloadRules() {
+	n, err := rulesEngine.Load()
+	if err != nil {
+		logger.Log(winsvc.LogError, "규칙을 로드할 수 없습니다 (기존 규칙 유지): %v", err)
+		return
+	}
+	logger.LogFields(winsvc.LogInfo, "규칙을 로드했습니다", winsvc.F("path", rulesEngine.Path()), winsvc.F("rules", n))
+
+	// sink 동작에 설정에 없는 싱크 이름이 있으면 해당 동작은 무시됨
+	configured := make(map[string]bool)
+	if sinkDispatcher != nil {
+		for _, name := range sinkDispatcher.Names() {
+			configured[name] = true
+		}
+	}
+	for _, name := range rulesEngine.Sinks() {
+		if !configured[name] {
+			logger.Log(winsvc.LogWarning, "규칙의 sink 동작에 설정되지 않은 싱크가 있습니다: %s", name)
+		}
+	}
+}
+
+// validateRulesFile은 설정 파일과 같은 디렉토리의 규칙 파일을 검증하고 경로를 반환합니다 (파일이 없으면 빈 문자열)
+func validateRulesFile(configPath string) (string, error) {
+	path := rulesFilePath(configPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return path, err
+	}
+	file, err := rules.Parse(filepath.Base(path), data)
+	if err != nil {
+		return path, err
+	}
+	return path, rules.Check(file.Rules)
+}
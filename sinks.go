@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/sink"
+	"windows_service_module/pkg/winsvc"
+)
+
+// sinkDispatcher는 처리한 이벤트를 설정된 외부 싱크(webhook, syslog, file)로 보냅니다 (싱크가 없으면 nil)
+var sinkDispatcher *sink.Dispatcher
+
+// startSinks는 설정의 싱크를 생성하고 전송을 시작합니다. 하나라도 생성하지 못하면 오류를 반환합니다.
+func startSinks(cfg *ServiceConfig) (*sink.Dispatcher, error) {
+	if len(cfg.Sinks) == 0 {
+		return nil, nil
+	}
+
+	var outputs []sink.Output
+	for i, c := range cfg.Sinks {
+		c.Name = c.DisplayName(i)
+		s, err := sink.New(c, cfg.ServiceName)
+		if err != nil {
+			for _, created := range outputs {
+				created.Sink.Close()
+			}
+			return nil, fmt.Errorf("이벤트 싱크 %s 초기화 실패: %v", c.Name, err)
+		}
+		outputs = append(outputs, sink.Output{Sink: s, QueueSize: c.QueueSize, RulesOnly: c.RulesOnly})
+		logger.Log(winsvc.LogInfo, "이벤트 싱크 추가: %s (%s)", c.Name, c.Type)
+	}
+
+	d := sink.NewDispatcher(outputs)
+	d.OnError = func(name string, err error) {
+		logger.Log(winsvc.LogWarning, "이벤트 싱크 %s 전송 실패: %v", name, err)
+	}
+	d.Observe = func(name string, err error) {
+		switch {
+		case err == nil:
+			serviceMetrics.ObserveSink(name, "sent")
+		case errors.Is(err, sink.ErrQueueFull):
+			serviceMetrics.ObserveSink(name, "dropped")
+		default:
+			serviceMetrics.ObserveSink(name, "error")
+		}
+	}
+	d.Start()
+	return d, nil
+}
+
+// sinkRecorder는 처리한 이벤트를 싱크로 보냅니다 (agent.EventRecorder 구현)
+type sinkRecorder struct {
+	dispatcher *sink.Dispatcher
+	service    string
+	host       string
+}
+
+func newSinkRecorder(dispatcher *sink.Dispatcher, service string) sinkRecorder {
+	host, _ := os.Hostname()
+	return sinkRecorder{dispatcher: dispatcher, service: service, host: host}
+}
+
+func (r sinkRecorder) Record(event agent.ProcessedEvent) {
+	r.dispatcher.Send(newSinkEvent(event, r.service, r.host), event.Sinks...)
+}
+
+// newSinkEvent는 처리한 이벤트를 싱크 이벤트로 변환합니다.
+// 경고가 있으면 가장 높은 수준의 경고를 이벤트의 수준과 메시지로 사용합니다.
+func newSinkEvent(event agent.ProcessedEvent, service, host string) sink.Event {
+	e := sink.Event{
+		Timestamp:     event.Timestamp,
+		LastTimestamp: event.LastTimestamp,
+		Count:         event.Count,
+		Host:          host,
+		Service:       service,
+		Level:         winsvc.LogInfo.String(),
+		Message:       "파일 이벤트 발생",
+		Path:          event.Path,
+		Operation:     event.Operation,
+		FileType:      event.FileType,
+		SHA256:        event.SHA256,
+		HashError:     event.HashError,
+		Rules:         event.Rules,
+		Tags:          event.Tags,
+
+		OfflineDetected: event.OfflineDetected,
+	}
+
+	level := winsvc.LogInfo
+	for _, a := range event.Alerts {
+		e.Alerts = append(e.Alerts, sink.Alert{Level: a.Level.String(), Source: a.Source, Label: a.Label, Message: a.Message})
+		if a.Level > level {
+			level = a.Level
+			e.Level, e.Message = a.Level.String(), a.Message
+		}
+	}
+	return e
+}
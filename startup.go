@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"windows_service_module/pkg/winsvc"
+)
+
+// 시작 단계별 서비스 전용 종료 코드.
+// 시작에 실패하면 서비스가 이 코드로 종료되므로 sc query의 SERVICE_EXIT_CODE나 systemd의 status=로 실패한 단계를 알 수 있습니다.
+const (
+	exitCodeDirectories uint32 = 101 // 로그, 데이터베이스, 데이터 디렉토리 생성
+	exitCodeLogger      uint32 = 102 // 로그 파일 초기화
+	exitCodePipeline    uint32 = 103 // 데이터베이스, 싱크, 규칙 등 이벤트 파이프라인 초기화
+	exitCodeMonitor     uint32 = 104 // 감시 경로 등록과 모니터링 시작
+)
+
+// newStartup은 서비스 시작 단계를 만듭니다. report는 단계마다 진행 상황을 받습니다 (nil이면 보고하지 않음).
+// 파이프라인 초기화에 실패해도 closeEventPipeline으로 정리할 수 있도록 호출한 쪽에서 먼저 defer해야 하며,
+// 정리하기 전에 Wait로 제한 시간을 넘긴 단계가 끝나기를 기다려야 합니다.
+func newStartup(report func(winsvc.StartupStatus)) *winsvc.Startup {
+	return &winsvc.Startup{
+		Report: report,
+		Logger: logger,
+		Phases: []winsvc.StartupPhase{
+			{
+				Name:     "directories",
+				WaitHint: 5 * time.Second,
+				Timeout:  30 * time.Second,
+				ExitCode: exitCodeDirectories,
+				Run: func(ctx context.Context) error {
+					if err := initializeDirectories(); err != nil {
+						return fmt.Errorf("디렉토리 초기화 실패: %v", err)
+					}
+					return nil
+				},
+			},
+			{
+				Name:     "logger",
+				WaitHint: 5 * time.Second,
+				Timeout:  30 * time.Second,
+				ExitCode: exitCodeLogger,
+				Run: func(ctx context.Context) error {
+					logger.SetLogPath(config.LogPath)
+					if err := logger.InitializeFileLogger(); err != nil {
+						return fmt.Errorf("로그 초기화 실패: %v", err)
+					}
+					startAsyncLogging()
+					return nil
+				},
+			},
+			{
+				// 스키마 마이그레이션은 데이터베이스가 크면 오래 걸릴 수 있음
+				Name:     "pipeline",
+				WaitHint: 30 * time.Second,
+				Timeout:  10 * time.Minute,
+				ExitCode: exitCodePipeline,
+				Run: func(ctx context.Context) error {
+					var err error
+					agentInstance, err = newAgent()
+					return err
+				},
+			},
+			{
+				// 큰 감시 경로(C:\)는 등록에 오래 걸릴 수 있으며, 그동안 WaitHint의 절반마다 진행 상황을 보고
+				Name:     "monitor",
+				WaitHint: 30 * time.Second,
+				Timeout:  10 * time.Minute,
+				ExitCode: exitCodeMonitor,
+				Run: func(ctx context.Context) error {
+					return agentInstance.Start()
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// serviceStateFileName은 실행 간에 유지되는 서비스 상태 파일 이름입니다 (custom_data_path 아래)
+const serviceStateFileName = "service_state.json"
+
+// serviceState는 서비스 실행 간에 유지되는 상태입니다
+type serviceState struct {
+	StartCount uint64    `json:"start_count"` // 서비스가 시작된 횟수
+	LastStart  time.Time `json:"last_start"`  // 마지막 시작 시각
+	LastVacuum time.Time `json:"last_vacuum"` // 마지막 데이터베이스 VACUUM 시각 (주기 기준)
+	// 이 시각까지의 파일 변경은 처리함 (종료 시와 주기적으로 기록, 다음 시작 시 보완 검사 기준)
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// serviceStateMu는 서비스 루프와 정리 작업 고루틴이 상태 파일을 동시에 읽고 고쳐 쓰지 않도록 보호합니다
+var serviceStateMu sync.Mutex
+
+// serviceStatePath는 현재 설정의 상태 파일 경로를 반환합니다
+func serviceStatePath() string {
+	return filepath.Join(currentConfig().CustomDataPath, serviceStateFileName)
+}
+
+// loadServiceState는 상태 파일을 읽습니다. 파일이 없으면 빈 상태를 반환합니다.
+func loadServiceState(path string) (serviceState, error) {
+	var state serviceState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("상태 파일 읽기 실패: %v", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("상태 파일 형식 오류: %v", err)
+	}
+	return state, nil
+}
+
+// saveServiceState는 상태 파일을 임시 파일에 쓴 뒤 교체하여 중간에 중단되어도 손상되지 않게 저장합니다
+func saveServiceState(path string, state serviceState) error {
+	data, err := json.MarshalIndent(state, "", "    ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("상태 파일 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// updateServiceState는 상태 파일을 읽어 update로 고친 뒤 저장하고, 저장한 상태를 반환합니다
+func updateServiceState(path string, update func(*serviceState)) (serviceState, error) {
+	serviceStateMu.Lock()
+	defer serviceStateMu.Unlock()
+
+	state, err := loadServiceState(path)
+	if err != nil {
+		return state, err
+	}
+	update(&state)
+	return state, saveServiceState(path, state)
+}
+
+// recordServiceStart는 서비스 관리자가 시작한 경우(asService) 시작 횟수를 늘려 저장하고 재시작 횟수(시작 횟수 - 1)를 반환합니다.
+// 콘솔 실행(debug)은 시작 횟수를 늘리지 않고 저장된 재시작 횟수만 반환합니다.
+func recordServiceStart(asService bool) (uint64, error) {
+	var state serviceState
+	var err error
+	if asService {
+		state, err = updateServiceState(serviceStatePath(), func(state *serviceState) {
+			state.StartCount++
+			state.LastStart = time.Now()
+		})
+	} else {
+		serviceStateMu.Lock()
+		state, err = loadServiceState(serviceStatePath())
+		serviceStateMu.Unlock()
+	}
+	if err != nil || state.StartCount == 0 {
+		return 0, err
+	}
+	return state.StartCount - 1, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/health"
+	"windows_service_module/pkg/winsvc"
+)
+
+// processStartedAt은 프로세스가 시작된 시각입니다 (가동 시간 계산용)
+var processStartedAt = time.Now()
+
+// serviceStatus는 /status 응답 본문입니다
+type serviceStatus struct {
+	ServiceName   string       `json:"service_name"`
+	PID           int          `json:"pid"`
+	StartedAt     time.Time    `json:"started_at"`
+	UptimeSeconds int64        `json:"uptime_seconds"`
+	Monitor       agent.Status `json:"monitor"`
+}
+
+// startStatusServer는 status_address가 설정되어 있으면 상태 서버와 /metrics를 시작합니다.
+// 시작하지 못해도 서비스는 계속 실행되며 nil을 반환합니다.
+func startStatusServer() *health.Server {
+	if config.StatusAddress == "" {
+		return nil
+	}
+
+	// 요청은 다른 고루틴에서 처리되므로 전역 변수 대신 시작 시점의 값을 사용
+	serviceName := config.ServiceName
+	a := agentInstance
+
+	server := health.NewServer(
+		func() interface{} {
+			return serviceStatus{
+				ServiceName:   serviceName,
+				PID:           os.Getpid(),
+				StartedAt:     processStartedAt,
+				UptimeSeconds: int64(time.Since(processStartedAt).Seconds()),
+				Monitor:       a.Status(),
+			}
+		},
+		agentReady(a),
+	)
+
+	if serviceMetrics != nil {
+		server.Handle("/metrics", serviceMetrics.Handler())
+	}
+
+	if err := server.Start(config.StatusAddress); err != nil {
+		logger.Log(winsvc.LogWarning, "상태 서버를 시작할 수 없습니다: %v", err)
+		return nil
+	}
+	logger.Log(winsvc.LogInfo, "상태 서버가 시작되었습니다: http://%s/status", server.Addr())
+	return server
+}
+
+// agentReady는 파이프라인이 실행 중이 아니면 상태와 마지막 오류를 반환하는 /readyz 확인 함수를 만듭니다
+func agentReady(a *agent.Agent) func() error {
+	return func() error {
+		status := a.Status()
+		if status.State != agent.StateRunning {
+			if status.LastError != "" {
+				return fmt.Errorf("모니터 상태 %s: %s", status.State, status.LastError)
+			}
+			return fmt.Errorf("모니터 상태 %s", status.State)
+		}
+		return nil
+	}
+}
+
+// stopStatusServer는 상태 서버를 중지합니다
+func stopStatusServer(server *health.Server) {
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Log(winsvc.LogWarning, "상태 서버 중지 실패: %v", err)
+	}
+}
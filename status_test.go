@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"windows_service_module/pkg/agent"
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/health"
+	"windows_service_module/pkg/winsvc"
+)
+
+// readyz는 /readyz 응답 코드와 본문을 반환합니다
+func readyz(s *health.Server) (int, string) {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestAgentReady(t *testing.T) {
+	fileFilter, err := filter.New(filter.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTestAgent := func(path string) *agent.Agent {
+		return agent.New(agent.Config{MonitoringPath: []string{path}, Filter: fileFilter},
+			agent.NewMonitorSource(), winsvc.NewLogger(t.TempDir(), false))
+	}
+
+	a := newTestAgent(t.TempDir())
+	s := health.NewServer(nil, agentReady(a))
+	if code, body := readyz(s); code != http.StatusServiceUnavailable || !strings.Contains(body, "모니터 상태 stopped") {
+		t.Errorf("시작 전 /readyz = %d %q, want 503", code, body)
+	}
+
+	if err := a.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+	if code, body := readyz(s); code != http.StatusOK {
+		t.Errorf("실행 중 /readyz = %d %q, want 200", code, body)
+	}
+
+	// 파이프라인이 멈추면 준비되지 않음
+	cancel()
+	<-done
+	if code, body := readyz(s); code != http.StatusServiceUnavailable || !strings.Contains(body, "모니터 상태 stopped") {
+		t.Errorf("중지 후 /readyz = %d %q, want 503", code, body)
+	}
+
+	// 시작에 실패하면 마지막 오류를 함께 보고
+	failed := newTestAgent(filepath.Join(t.TempDir(), "missing"))
+	if err := failed.Start(); err == nil {
+		t.Fatal("없는 경로로 Start하면 오류를 반환해야 합니다")
+	}
+	code, body := readyz(health.NewServer(nil, agentReady(failed)))
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "모니터 상태 failed: 모니터링 시작 실패") {
+		t.Errorf("시작 실패 /readyz = %d %q, want 503 failed", code, body)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"windows_service_module/pkg/filter"
+	"windows_service_module/pkg/health"
+	"windows_service_module/pkg/winsvc"
+)
+
+// FieldError는 설정 필드 하나의 검증 오류입니다
+type FieldError struct {
+	Field   string // JSON 필드 이름 (예: "monitoring_path[0]")
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError는 설정 검증에서 발견된 모든 오류를 담습니다
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("설정 검증 실패 (%d개 오류)", len(e.Errors)))
+	for _, fe := range e.Errors {
+		lines = append(lines, "  - "+fe.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add는 필드 오류를 추가합니다
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err는 오류가 있으면 자신을, 없으면 nil을 반환합니다
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate는 설정 값의 범위, 필수 값, 경로 존재 여부를 검사하고 모든 오류를 한 번에 반환합니다
+func (c *ServiceConfig) Validate() error {
+	v := &ValidationError{}
+	c.validate(v)
+	return v.err()
+}
+
+func (c *ServiceConfig) validate(v *ValidationError) {
+	// 서비스 이름
+	switch {
+	case strings.TrimSpace(c.ServiceName) == "":
+		v.add("service_name", "비어 있을 수 없습니다")
+	case len(c.ServiceName) > 256:
+		v.add("service_name", "256자를 넘을 수 없습니다")
+	case strings.ContainsAny(c.ServiceName, `/\ `):
+		v.add("service_name", "공백, '/', '\\'를 포함할 수 없습니다 (현재 값: %q)", c.ServiceName)
+	}
+
+	// 재시작 정책
+	if c.RestartDelay < 0 || c.RestartDelay > 3600 {
+		v.add("restart_delay", "0 이상 3600 이하여야 합니다 (현재 값: %d)", c.RestartDelay)
+	}
+	if c.MaxRestartAttempts < 0 || c.MaxRestartAttempts > 100 {
+		v.add("max_restart_attempts", "0 이상 100 이하여야 합니다 (현재 값: %d)", c.MaxRestartAttempts)
+	}
+
+	// 경로 설정
+	if strings.TrimSpace(c.LogPath) == "" {
+		v.add("log_path", "비어 있을 수 없습니다")
+	}
+	if _, err := winsvc.ParseLevel(c.LogLevel); err != nil {
+		v.add("log_level", "%v", err)
+	}
+	if _, err := winsvc.ParseFormat(c.LogFormat); err != nil {
+		v.add("log_format", "%v", err)
+	}
+	if c.LogRotation.MaxSizeMB < 0 || c.LogRotation.MaxSizeMB > 10240 {
+		v.add("log_rotation.max_size_mb", "0 이상 10240 이하여야 합니다 (현재 값: %d)", c.LogRotation.MaxSizeMB)
+	}
+	if c.LogRotation.IntervalHours < 0 || c.LogRotation.IntervalHours > 24*365 {
+		v.add("log_rotation.interval_hours", "0 이상 8760 이하여야 합니다 (현재 값: %d)", c.LogRotation.IntervalHours)
+	}
+	if c.LogRotation.MaxFiles < 0 || c.LogRotation.MaxFiles > 1000 {
+		v.add("log_rotation.max_files", "0 이상 1000 이하여야 합니다 (현재 값: %d)", c.LogRotation.MaxFiles)
+	}
+	if c.LogAsync.QueueSize < 0 || c.LogAsync.QueueSize > 1000000 {
+		v.add("log_async.queue_size", "0 이상 1000000 이하여야 합니다 (현재 값: %d)", c.LogAsync.QueueSize)
+	}
+	if _, err := winsvc.ParseDropPolicy(string(c.LogAsync.DropPolicy)); err != nil {
+		v.add("log_async.drop_policy", "%v", err)
+	}
+	if strings.TrimSpace(c.DatabasePath) == "" {
+		v.add("database_path", "비어 있을 수 없습니다")
+	}
+	if c.Retention.MaxAgeDays < 0 || c.Retention.MaxAgeDays > 36500 {
+		v.add("retention.max_age_days", "0 이상 36500 이하여야 합니다 (현재 값: %d)", c.Retention.MaxAgeDays)
+	}
+	if c.Retention.MaxRows < 0 {
+		v.add("retention.max_rows", "0 이상이어야 합니다 (현재 값: %d)", c.Retention.MaxRows)
+	}
+	if c.Retention.PruneIntervalMinutes < 1 || c.Retention.PruneIntervalMinutes > 7*24*60 {
+		v.add("retention.prune_interval_minutes", "1 이상 10080 이하여야 합니다 (현재 값: %d)", c.Retention.PruneIntervalMinutes)
+	}
+	if c.Retention.VacuumIntervalHours < 0 || c.Retention.VacuumIntervalHours > 24*365 {
+		v.add("retention.vacuum_interval_hours", "0 이상 8760 이하여야 합니다 (현재 값: %d)", c.Retention.VacuumIntervalHours)
+	}
+	// 해시 계산을 하지 않으면 작업자가 없어도 됨 (기준선은 0이면 기본 작업자 수 사용)
+	switch {
+	case c.Hashing.Enabled && (c.Hashing.Workers < 1 || c.Hashing.Workers > 64):
+		v.add("hashing.workers", "1 이상 64 이하여야 합니다 (현재 값: %d)", c.Hashing.Workers)
+	case !c.Hashing.Enabled && (c.Hashing.Workers < 0 || c.Hashing.Workers > 64):
+		v.add("hashing.workers", "0 이상 64 이하여야 합니다 (현재 값: %d)", c.Hashing.Workers)
+	}
+	if c.Hashing.MaxSizeMB < 0 || c.Hashing.MaxSizeMB > 102400 {
+		v.add("hashing.max_size_mb", "0 이상 102400 이하여야 합니다 (현재 값: %d)", c.Hashing.MaxSizeMB)
+	}
+	if c.Hashing.Retries < 0 || c.Hashing.Retries > 20 {
+		v.add("hashing.retries", "0 이상 20 이하여야 합니다 (현재 값: %d)", c.Hashing.Retries)
+	}
+	if c.Hashing.RetryDelayMs < 0 || c.Hashing.RetryDelayMs > 60000 {
+		v.add("hashing.retry_delay_ms", "0 이상 60000 이하여야 합니다 (현재 값: %d)", c.Hashing.RetryDelayMs)
+	}
+	// 해시 목록은 계산한 해시와 비교하므로 해시 계산이 필요
+	if !c.Hashing.Enabled {
+		if c.HashLists.Allowlist != "" {
+			v.add("hash_lists.allowlist", "hashing.enabled가 false이면 사용할 수 없습니다")
+		}
+		if c.HashLists.Blocklist != "" {
+			v.add("hash_lists.blocklist", "hashing.enabled가 false이면 사용할 수 없습니다")
+		}
+	}
+	// 규칙의 sink 동작과 메트릭은 싱크를 이름으로 구분하므로 이름이 겹치면 안 됨 (생성된 이름 포함)
+	sinkNames := make(map[string]int, len(c.Sinks))
+	for i, s := range c.Sinks {
+		for field, problem := range s.Validate() {
+			v.add(fmt.Sprintf("sinks[%d].%s", i, field), "%s", problem)
+		}
+		name := s.DisplayName(i)
+		if first, ok := sinkNames[name]; ok {
+			v.add(fmt.Sprintf("sinks[%d].name", i), "sinks[%d]와 이름이 같습니다: %q", first, name)
+			continue
+		}
+		sinkNames[name] = i
+	}
+	if c.EventCoalescing.WindowMs < 0 || c.EventCoalescing.WindowMs > 60000 {
+		v.add("event_coalescing.window_ms", "0 이상 60000 이하여야 합니다 (현재 값: %d)", c.EventCoalescing.WindowMs)
+	}
+	if c.EventCoalescing.MaxWaitMs < 0 || c.EventCoalescing.MaxWaitMs > 600000 {
+		v.add("event_coalescing.max_wait_ms", "0 이상 600000 이하여야 합니다 (현재 값: %d)", c.EventCoalescing.MaxWaitMs)
+	}
+	if c.Baseline.DiffIntervalHours < 0 || c.Baseline.DiffIntervalHours > 24*365 {
+		v.add("baseline.diff_interval_hours", "0 이상 8760 이하여야 합니다 (현재 값: %d)", c.Baseline.DiffIntervalHours)
+	}
+	if c.CatchUp.Workers < 0 || c.CatchUp.Workers > 64 {
+		v.add("catch_up.workers", "0 이상 64 이하여야 합니다 (현재 값: %d)", c.CatchUp.Workers)
+	}
+	if strings.TrimSpace(c.CustomDataPath) == "" {
+		v.add("custom_data_path", "비어 있을 수 없습니다")
+	}
+
+	// 모니터링 경로
+	if len(c.MonitoringPath) == 0 {
+		v.add("monitoring_path", "하나 이상의 경로가 필요합니다")
+	}
+	for i, path := range c.MonitoringPath {
+		if strings.TrimSpace(path) == "" {
+			v.add(fmt.Sprintf("monitoring_path[%d]", i), "비어 있을 수 없습니다")
+		}
+	}
+
+	// 상태 서버
+	if c.StatusAddress != "" {
+		if err := health.ValidateAddress(c.StatusAddress); err != nil {
+			v.add("status_address", "%v", err)
+		}
+	}
+
+	// 파일 필터
+	for i, ext := range c.FileFilters.Extensions {
+		if err := filter.ValidateExtension(ext); err != nil {
+			v.add(fmt.Sprintf("file_filters.extensions[%d]", i), "%v", err)
+		}
+	}
+	for i, pattern := range c.FileFilters.Patterns {
+		if err := filter.ValidatePattern(pattern); err != nil {
+			v.add(fmt.Sprintf("file_filters.patterns[%d]", i), "%v", err)
+		}
+	}
+	for i, pattern := range c.FileFilters.Exclude {
+		if err := filter.ValidateExclude(pattern); err != nil {
+			v.add(fmt.Sprintf("file_filters.exclude[%d]", i), "%v", err)
+		}
+	}
+}
+
+// ValidatePaths는 감시 경로가 있는 디렉토리인지 확인합니다.
+// 경로는 서비스를 실행할 컴퓨터에만 있으면 되므로 서비스를 실행하거나(run, debug, 설정 다시 로드)
+// validate-config로 검증할 때만 확인하고, install, status 같은 관리 명령에서는 확인하지 않습니다.
+func (c *ServiceConfig) ValidatePaths() error {
+	v := &ValidationError{}
+	c.validatePaths(v)
+	return v.err()
+}
+
+func (c *ServiceConfig) validatePaths(v *ValidationError) {
+	for i, path := range c.MonitoringPath {
+		if strings.TrimSpace(path) == "" {
+			continue // validate에서 보고
+		}
+		field := fmt.Sprintf("monitoring_path[%d]", i)
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			v.add(field, "경로에 접근할 수 없습니다: %v", err)
+		case !info.IsDir():
+			v.add(field, "디렉토리가 아닙니다: %s", path)
+		}
+	}
+}
+
+// unknownFields는 JSON 데이터에서 구조체에 정의되지 않은 키를 찾습니다
+func unknownFields(data json.RawMessage, t reflect.Type, prefix string, v *ValidationError) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+
+		for key, value := range obj {
+			field := key
+			if prefix != "" {
+				field = prefix + "." + key
+			}
+			ft, ok := fields[key]
+			if !ok {
+				v.add(field, "알 수 없는 설정 항목입니다")
+				continue
+			}
+			unknownFields(value, ft, field, v)
+		}
+
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i, item := range items {
+			unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i), v)
+		}
+	}
+}
+
+// runValidateConfig는 서비스 관리자에 접근하지 않고 설정 파일만 검증합니다
+func runValidateConfig(args []string) int {
+	path := defaultConfigPath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "설정 파일을 읽을 수 없습니다: %v\n", err)
+		return 1
+	}
+
+	if _, err := loadConfig(path, true); err != nil {
+		fmt.Fprintf(os.Stderr, "설정 파일이 유효하지 않습니다: %s\n%v\n", path, err)
+		return 1
+	}
+
+	fmt.Printf("설정 파일이 유효합니다: %s\n", path)
+
+	if rulesPath, err := validateRulesFile(path); err != nil {
+		fmt.Fprintf(os.Stderr, "규칙 파일이 유효하지 않습니다: %s\n%v\n", rulesPath, err)
+		return 1
+	} else if rulesPath != "" {
+		fmt.Printf("규칙 파일이 유효합니다: %s\n", rulesPath)
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"windows_service_module/pkg/sink"
+)
+
+// writeConfig는 임시 디렉토리에 설정 파일을 만들고 경로를 반환합니다
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), configFileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultConfigValid(t *testing.T) {
+	cfg := newDefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("기본 설정이 유효하지 않습니다: %v", err)
+	}
+}
+
+func TestLoadConfigPathCheck(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	path := writeConfig(t, `{"monitoring_path": [`+quoteJSON(missing)+`]}`)
+
+	// 관리 명령은 감시 경로를 확인하지 않음
+	if _, err := LoadConfig(path); err != nil {
+		t.Errorf("LoadConfig: %v", err)
+	}
+	_, err := loadConfig(path, true)
+	if err == nil || !strings.Contains(err.Error(), "monitoring_path[0]") {
+		t.Errorf("loadConfig(checkPaths) 오류 = %v, want monitoring_path[0]", err)
+	}
+}
+
+func TestValidateHashingWorkers(t *testing.T) {
+	tests := []struct {
+		enabled bool
+		workers int
+		valid   bool
+	}{
+		{true, 0, false},
+		{true, 4, true},
+		{true, 65, false},
+		{false, 0, true},
+		{false, -1, false},
+	}
+	for _, tt := range tests {
+		cfg := newDefaultConfig()
+		cfg.Hashing.Enabled = tt.enabled
+		cfg.Hashing.Workers = tt.workers
+		if err := cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("enabled=%v workers=%d: Validate = %v, want valid=%v", tt.enabled, tt.workers, err, tt.valid)
+		}
+	}
+}
+
+func TestConfigFieldsScalarOnly(t *testing.T) {
+	paths := map[string]bool{}
+	for _, f := range configFields() {
+		paths[f.Path] = true
+	}
+	for _, want := range []string{"log_path", "monitoring_path", "hashing.workers", "file_filters.extensions"} {
+		if !paths[want] {
+			t.Errorf("%s 필드가 없습니다", want)
+		}
+	}
+	if paths["sinks"] {
+		t.Error("구조체 목록 sinks는 덮어쓸 수 있는 필드가 아니어야 합니다")
+	}
+}
+
+func TestLoadConfigLayersEnv(t *testing.T) {
+	path := writeConfig(t, `{"log_level": "warning", "sinks": [{"name": "f", "type": "file", "path": "events.jsonl"}]}`)
+	t.Setenv("HJSVC_LOG_LEVEL", "debug")
+	t.Setenv("HJSVC_SINKS", "ignored")
+
+	cfg, sources, err := loadConfigLayers(path, false)
+	if err != nil {
+		t.Fatalf("loadConfigLayers: %v", err)
+	}
+	if cfg.LogLevel != "debug" || sources["log_level"] != SourceEnv {
+		t.Errorf("log_level = %q (%s), want debug (env)", cfg.LogLevel, sources["log_level"])
+	}
+	if len(cfg.Sinks) != 1 || cfg.Sinks[0].Name != "f" {
+		t.Errorf("sinks = %+v, want 설정 파일의 싱크 하나", cfg.Sinks)
+	}
+}
+
+// quoteJSON은 문자열을 JSON 문자열로 만듭니다 (Windows 경로의 역슬래시)
+func quoteJSON(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func TestValidateHashListsNeedHashing(t *testing.T) {
+	cfg := newDefaultConfig()
+	cfg.HashLists.Allowlist = "allow.txt"
+	cfg.HashLists.Blocklist = "block.txt"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("hashing.enabled이면 목록을 사용할 수 있어야 합니다: %v", err)
+	}
+
+	cfg.Hashing.Enabled = false
+	err := cfg.Validate()
+	for _, field := range []string{"hash_lists.allowlist", "hash_lists.blocklist"} {
+		if err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate 오류 = %v, want %s", err, field)
+		}
+	}
+}
+
+func TestValidateSinkNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		sinks []sink.Config
+		want  string // 빈 문자열이면 유효
+	}{
+		{"서로 다름", []sink.Config{
+			{Type: sink.TypeFile, Name: "a", Path: "a.jsonl"},
+			{Type: sink.TypeFile, Name: "b", Path: "b.jsonl"},
+		}, ""},
+		{"이름 중복", []sink.Config{
+			{Type: sink.TypeFile, Name: "soc", Path: "a.jsonl"},
+			{Type: sink.TypeWebhook, Name: "soc", URL: "http://localhost/hook"},
+		}, "sinks[1].name"},
+		{"생성된 이름과 중복", []sink.Config{
+			{Type: sink.TypeFile, Path: "a.jsonl"},
+			{Type: sink.TypeFile, Name: "file-1", Path: "b.jsonl"},
+		}, "sinks[1].name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newDefaultConfig()
+			cfg.Sinks = tt.sinks
+			err := cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate 오류 = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}